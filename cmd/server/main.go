@@ -1,18 +1,115 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"strings"
+	"student-api/internal/config"
 	"student-api/internal/handlers"
+	"student-api/internal/middleware"
 	"student-api/internal/services"
 )
 
 func main() {
-	ollamaService := services.NewOllamaService()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if cfg.AuditLogPath != "" {
+		if err := services.OpenAuditLog(cfg.AuditLogPath); err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer services.CloseAuditLog()
+	}
+
+	services.SetEventBufferSize(cfg.EventBufferSize)
+	services.StartStudentPurger(context.Background(), cfg.PurgeInterval, cfg.DeletedRetention)
+
+	services.ConfigureWebhooks(services.WebhookSettings{
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		InitialBackoff: cfg.WebhookInitialBackoff,
+		Timeout:        cfg.WebhookTimeout,
+	})
+	services.StartWebhookDispatcher(context.Background())
+
+	promptLibrary, err := services.LoadPromptLibrary(cfg.PromptsDir)
+	if err != nil {
+		log.Fatalf("Invalid prompt templates: %v", err)
+	}
+	services.SetPromptLibrary(promptLibrary)
+	if err := services.ConfigurePromptSafeguards(services.PromptSafeguards{
+		RedactFields: cfg.PromptRedactFields,
+		BlockedTerms: cfg.SummaryBlockedTerms,
+	}); err != nil {
+		log.Fatalf("Invalid prompt safeguards: %v", err)
+	}
+
+	gradeScale, err := services.ParseGradeScale(cfg.GradeScale)
+	if err != nil {
+		log.Fatalf("Invalid grade scale: %v", err)
+	}
+	services.ConfigureGradeScale(gradeScale)
+	services.ConfigureAttendanceThreshold(cfg.AttendanceThreshold)
+
+	attachmentStore, err := services.NewLocalBlobStore(cfg.AttachmentsDir)
+	if err != nil {
+		log.Fatalf("Failed to open attachment store: %v", err)
+	}
+	services.ConfigureAttachments(services.AttachmentSettings{
+		Store:        attachmentStore,
+		MaxSize:      int64(cfg.AttachmentMaxSize),
+		AllowedTypes: cfg.AttachmentTypes,
+	})
+
+	httpSettings := services.HTTPSettings{
+		BaseURL:             cfg.OllamaURL,
+		Timeout:             cfg.OllamaTimeout,
+		MaxRetries:          cfg.OllamaMaxRetries,
+		RetryBaseDelay:      cfg.OllamaRetryBaseDelay,
+		BreakerThreshold:    cfg.OllamaBreakerThreshold,
+		BreakerResetTimeout: cfg.OllamaBreakerResetTimeout,
+	}
+	summaryProvider, err := services.NewSummaryProvider(services.ProviderSettings{
+		Provider:      cfg.SummaryProvider,
+		HTTP:          httpSettings,
+		OllamaModel:   cfg.OllamaModel,
+		OpenAIURL:     cfg.OpenAIURL,
+		OpenAIAPIKey:  cfg.OpenAIAPIKey,
+		OpenAIModel:   cfg.OpenAIModel,
+		ExtraModels:   cfg.SummaryModels,
+		SystemPrompt:  cfg.SummarySystemPrompt,
+		KeepAlive:     cfg.OllamaKeepAlive,
+		OutputRetries: cfg.SummaryOutputRetries,
+	})
+	if err != nil {
+		log.Fatalf("Invalid summary provider: %v", err)
+	}
+
+	// The admin client has its own circuit breaker so model management does
+	// not trip summaries, and vice versa.
+	ollamaClient := services.NewLLMClient(httpSettings)
+	if cfg.SummaryProvider == services.ProviderOllama && cfg.OllamaModelCheck != "off" {
+		checkOllamaModels(ollamaClient, summaryProvider.Models(), cfg.OllamaModelCheck == "fail")
+	}
+
+	summaryCache := services.NewSummaryCache(cfg.SummaryCacheSize, cfg.SummaryCacheTTL)
+	summaryCache.InvalidateOnChanges(context.Background())
+	if cfg.SummaryJobsPath != "" {
+		if err := services.OpenSummaryJobStore(cfg.SummaryJobsPath); err != nil {
+			log.Fatalf("Failed to open summary job store: %v", err)
+		}
+	}
+	services.StartSummaryJobWorkers(context.Background(), summaryProvider, cfg.SummaryJobWorkers)
+
 	ollamaHandler := &handlers.OllamaHandler{
-		OllamaService: ollamaService,
+		OllamaService: summaryProvider,
+		SummaryCache:  summaryCache,
 	}
+	modelHandler := &handlers.ModelHandler{Client: ollamaClient, AdminToken: cfg.TenantAdminToken}
+	tenantHandler := &handlers.TenantHandler{AdminToken: cfg.TenantAdminToken}
+	customFieldHandler := &handlers.CustomFieldHandler{AdminToken: cfg.TenantAdminToken}
 
 	http.HandleFunc("/students", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -26,6 +123,42 @@ func main() {
 	})
 
 	http.HandleFunc("/students/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/students/events" {
+			if r.Method == "GET" {
+				handlers.StreamStudentEvents(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if r.URL.Path == "/students/stats" {
+			if r.Method == "GET" {
+				handlers.GetStudentStats(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if r.URL.Path == "/students/ask" {
+			if r.Method == "POST" {
+				ollamaHandler.AskStudents(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/summary/stream") {
+			if r.Method == "GET" {
+				ollamaHandler.StreamSummary(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
 		if strings.HasSuffix(r.URL.Path, "/summary") {
 			if r.Method == "GET" {
 				ollamaHandler.GenerateSummary(w, r)
@@ -35,6 +168,150 @@ func main() {
 			return
 		}
 
+		if strings.HasSuffix(r.URL.Path, "/summaries") {
+			switch r.Method {
+			case "GET":
+				handlers.GetStudentSummaries(w, r)
+			case "POST":
+				ollamaHandler.RegenerateSummary(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/pin") {
+			if r.Method == "POST" {
+				handlers.PinStudentSummary(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/restore") {
+			if r.Method == "POST" {
+				handlers.RestoreStudent(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/transitions") {
+			switch r.Method {
+			case "GET":
+				handlers.GetStudentTransitions(w, r)
+			case "POST":
+				handlers.TransitionStudent(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/history") {
+			if r.Method == "GET" {
+				handlers.GetStudentHistory(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/grades") {
+			switch r.Method {
+			case "GET":
+				handlers.GetStudentGrades(w, r)
+			case "POST":
+				handlers.RecordGrade(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.Contains(r.URL.Path, "/grades/") {
+			if r.Method == "PUT" {
+				handlers.UpdateGrade(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/attendance") {
+			if r.Method == "GET" {
+				handlers.GetStudentAttendance(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/transcript") {
+			if r.Method == "GET" {
+				handlers.GetTranscript(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/attachments") {
+			switch r.Method {
+			case "GET":
+				handlers.GetStudentAttachments(w, r)
+			case "POST":
+				handlers.UploadAttachment(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.Contains(r.URL.Path, "/attachments/") {
+			if strings.HasSuffix(r.URL.Path, "/content") {
+				if r.Method == "GET" || r.Method == "HEAD" {
+					handlers.DownloadAttachment(w, r)
+				} else {
+					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				}
+				return
+			}
+
+			switch r.Method {
+			case "GET":
+				handlers.GetAttachment(w, r)
+			case "DELETE":
+				handlers.DeleteAttachment(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/enrollments") {
+			switch r.Method {
+			case "GET":
+				handlers.GetStudentEnrollments(w, r)
+			case "POST":
+				handlers.CreateEnrollment(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.Contains(r.URL.Path, "/enrollments/") {
+			if r.Method == "PATCH" {
+				handlers.UpdateEnrollment(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
 		switch r.Method {
 		case "GET":
 			handlers.GetStudentByID(w, r)
@@ -47,6 +324,278 @@ func main() {
 		}
 	})
 
-	log.Println("Server starting on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	http.HandleFunc("/courses", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handlers.GetAllCourses(w, r)
+		case "POST":
+			handlers.CreateCourse(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/courses/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/attendance") {
+			switch r.Method {
+			case "GET":
+				handlers.GetCourseAttendance(w, r)
+			case "POST":
+				handlers.RecordCourseAttendance(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/students") {
+			if r.Method == "GET" {
+				handlers.GetCourseStudents(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			handlers.GetCourseByID(w, r)
+		case "PUT":
+			handlers.UpdateCourse(w, r)
+		case "DELETE":
+			handlers.DeleteCourse(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/attendance/report", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			handlers.GetAttendanceReport(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/attendance/below-threshold", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			handlers.GetLowAttendanceReport(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/reports/cohort", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handlers.GetCohortReports(w, r)
+		case "POST":
+			ollamaHandler.CreateCohortReport(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/reports/cohort/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/download") {
+			if r.Method == "GET" {
+				handlers.DownloadCohortReport(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			handlers.GetCohortReport(w, r)
+		case "DELETE":
+			handlers.DeleteCohortReport(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			handlers.GetAuditEvents(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handlers.GetAllWebhooks(w, r)
+		case "POST":
+			handlers.CreateWebhook(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/webhooks/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhooks/dead-letters" {
+			if r.Method == "GET" {
+				handlers.GetWebhookDeadLetters(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/deliveries") {
+			if r.Method == "GET" {
+				handlers.GetWebhookDeliveries(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			handlers.GetWebhookByID(w, r)
+		case "DELETE":
+			handlers.DeleteWebhook(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/summaries/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handlers.GetAllSummaryJobs(w, r)
+		case "POST":
+			handlers.CreateSummaryJob(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/summaries/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			if r.Method == "POST" {
+				handlers.CancelSummaryJob(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if r.Method == "GET" {
+			handlers.GetSummaryJobByID(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/custom-fields", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handlers.GetCustomFields(w, r)
+		case "POST":
+			customFieldHandler.CreateCustomField(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/custom-fields/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			customFieldHandler.DeleteCustomField(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			modelHandler.ListModels(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/models/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/models/pull" {
+			if r.Method == "POST" {
+				modelHandler.PullModel(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		if r.Method == "GET" {
+			modelHandler.ShowModel(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/tenants", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			tenantHandler.ListTenants(w, r)
+		case "POST":
+			tenantHandler.CreateTenant(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/admin/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/tokens") {
+			switch r.Method {
+			case "POST":
+				tenantHandler.IssueTenantToken(w, r)
+			case "DELETE":
+				tenantHandler.RevokeTenantTokens(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			tenantHandler.GetTenant(w, r)
+		case "DELETE":
+			tenantHandler.DeleteTenant(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	tenantOptions := middleware.TenantOptions{
+		Header:      cfg.TenantHeader,
+		TrustHeader: cfg.TrustTenantHeader,
+		Required:    cfg.RequireTenant,
+		AdminToken:  cfg.TenantAdminToken,
+	}
+	handler := middleware.RequestContextMiddleware(middleware.TenantMiddleware(tenantOptions, http.DefaultServeMux))
+
+	log.Printf("Server starting on %s", cfg.Addr)
+	log.Fatal(http.ListenAndServe(cfg.Addr, handler))
+}
+
+// checkOllamaModels verifies that the summary models are available at Ollama,
+// logging a warning or exiting when they are missing or Ollama is unreachable.
+func checkOllamaModels(client *services.LLMClient, models []string, fail bool) {
+	report := log.Printf
+	if fail {
+		report = log.Fatalf
+	}
+
+	missing, err := services.MissingOllamaModels(context.Background(), client, models)
+	if err != nil {
+		report("Could not verify Ollama models: %v", err)
+		return
+	}
+	if len(missing) > 0 {
+		report("Models not available at Ollama: %s (pull them with POST /admin/models/pull)", strings.Join(missing, ", "))
+	}
 }
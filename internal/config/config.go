@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Addr             string
+	AuditLogPath     string
+	DeletedRetention time.Duration
+	PurgeInterval    time.Duration
+	EventBufferSize  int
+
+	WebhookMaxAttempts    int
+	WebhookInitialBackoff time.Duration
+	WebhookTimeout        time.Duration
+
+	SummaryProvider      string
+	SummaryModels        []string
+	SummarySystemPrompt  string
+	SummaryOutputRetries int
+	OpenAIURL            string
+	OpenAIAPIKey         string
+	OpenAIModel          string
+	PromptsDir           string
+	PromptRedactFields   []string
+	SummaryBlockedTerms  []string
+
+	OllamaURL                 string
+	OllamaModel               string
+	OllamaKeepAlive           string
+	OllamaModelCheck          string
+	OllamaTimeout             time.Duration
+	OllamaMaxRetries          int
+	OllamaRetryBaseDelay      time.Duration
+	OllamaBreakerThreshold    int
+	OllamaBreakerResetTimeout time.Duration
+
+	SummaryCacheSize int
+	SummaryCacheTTL  time.Duration
+
+	SummaryJobWorkers int
+	SummaryJobsPath   string
+
+	GradeScale          []string
+	AttendanceThreshold float64
+
+	AttachmentsDir    string
+	AttachmentMaxSize int
+	AttachmentTypes   []string
+
+	TenantHeader      string
+	TrustTenantHeader bool
+	RequireTenant     bool
+	TenantAdminToken  string
+}
+
+func Load() (Config, error) {
+	cfg := Config{
+		Addr:         getEnv("ADDR", ":8080"),
+		AuditLogPath: getEnv("AUDIT_LOG_PATH", ""),
+		OllamaURL:    getEnv("OLLAMA_URL", "http://localhost:11434"),
+
+		OllamaModel:      getEnv("OLLAMA_MODEL", "llama3"),
+		OllamaKeepAlive:  getEnv("OLLAMA_KEEP_ALIVE", ""),
+		OllamaModelCheck: getEnv("OLLAMA_MODEL_CHECK", "warn"),
+
+		SummaryProvider:     getEnv("SUMMARY_PROVIDER", "ollama"),
+		SummaryModels:       getListEnv("SUMMARY_MODELS", ""),
+		SummarySystemPrompt: getEnv("SUMMARY_SYSTEM_PROMPT", ""),
+		OpenAIURL:           getEnv("OPENAI_URL", "http://localhost:8000"),
+		OpenAIAPIKey:        getEnv("OPENAI_API_KEY", ""),
+		OpenAIModel:         getEnv("OPENAI_MODEL", "llama3"),
+		PromptsDir:          getEnv("PROMPTS_DIR", ""),
+		PromptRedactFields:  getListEnv("PROMPT_REDACT_FIELDS", "email"),
+		SummaryBlockedTerms: getListEnv("SUMMARY_BLOCKED_TERMS", ""),
+
+		SummaryJobsPath: getEnv("SUMMARY_JOBS_PATH", ""),
+
+		GradeScale: getListEnv("GRADE_SCALE", ""),
+
+		AttachmentsDir:  getEnv("ATTACHMENTS_DIR", "data/attachments"),
+		AttachmentTypes: getListEnv("ATTACHMENT_TYPES", "image/jpeg,image/png,application/pdf"),
+
+		TenantHeader:     getEnv("TENANT_HEADER", "X-Tenant-ID"),
+		TenantAdminToken: getEnv("TENANT_ADMIN_TOKEN", ""),
+	}
+	if cfg.TenantHeader == "none" {
+		cfg.TenantHeader = ""
+	}
+
+	switch cfg.SummaryProvider {
+	case "ollama", "openai", "template":
+	default:
+		return Config{}, fmt.Errorf("SUMMARY_PROVIDER must be one of ollama, openai or template, got %q", cfg.SummaryProvider)
+	}
+
+	switch cfg.OllamaModelCheck {
+	case "warn", "fail", "off":
+	default:
+		return Config{}, fmt.Errorf("OLLAMA_MODEL_CHECK must be one of warn, fail or off, got %q", cfg.OllamaModelCheck)
+	}
+
+	var err error
+	if cfg.DeletedRetention, err = getDurationEnv("DELETED_RETENTION", 30*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.PurgeInterval, err = getDurationEnv("PURGE_INTERVAL", time.Hour); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.EventBufferSize, err = getIntEnv("EVENT_BUFFER_SIZE", 100, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.WebhookMaxAttempts, err = getIntEnv("WEBHOOK_MAX_ATTEMPTS", 5, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.WebhookInitialBackoff, err = getDurationEnv("WEBHOOK_INITIAL_BACKOFF", time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.WebhookTimeout, err = getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.OllamaTimeout, err = getDurationEnv("OLLAMA_TIMEOUT", 60*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.OllamaMaxRetries, err = getIntEnv("OLLAMA_MAX_RETRIES", 2, 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.OllamaRetryBaseDelay, err = getDurationEnv("OLLAMA_RETRY_BASE_DELAY", 200*time.Millisecond); err != nil {
+		return Config{}, err
+	}
+	if cfg.OllamaBreakerThreshold, err = getIntEnv("OLLAMA_BREAKER_THRESHOLD", 5, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.OllamaBreakerResetTimeout, err = getDurationEnv("OLLAMA_BREAKER_RESET_TIMEOUT", 30*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.SummaryOutputRetries, err = getIntEnv("SUMMARY_OUTPUT_RETRIES", 2, 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.SummaryCacheSize, err = getIntEnv("SUMMARY_CACHE_SIZE", 1000, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.SummaryCacheTTL, err = getDurationEnv("SUMMARY_CACHE_TTL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.SummaryJobWorkers, err = getIntEnv("SUMMARY_JOB_WORKERS", 4, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.AttendanceThreshold, err = getFloatEnv("ATTENDANCE_THRESHOLD", 0.8, 0, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.AttachmentMaxSize, err = getIntEnv("ATTACHMENT_MAX_SIZE", 10<<20, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.RequireTenant, err = getBoolEnv("REQUIRE_TENANT", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.TrustTenantHeader, err = getBoolEnv("TRUST_TENANT_HEADER", false); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getListEnv splits a comma-separated variable, dropping empty entries. The
+// value "none" yields an empty list, so a non-empty fallback can be disabled.
+func getListEnv(key, fallback string) []string {
+	raw := getEnv(key, fallback)
+	if raw == "none" {
+		return nil
+	}
+
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getDurationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration, got %q", key, value)
+	}
+	return duration, nil
+}
+
+func getIntEnv(key string, fallback, min int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min {
+		return 0, fmt.Errorf("%s must be an integer of at least %d, got %q", key, min, value)
+	}
+	return number, nil
+}
+
+func getBoolEnv(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	return enabled, nil
+}
+
+func getFloatEnv(key string, fallback, min, max float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < min || number > max {
+		return 0, fmt.Errorf("%s must be a number between %g and %g, got %q", key, min, max, value)
+	}
+	return number, nil
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("ADDR", "")
+	t.Setenv("AUDIT_LOG_PATH", "")
+	t.Setenv("DELETED_RETENTION", "")
+	t.Setenv("PURGE_INTERVAL", "")
+	t.Setenv("EVENT_BUFFER_SIZE", "")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "")
+	t.Setenv("WEBHOOK_INITIAL_BACKOFF", "")
+	t.Setenv("WEBHOOK_TIMEOUT", "")
+	t.Setenv("OLLAMA_URL", "")
+	t.Setenv("OLLAMA_TIMEOUT", "")
+	t.Setenv("OLLAMA_MAX_RETRIES", "")
+	t.Setenv("SUMMARY_CACHE_SIZE", "")
+	t.Setenv("SUMMARY_CACHE_TTL", "")
+	t.Setenv("SUMMARY_JOB_WORKERS", "")
+	t.Setenv("SUMMARY_JOBS_PATH", "")
+	t.Setenv("SUMMARY_PROVIDER", "")
+	t.Setenv("OPENAI_URL", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("OPENAI_MODEL", "")
+	t.Setenv("PROMPTS_DIR", "")
+	t.Setenv("OLLAMA_MODEL", "")
+	t.Setenv("OLLAMA_KEEP_ALIVE", "")
+	t.Setenv("OLLAMA_MODEL_CHECK", "")
+	t.Setenv("GRADE_SCALE", "")
+	t.Setenv("ATTENDANCE_THRESHOLD", "")
+	t.Setenv("ATTACHMENTS_DIR", "")
+	t.Setenv("ATTACHMENT_MAX_SIZE", "")
+	t.Setenv("ATTACHMENT_TYPES", "")
+	t.Setenv("TENANT_HEADER", "")
+	t.Setenv("REQUIRE_TENANT", "")
+	t.Setenv("TENANT_ADMIN_TOKEN", "")
+	t.Setenv("SUMMARY_MODELS", "")
+	t.Setenv("SUMMARY_SYSTEM_PROMPT", "")
+	t.Setenv("SUMMARY_OUTPUT_RETRIES", "")
+	t.Setenv("PROMPT_REDACT_FIELDS", "")
+	t.Setenv("SUMMARY_BLOCKED_TERMS", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Addr != ":8080" {
+		t.Errorf("Expected default addr :8080, got %s", cfg.Addr)
+	}
+	if cfg.AuditLogPath != "" {
+		t.Errorf("Expected no audit log path, got %s", cfg.AuditLogPath)
+	}
+	if cfg.DeletedRetention != 30*24*time.Hour {
+		t.Errorf("Expected default retention of 30 days, got %s", cfg.DeletedRetention)
+	}
+	if cfg.PurgeInterval != time.Hour {
+		t.Errorf("Expected default purge interval of 1h, got %s", cfg.PurgeInterval)
+	}
+	if cfg.EventBufferSize != 100 {
+		t.Errorf("Expected default event buffer size 100, got %d", cfg.EventBufferSize)
+	}
+	if cfg.WebhookMaxAttempts != 5 || cfg.WebhookInitialBackoff != time.Second || cfg.WebhookTimeout != 10*time.Second {
+		t.Errorf("Unexpected webhook defaults: %d attempts, %s backoff, %s timeout", cfg.WebhookMaxAttempts, cfg.WebhookInitialBackoff, cfg.WebhookTimeout)
+	}
+	if cfg.OllamaURL != "http://localhost:11434" || cfg.OllamaTimeout != 60*time.Second || cfg.OllamaMaxRetries != 2 {
+		t.Errorf("Unexpected Ollama defaults: %s, %s timeout, %d retries", cfg.OllamaURL, cfg.OllamaTimeout, cfg.OllamaMaxRetries)
+	}
+	if cfg.SummaryCacheSize != 1000 || cfg.SummaryCacheTTL != time.Hour {
+		t.Errorf("Unexpected summary cache defaults: %d entries, %s TTL", cfg.SummaryCacheSize, cfg.SummaryCacheTTL)
+	}
+	if cfg.SummaryJobWorkers != 4 || cfg.SummaryJobsPath != "" {
+		t.Errorf("Unexpected summary job defaults: %d workers, path %q", cfg.SummaryJobWorkers, cfg.SummaryJobsPath)
+	}
+	if cfg.SummaryProvider != "ollama" || cfg.OpenAIURL != "http://localhost:8000" || cfg.OpenAIAPIKey != "" || cfg.OpenAIModel != "llama3" {
+		t.Errorf("Unexpected provider defaults: %s, %s, key %q, model %s", cfg.SummaryProvider, cfg.OpenAIURL, cfg.OpenAIAPIKey, cfg.OpenAIModel)
+	}
+	if cfg.PromptsDir != "" {
+		t.Errorf("Expected no prompts directory, got %s", cfg.PromptsDir)
+	}
+	if cfg.OllamaModel != "llama3" || cfg.OllamaKeepAlive != "" || len(cfg.SummaryModels) != 0 || cfg.SummarySystemPrompt != "" {
+		t.Errorf("Unexpected model defaults: %s, keep alive %q, models %v, system %q", cfg.OllamaModel, cfg.OllamaKeepAlive, cfg.SummaryModels, cfg.SummarySystemPrompt)
+	}
+	if cfg.SummaryOutputRetries != 2 {
+		t.Errorf("Expected 2 structured output retries, got %d", cfg.SummaryOutputRetries)
+	}
+	if cfg.OllamaModelCheck != "warn" {
+		t.Errorf("Expected model check to warn, got %s", cfg.OllamaModelCheck)
+	}
+	if len(cfg.GradeScale) != 0 {
+		t.Errorf("Expected the default grade scale, got %v", cfg.GradeScale)
+	}
+	if cfg.AttendanceThreshold != 0.8 {
+		t.Errorf("Expected attendance threshold 0.8, got %v", cfg.AttendanceThreshold)
+	}
+	if cfg.AttachmentsDir != "data/attachments" || cfg.AttachmentMaxSize != 10<<20 || len(cfg.AttachmentTypes) != 3 {
+		t.Errorf("Unexpected attachment defaults: %s, %d bytes, types %v", cfg.AttachmentsDir, cfg.AttachmentMaxSize, cfg.AttachmentTypes)
+	}
+	if len(cfg.PromptRedactFields) != 1 || cfg.PromptRedactFields[0] != "email" || len(cfg.SummaryBlockedTerms) != 0 {
+		t.Errorf("Unexpected safeguard defaults: redact %v, blocked %v", cfg.PromptRedactFields, cfg.SummaryBlockedTerms)
+	}
+	if cfg.TenantHeader != "X-Tenant-ID" || cfg.TrustTenantHeader || cfg.RequireTenant || cfg.TenantAdminToken != "" {
+		t.Errorf("Unexpected tenant defaults: header %q, required %v", cfg.TenantHeader, cfg.RequireTenant)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("ADDR", ":9090")
+	t.Setenv("AUDIT_LOG_PATH", "/tmp/audit.log")
+	t.Setenv("DELETED_RETENTION", "48h")
+	t.Setenv("PURGE_INTERVAL", "10m")
+	t.Setenv("EVENT_BUFFER_SIZE", "500")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Addr != ":9090" {
+		t.Errorf("Expected addr :9090, got %s", cfg.Addr)
+	}
+	if cfg.AuditLogPath != "/tmp/audit.log" {
+		t.Errorf("Expected audit log path /tmp/audit.log, got %s", cfg.AuditLogPath)
+	}
+	if cfg.DeletedRetention != 48*time.Hour {
+		t.Errorf("Expected retention 48h, got %s", cfg.DeletedRetention)
+	}
+	if cfg.PurgeInterval != 10*time.Minute {
+		t.Errorf("Expected purge interval 10m, got %s", cfg.PurgeInterval)
+	}
+	if cfg.EventBufferSize != 500 {
+		t.Errorf("Expected event buffer size 500, got %d", cfg.EventBufferSize)
+	}
+}
+
+func TestLoadInvalidDuration(t *testing.T) {
+	t.Setenv("DELETED_RETENTION", "forever")
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
+
+func TestLoadInvalidInteger(t *testing.T) {
+	t.Setenv("EVENT_BUFFER_SIZE", "-1")
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for invalid integer")
+	}
+}
+
+func TestLoadZeroRetries(t *testing.T) {
+	t.Setenv("OLLAMA_MAX_RETRIES", "0")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.OllamaMaxRetries != 0 {
+		t.Errorf("Expected retries to be disabled, got %d", cfg.OllamaMaxRetries)
+	}
+}
+
+func TestLoadSummaryProvider(t *testing.T) {
+	t.Setenv("SUMMARY_PROVIDER", "openai")
+	t.Setenv("OPENAI_MODEL", "qwen2")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.SummaryProvider != "openai" || cfg.OpenAIModel != "qwen2" {
+		t.Errorf("Expected openai provider with model qwen2, got %s with %s", cfg.SummaryProvider, cfg.OpenAIModel)
+	}
+
+	t.Setenv("SUMMARY_PROVIDER", "gpt")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unknown summary provider")
+	}
+}
+
+func TestLoadSummaryModels(t *testing.T) {
+	t.Setenv("SUMMARY_MODELS", " mistral, ,qwen2 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cfg.SummaryModels) != 2 || cfg.SummaryModels[0] != "mistral" || cfg.SummaryModels[1] != "qwen2" {
+		t.Errorf("Expected [mistral qwen2], got %v", cfg.SummaryModels)
+	}
+}
+
+func TestLoadDisableRedaction(t *testing.T) {
+	t.Setenv("PROMPT_REDACT_FIELDS", "none")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cfg.PromptRedactFields) != 0 {
+		t.Errorf("Expected redaction to be disabled, got %v", cfg.PromptRedactFields)
+	}
+}
+
+func TestLoadOllamaModelCheck(t *testing.T) {
+	t.Setenv("OLLAMA_MODEL_CHECK", "fail")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.OllamaModelCheck != "fail" {
+		t.Errorf("Expected model check to fail, got %s", cfg.OllamaModelCheck)
+	}
+
+	t.Setenv("OLLAMA_MODEL_CHECK", "strict")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unknown model check mode")
+	}
+}
+
+func TestLoadAttendanceThreshold(t *testing.T) {
+	t.Setenv("ATTENDANCE_THRESHOLD", "0.9")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.AttendanceThreshold != 0.9 {
+		t.Errorf("Expected attendance threshold 0.9, got %v", cfg.AttendanceThreshold)
+	}
+
+	t.Setenv("ATTENDANCE_THRESHOLD", "90")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a threshold above 1")
+	}
+}
+
+func TestLoadTenantSettings(t *testing.T) {
+	t.Setenv("TENANT_HEADER", "none")
+	t.Setenv("REQUIRE_TENANT", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.TenantHeader != "" || !cfg.RequireTenant {
+		t.Errorf("Expected the tenant header to be disabled and tenants required, got %q and %v", cfg.TenantHeader, cfg.RequireTenant)
+	}
+
+	t.Setenv("REQUIRE_TENANT", "sometimes")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for an invalid boolean")
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+// UploadAttachment accepts a multipart/form-data body with a "file" part and
+// an optional "checksum" field holding the file's hex-encoded SHA-256. The
+// checksum must come before the file, which is streamed to the blob store
+// without being buffered.
+func UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/attachments")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	var checksum string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			http.Error(w, "Missing file part", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		switch part.FormName() {
+		case "checksum":
+			value, err := io.ReadAll(io.LimitReader(part, 256))
+			if err != nil {
+				http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+				return
+			}
+			checksum = strings.TrimSpace(string(value))
+			if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != 32 {
+				http.Error(w, "Checksum must be a hex-encoded SHA-256", http.StatusBadRequest)
+				return
+			}
+		case "file":
+			attachment, err := services.UploadAttachment(r.Context(), id, services.AttachmentUpload{
+				Filename: part.FileName(),
+				Content:  part,
+				Checksum: checksum,
+			})
+			if err != nil {
+				status, message := attachmentErrorStatus(err)
+				http.Error(w, message, status)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(attachment)
+			return
+		}
+	}
+}
+
+func GetStudentAttachments(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/attachments")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := services.ListAttachments(r.Context(), id)
+	if err != nil {
+		status, message := attachmentErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+func GetAttachment(w http.ResponseWriter, r *http.Request) {
+	studentID, attachmentID, ok := parseAttachmentPath(w, r, "")
+	if !ok {
+		return
+	}
+
+	attachment, err := services.GetAttachment(r.Context(), studentID, attachmentID)
+	if err != nil {
+		status, message := attachmentErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// DownloadAttachment serves an attachment's content with support for range
+// and conditional requests. The ETag and Digest headers carry the SHA-256
+// recorded at upload so clients can verify what they received.
+func DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	studentID, attachmentID, ok := parseAttachmentPath(w, r, "content")
+	if !ok {
+		return
+	}
+
+	attachment, content, err := services.OpenAttachment(r.Context(), studentID, attachmentID)
+	if err != nil {
+		status, message := attachmentErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+	defer content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+attachment.Checksum+`"`)
+	if sum, err := hex.DecodeString(attachment.Checksum); err == nil {
+		w.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+	}
+	http.ServeContent(w, r, "", attachment.UploadedAt, content)
+}
+
+func DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	studentID, attachmentID, ok := parseAttachmentPath(w, r, "")
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteAttachment(r.Context(), studentID, attachmentID); err != nil {
+		status, message := attachmentErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseAttachmentPath parses /students/{id}/attachments/{aid}, followed by
+// /{suffix} when suffix is set, writing an error response when it fails.
+func parseAttachmentPath(w http.ResponseWriter, r *http.Request, suffix string) (int, int, bool) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	parts := strings.Split(path, "/")
+	expected := 3
+	if suffix != "" {
+		expected = 4
+	}
+	if len(parts) != expected || parts[1] != "attachments" || (suffix != "" && parts[3] != suffix) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return 0, 0, false
+	}
+
+	studentID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return studentID, attachmentID, true
+}
+
+func attachmentErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrStudentNotFound):
+		return http.StatusNotFound, "Student not found"
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		return http.StatusNotFound, "Attachment not found"
+	case errors.Is(err, services.ErrAttachmentEmpty), errors.Is(err, services.ErrAttachmentChecksum):
+		return http.StatusBadRequest, capitalize(err.Error())
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge, fmt.Sprintf("Attachment is too large (limit %d bytes)", services.AttachmentMaxSize())
+	case errors.Is(err, services.ErrUnsupportedAttachmentType):
+		return http.StatusUnsupportedMediaType, capitalize(err.Error())
+	default:
+		return http.StatusInternalServerError, "Failed to process attachment"
+	}
+}
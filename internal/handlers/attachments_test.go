@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+var pngContent = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 92)...)
+
+func newUploadRequest(t *testing.T, path, checksum, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if checksum != "" {
+		writer.WriteField("checksum", checksum)
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAttachmentHandlers(t *testing.T) {
+	setupTest()
+	services.ConfigureAttachments(services.AttachmentSettings{
+		Store:        services.NewMemoryBlobStore(),
+		MaxSize:      256,
+		AllowedTypes: []string{"image/png"},
+	})
+	student, _ := services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	sum := sha256.Sum256(pngContent)
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"upload photo", newUploadRequest(t, "/students/1/attachments", hex.EncodeToString(sum[:]), "photo.png", pngContent), http.StatusCreated},
+		{"checksum mismatch", newUploadRequest(t, "/students/1/attachments", hex.EncodeToString(make([]byte, 32)), "photo.png", pngContent), http.StatusBadRequest},
+		{"malformed checksum", newUploadRequest(t, "/students/1/attachments", "abc", "photo.png", pngContent), http.StatusBadRequest},
+		{"unsupported type", newUploadRequest(t, "/students/1/attachments", "", "notes.png", []byte("just some text")), http.StatusUnsupportedMediaType},
+		{"too large", newUploadRequest(t, "/students/1/attachments", "", "big.png", append(pngContent, make([]byte, 256)...)), http.StatusRequestEntityTooLarge},
+		{"missing student", newUploadRequest(t, "/students/99/attachments", "", "photo.png", pngContent), http.StatusNotFound},
+		{"not multipart", httptest.NewRequest("POST", "/students/1/attachments", bytes.NewReader(pngContent)), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			UploadAttachment(rr, tt.req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/students/1/attachments", nil)
+	rr := httptest.NewRecorder()
+	GetStudentAttachments(rr, req)
+
+	var listed []models.Attachment
+	json.NewDecoder(rr.Body).Decode(&listed)
+	if len(listed) != 1 || listed[0].StudentID != student.ID || listed[0].ContentType != "image/png" {
+		t.Fatalf("Expected one PNG attachment, got %+v", listed)
+	}
+
+	req = httptest.NewRequest("DELETE", "/students/1/attachments/1", nil)
+	rr = httptest.NewRecorder()
+	DeleteAttachment(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/students/1/attachments/1", nil)
+	rr = httptest.NewRecorder()
+	GetAttachment(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after delete, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	setupTest()
+	services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+
+	rr := httptest.NewRecorder()
+	UploadAttachment(rr, newUploadRequest(t, "/students/1/attachments", "", "photo.png", pngContent))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var attachment models.Attachment
+	json.NewDecoder(rr.Body).Decode(&attachment)
+
+	req := httptest.NewRequest("GET", "/students/1/attachments/1/content", nil)
+	rr = httptest.NewRecorder()
+	DownloadAttachment(rr, req)
+
+	if rr.Code != http.StatusOK || !bytes.Equal(rr.Body.Bytes(), pngContent) {
+		t.Fatalf("Expected the full content, got status %d with %d bytes", rr.Code, rr.Body.Len())
+	}
+	if rr.Header().Get("Content-Type") != "image/png" || rr.Header().Get("ETag") != `"`+attachment.Checksum+`"` {
+		t.Errorf("Unexpected headers: %v", rr.Header())
+	}
+	if rr.Header().Get("Content-Disposition") != `attachment; filename=photo.png` {
+		t.Errorf("Unexpected Content-Disposition %q", rr.Header().Get("Content-Disposition"))
+	}
+
+	req = httptest.NewRequest("GET", "/students/1/attachments/1/content", nil)
+	req.Header.Set("Range", "bytes=0-7")
+	rr = httptest.NewRecorder()
+	DownloadAttachment(rr, req)
+
+	if rr.Code != http.StatusPartialContent || !bytes.Equal(rr.Body.Bytes(), pngContent[:8]) {
+		t.Errorf("Expected the first 8 bytes, got status %d with %q", rr.Code, rr.Body.Bytes())
+	}
+	if rr.Header().Get("Content-Range") != "bytes 0-7/100" {
+		t.Errorf("Unexpected Content-Range %q", rr.Header().Get("Content-Range"))
+	}
+
+	req = httptest.NewRequest("GET", "/students/1/attachments/1/content", nil)
+	req.Header.Set("If-None-Match", `"`+attachment.Checksum+`"`)
+	rr = httptest.NewRecorder()
+	DownloadAttachment(rr, req)
+
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d for a matching ETag, got %d", http.StatusNotModified, rr.Code)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+// RecordCourseAttendance records one class session for a course in bulk.
+func RecordCourseAttendance(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/courses/")
+	idStr := strings.TrimSuffix(path, "/attendance")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Date    string                     `json:"date"`
+		Records []services.AttendanceEntry `json:"records"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	records, err := services.RecordAttendance(r.Context(), id, req.Date, req.Records)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// GetCourseAttendance lists a course's attendance records. ?date= selects
+// one session; ?from= and ?to= a range of them.
+func GetCourseAttendance(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/courses/")
+	idStr := strings.TrimSuffix(path, "/attendance")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	if date := query.Get("date"); date != "" {
+		query.Set("from", date)
+		query.Set("to", date)
+	}
+	attendanceRange, err := parseAttendanceRange(query)
+	if err != nil {
+		http.Error(w, "Invalid attendance range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := services.GetCourseAttendance(r.Context(), id, attendanceRange)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+func GetStudentAttendance(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/attendance")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	attendanceRange, err := parseAttendanceRange(r.URL.Query())
+	if err != nil {
+		http.Error(w, "Invalid attendance range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attendance, err := services.GetStudentAttendance(r.Context(), id, attendanceRange)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attendance)
+}
+
+func GetAttendanceReport(w http.ResponseWriter, r *http.Request) {
+	attendanceRange, err := parseAttendanceRange(r.URL.Query())
+	if err != nil {
+		http.Error(w, "Invalid attendance range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := services.GetAttendanceReport(r.Context(), attendanceRange)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetLowAttendanceReport lists students below ?threshold=, or the configured
+// attendance threshold when none is given.
+func GetLowAttendanceReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	attendanceRange, err := parseAttendanceRange(query)
+	if err != nil {
+		http.Error(w, "Invalid attendance range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	threshold := services.AttendanceThreshold()
+	if thresholdStr := query.Get("threshold"); thresholdStr != "" {
+		threshold, err = strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			http.Error(w, "Invalid threshold parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report := services.GetLowAttendanceReport(r.Context(), attendanceRange, threshold)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func parseAttendanceRange(query url.Values) (services.AttendanceRange, error) {
+	attendanceRange := services.AttendanceRange{
+		From: query.Get("from"),
+		To:   query.Get("to"),
+	}
+	if courseID := query.Get("course_id"); courseID != "" {
+		value, err := strconv.Atoi(courseID)
+		if err != nil || value <= 0 {
+			return attendanceRange, errors.New("invalid course_id parameter")
+		}
+		attendanceRange.CourseID = value
+	}
+	return attendanceRange, attendanceRange.Validate()
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestAttendanceHandlers(t *testing.T) {
+	setupTest()
+
+	course, _ := services.CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro", Capacity: 5})
+	for _, name := range []string{"Alice", "Bob"} {
+		student, _ := services.CreateStudent(context.Background(), models.Student{Name: name, Age: 20, Email: name + "@example.com"})
+		services.EnrollStudent(context.Background(), student.ID, course.ID)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"record session", "POST", "/courses/1/attendance", `{"date":"2026-10-05","records":[{"student_id":1,"status":"present"},{"student_id":2,"status":"absent"}]}`, RecordCourseAttendance, http.StatusOK},
+		{"record second session", "POST", "/courses/1/attendance", `{"date":"2026-10-12","records":[{"student_id":1,"status":"late"},{"student_id":2,"status":"present"}]}`, RecordCourseAttendance, http.StatusOK},
+		{"invalid status", "POST", "/courses/1/attendance", `{"date":"2026-10-19","records":[{"student_id":1,"status":"sick"}]}`, RecordCourseAttendance, http.StatusBadRequest},
+		{"unknown course", "POST", "/courses/99/attendance", `{"date":"2026-10-19","records":[{"student_id":1,"status":"present"}]}`, RecordCourseAttendance, http.StatusNotFound},
+		{"unknown student", "POST", "/courses/1/attendance", `{"date":"2026-10-19","records":[{"student_id":99,"status":"present"}]}`, RecordCourseAttendance, http.StatusNotFound},
+		{"course session", "GET", "/courses/1/attendance?date=2026-10-05", "", GetCourseAttendance, http.StatusOK},
+		{"invalid range", "GET", "/courses/1/attendance?from=2026-10-12&to=2026-10-05", "", GetCourseAttendance, http.StatusBadRequest},
+		{"student attendance", "GET", "/students/1/attendance?from=2026-10-01", "", GetStudentAttendance, http.StatusOK},
+		{"missing student attendance", "GET", "/students/99/attendance", "", GetStudentAttendance, http.StatusNotFound},
+		{"report", "GET", "/attendance/report?course_id=1", "", GetAttendanceReport, http.StatusOK},
+		{"invalid course_id", "GET", "/attendance/report?course_id=abc", "", GetAttendanceReport, http.StatusBadRequest},
+		{"invalid threshold", "GET", "/attendance/below-threshold?threshold=2", "", GetLowAttendanceReport, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/attendance/below-threshold", nil)
+	rr := httptest.NewRecorder()
+	GetLowAttendanceReport(rr, req)
+
+	var report models.AttendanceReport
+	json.NewDecoder(rr.Body).Decode(&report)
+	if report.Threshold == nil || *report.Threshold != services.AttendanceThreshold() {
+		t.Errorf("Expected the configured threshold, got %v", report.Threshold)
+	}
+	if len(report.Students) != 1 || report.Students[0].StudentName != "Bob" || *report.Students[0].Rate != 0.5 {
+		t.Errorf("Expected Bob at 50%% attendance, got %+v", report.Students)
+	}
+}
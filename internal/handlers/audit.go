@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-api/internal/services"
+)
+
+// GetStudentHistory returns the audit events of a student, including one that
+// has been soft-deleted. Each event's actor is the authenticated identity
+// when the request carried a token; otherwise it is the unverified X-Actor
+// header, which any caller can set.
+func GetStudentHistory(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/history")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	student, err := services.GetStudentIncludingDeleted(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+	history := services.GetStudentHistory(r.Context(), student)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	events := services.GetAuditEvents(r.Context(), since)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"testing"
+	"time"
+)
+
+func TestGetStudentHistory(t *testing.T) {
+	setupTest()
+
+	jsonData, _ := json.Marshal(models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	req := httptest.NewRequest("POST", "/students", bytes.NewBuffer(jsonData))
+	req = req.WithContext(reqctx.WithActor(req.Context(), "registrar"))
+	CreateStudent(httptest.NewRecorder(), req)
+
+	jsonData, _ = json.Marshal(models.Student{Name: "John Doe", Age: 21, Email: "john@example.com"})
+	req = httptest.NewRequest("PUT", "/students/1", bytes.NewBuffer(jsonData))
+	UpdateStudent(httptest.NewRecorder(), req)
+
+	jsonData, _ = json.Marshal(models.Student{Name: "Jane Doe", Age: 22, Email: "jane@example.com"})
+	CreateStudent(httptest.NewRecorder(), httptest.NewRequest("POST", "/students", bytes.NewBuffer(jsonData)))
+	DeleteStudent(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/students/2", nil))
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedEvents int
+	}{
+		{
+			name:           "Existing student history",
+			url:            "/students/1/history",
+			expectedStatus: http.StatusOK,
+			expectedEvents: 2,
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/history",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID format",
+			url:            "/students/abc/history",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			GetStudentHistory(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var events []models.AuditEvent
+				json.Unmarshal(rr.Body.Bytes(), &events)
+				if len(events) != tt.expectedEvents {
+					t.Fatalf("Expected %d events, got %d", tt.expectedEvents, len(events))
+				}
+				if events[0].Actor != "registrar" {
+					t.Errorf("Expected actor registrar, got %s", events[0].Actor)
+				}
+				if events[1].Action != models.AuditActionUpdate || len(events[1].Changes) != 1 {
+					t.Errorf("Expected update with a single change, got %+v", events[1])
+				}
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	GetStudentHistory(rr, httptest.NewRequest("GET", "/students/2/history", nil))
+	var events []models.AuditEvent
+	json.Unmarshal(rr.Body.Bytes(), &events)
+	if rr.Code != http.StatusOK || len(events) != 2 || events[1].Action != models.AuditActionDelete {
+		t.Errorf("Expected a deleted student's history to end with its deletion, got %d and %+v", rr.Code, events)
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	setupTest()
+
+	jsonData, _ := json.Marshal(models.Student{Name: "Jane Doe", Age: 22, Email: "jane@example.com"})
+	CreateStudent(httptest.NewRecorder(), httptest.NewRequest("POST", "/students", bytes.NewBuffer(jsonData)))
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedEvents int
+	}{
+		{
+			name:           "All events",
+			url:            "/audit",
+			expectedStatus: http.StatusOK,
+			expectedEvents: 1,
+		},
+		{
+			name:           "Events since the future",
+			url:            "/audit?since=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			expectedStatus: http.StatusOK,
+			expectedEvents: 0,
+		},
+		{
+			name:           "Invalid since",
+			url:            "/audit?since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			GetAuditEvents(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var events []models.AuditEvent
+				json.Unmarshal(rr.Body.Bytes(), &events)
+				if len(events) != tt.expectedEvents {
+					t.Errorf("Expected %d events, got %d", tt.expectedEvents, len(events))
+				}
+			}
+		})
+	}
+}
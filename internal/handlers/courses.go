@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+func CreateCourse(w http.ResponseWriter, r *http.Request) {
+	var course models.Course
+	if err := json.NewDecoder(r.Body).Decode(&course); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := course.Validate(); err != nil {
+		http.Error(w, "Invalid course: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := services.CreateCourse(r.Context(), course)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func GetAllCourses(w http.ResponseWriter, r *http.Request) {
+	courses := services.ListCourses(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(courses)
+}
+
+func GetCourseByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/courses/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	course, err := services.GetCourse(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(course)
+}
+
+func UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/courses/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var course models.Course
+	if err := json.NewDecoder(r.Body).Decode(&course); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := course.Validate(); err != nil {
+		http.Error(w, "Invalid course: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := services.UpdateCourse(r.Context(), id, course)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func DeleteCourse(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/courses/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.DeleteCourse(r.Context(), id); err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCourseStudents lists a course's enrollments with their students. Only
+// active enrollments are listed unless ?status= asks for another status.
+func GetCourseStudents(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/courses/")
+	idStr := strings.TrimSuffix(path, "/students")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	status := models.EnrollmentActive
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status = models.EnrollmentStatus(statusStr)
+		if !status.IsValid() {
+			http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	roster, err := services.GetCourseRoster(r.Context(), id, status)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roster)
+}
+
+func CreateEnrollment(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/enrollments")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		CourseID int `json:"course_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.CourseID <= 0 {
+		http.Error(w, "course_id is required", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := services.EnrollStudent(r.Context(), id, req.CourseID)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+func GetStudentEnrollments(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/enrollments")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := services.GetStudentByID(r.Context(), id); err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	enrollments := services.GetStudentEnrollments(r.Context(), id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollments)
+}
+
+// UpdateEnrollment changes the status of one of a student's enrollments.
+func UpdateEnrollment(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[1] != "enrollments" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	enrollmentID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		http.Error(w, "Invalid enrollment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status models.EnrollmentStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !req.Status.IsValid() {
+		http.Error(w, "status must be one of active, completed or dropped", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := services.UpdateEnrollmentStatus(r.Context(), id, enrollmentID, req.Status)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+func courseErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAttendance):
+		return http.StatusBadRequest, capitalize(err.Error())
+	case errors.Is(err, services.ErrStudentNotFound):
+		return http.StatusNotFound, "Student not found"
+	case errors.Is(err, services.ErrCourseNotFound):
+		return http.StatusNotFound, "Course not found"
+	case errors.Is(err, services.ErrEnrollmentNotFound):
+		return http.StatusNotFound, "Enrollment not found"
+	case errors.Is(err, services.ErrGradeNotFound):
+		return http.StatusNotFound, "Grade not found"
+	case errors.Is(err, services.ErrCourseCodeTaken),
+		errors.Is(err, services.ErrCourseFull),
+		errors.Is(err, services.ErrCourseHasEnrollments),
+		errors.Is(err, services.ErrCapacityBelowEnrollment),
+		errors.Is(err, services.ErrAlreadyEnrolled),
+		errors.Is(err, services.ErrInvalidEnrollmentTransition),
+		errors.Is(err, services.ErrNotEnrolled):
+		return http.StatusConflict, capitalize(err.Error())
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
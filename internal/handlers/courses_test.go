@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestCourseHandlers(t *testing.T) {
+	setupTest()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"create course", "POST", "/courses", `{"code":"CS101","title":"Intro","capacity":1}`, CreateCourse, http.StatusCreated},
+		{"duplicate code", "POST", "/courses", `{"code":"CS101","title":"Again","capacity":5}`, CreateCourse, http.StatusConflict},
+		{"invalid course", "POST", "/courses", `{"code":"CS102"}`, CreateCourse, http.StatusBadRequest},
+		{"get course", "GET", "/courses/1", "", GetCourseByID, http.StatusOK},
+		{"get missing course", "GET", "/courses/99", "", GetCourseByID, http.StatusNotFound},
+		{"invalid course ID", "GET", "/courses/abc", "", GetCourseByID, http.StatusBadRequest},
+		{"update course", "PUT", "/courses/1", `{"code":"CS101","title":"Programming I","capacity":2}`, UpdateCourse, http.StatusOK},
+		{"update missing course", "PUT", "/courses/99", `{"code":"CS9","title":"None","capacity":2}`, UpdateCourse, http.StatusNotFound},
+		{"delete missing course", "DELETE", "/courses/99", "", DeleteCourse, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestEnrollmentHandlers(t *testing.T) {
+	setupTest()
+
+	alice, _ := services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	bob, _ := services.CreateStudent(context.Background(), models.Student{Name: "Bob", Age: 21, Email: "bob@example.com"})
+	course, _ := services.CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro", Capacity: 1})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"enroll", "POST", "/students/1/enrollments", `{"course_id":1}`, CreateEnrollment, http.StatusCreated},
+		{"enroll twice", "POST", "/students/1/enrollments", `{"course_id":1}`, CreateEnrollment, http.StatusConflict},
+		{"course full", "POST", "/students/2/enrollments", `{"course_id":1}`, CreateEnrollment, http.StatusConflict},
+		{"missing course", "POST", "/students/2/enrollments", `{"course_id":99}`, CreateEnrollment, http.StatusNotFound},
+		{"missing student", "POST", "/students/99/enrollments", `{"course_id":1}`, CreateEnrollment, http.StatusNotFound},
+		{"missing course_id", "POST", "/students/2/enrollments", `{}`, CreateEnrollment, http.StatusBadRequest},
+		{"list enrollments", "GET", "/students/1/enrollments", "", GetStudentEnrollments, http.StatusOK},
+		{"delete course with enrollments", "DELETE", "/courses/1", "", DeleteCourse, http.StatusConflict},
+		{"invalid status", "PATCH", "/students/1/enrollments/1", `{"status":"paused"}`, UpdateEnrollment, http.StatusBadRequest},
+		{"another student's enrollment", "PATCH", "/students/2/enrollments/1", `{"status":"dropped"}`, UpdateEnrollment, http.StatusNotFound},
+		{"complete", "PATCH", "/students/1/enrollments/1", `{"status":"completed"}`, UpdateEnrollment, http.StatusOK},
+		{"reopen completed", "PATCH", "/students/1/enrollments/1", `{"status":"active"}`, UpdateEnrollment, http.StatusConflict},
+		{"seat freed", "POST", "/students/2/enrollments", `{"course_id":1}`, CreateEnrollment, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/courses/1/students", nil)
+	rr := httptest.NewRecorder()
+	GetCourseStudents(rr, req)
+
+	var roster []models.Enrollment
+	json.NewDecoder(rr.Body).Decode(&roster)
+	if len(roster) != 1 || roster[0].StudentID != bob.ID || roster[0].Student == nil || roster[0].CourseID != course.ID {
+		t.Errorf("Expected Bob as the only active student, got %+v", roster)
+	}
+
+	req = httptest.NewRequest("GET", "/courses/1/students?status=completed", nil)
+	rr = httptest.NewRecorder()
+	GetCourseStudents(rr, req)
+
+	roster = nil
+	json.NewDecoder(rr.Body).Decode(&roster)
+	if len(roster) != 1 || roster[0].StudentID != alice.ID {
+		t.Errorf("Expected Alice as the completed student, got %+v", roster)
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+// CustomFieldHandler serves the custom field admin endpoints of the request's
+// tenant. Defining and removing fields changes how every student write is
+// validated, so those requests must present AdminToken as a bearer token;
+// with no AdminToken they are disabled. Listing the fields is open to the
+// tenant.
+type CustomFieldHandler struct {
+	AdminToken string
+}
+
+func (h *CustomFieldHandler) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken) {
+		return
+	}
+
+	var definition models.CustomFieldDefinition
+	if err := json.NewDecoder(r.Body).Decode(&definition); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := definition.Validate(); err != nil {
+		http.Error(w, "Invalid custom field: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := services.CreateCustomField(r.Context(), definition)
+	if errors.Is(err, services.ErrCustomFieldExists) {
+		http.Error(w, "Custom field already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to create custom field", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	definitions := services.ListCustomFields(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(definitions)
+}
+
+func (h *CustomFieldHandler) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken) {
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/admin/custom-fields/")
+
+	err := services.DeleteCustomField(r.Context(), key)
+	if errors.Is(err, services.ErrCustomFieldNotFound) {
+		http.Error(w, "Custom field not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, services.ErrCustomFieldInUse) {
+		http.Error(w, "Custom field is in use", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete custom field", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
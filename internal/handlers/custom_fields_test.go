@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestCustomFieldHandlers(t *testing.T) {
+	setupTest()
+	fields := &CustomFieldHandler{AdminToken: "secret"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"create field", "POST", "/admin/custom-fields", `{"key":"house","label":"House","type":"enum","options":["red","blue"]}`, fields.CreateCustomField, http.StatusCreated},
+		{"duplicate field", "POST", "/admin/custom-fields", `{"key":"house","label":"House","type":"string"}`, fields.CreateCustomField, http.StatusConflict},
+		{"invalid field", "POST", "/admin/custom-fields", `{"key":"House","label":"House","type":"string"}`, fields.CreateCustomField, http.StatusBadRequest},
+		{"list fields", "GET", "/admin/custom-fields", "", GetCustomFields, http.StatusOK},
+		{"create student with field", "POST", "/students", `{"name":"Alice","age":20,"email":"alice@example.com","custom_fields":{"house":"red"}}`, CreateStudent, http.StatusCreated},
+		{"create student with invalid value", "POST", "/students", `{"name":"Bob","age":20,"email":"bob@example.com","custom_fields":{"house":"green"}}`, CreateStudent, http.StatusBadRequest},
+		{"create student with unknown field", "POST", "/students", `{"name":"Bob","age":20,"email":"bob@example.com","custom_fields":{"locker":"A1"}}`, CreateStudent, http.StatusBadRequest},
+		{"delete field in use", "DELETE", "/admin/custom-fields/house", "", fields.DeleteCustomField, http.StatusConflict},
+		{"delete missing field", "DELETE", "/admin/custom-fields/locker", "", fields.DeleteCustomField, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestCustomFieldHandlers_AdminToken(t *testing.T) {
+	setupTest()
+
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		expectedStatus int
+	}{
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"no admin token configured", "", "Bearer secret", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := &CustomFieldHandler{AdminToken: tt.adminToken}
+
+			for _, req := range []*http.Request{
+				httptest.NewRequest("POST", "/admin/custom-fields", strings.NewReader(`{"key":"house","label":"House","type":"string"}`)),
+				httptest.NewRequest("DELETE", "/admin/custom-fields/house", nil),
+			} {
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rr := httptest.NewRecorder()
+				if req.Method == "POST" {
+					fields.CreateCustomField(rr, req)
+				} else {
+					fields.DeleteCustomField(rr, req)
+				}
+
+				if rr.Code != tt.expectedStatus {
+					t.Errorf("%s %s: expected status %d, got %d", req.Method, req.URL.Path, tt.expectedStatus, rr.Code)
+				}
+			}
+		})
+	}
+	if definitions := services.ListCustomFields(context.Background()); len(definitions) != 0 {
+		t.Errorf("Expected no field to be created without the admin token, got %+v", definitions)
+	}
+}
+
+func TestCreateStudentProfile(t *testing.T) {
+	setupTest()
+
+	body := `{
+		"name": "Jane Doe",
+		"email": "jane@example.com",
+		"date_of_birth": "2004-03-09",
+		"enrollment_date": "2022-09-01",
+		"contacts": [{"type": "phone", "value": "+1 555 123 4567", "primary": true}],
+		"guardians": [{"name": "Mary Doe", "relationship": "mother"}],
+		"addresses": [{"type": "home", "line1": "1 Main St", "city": "Springfield", "country": "US"}]
+	}`
+	req := httptest.NewRequest("POST", "/students", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	CreateStudent(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var created models.Student
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if created.DateOfBirth == nil || created.DateOfBirth.String() != "2004-03-09" || created.Age < 20 {
+		t.Errorf("Expected date of birth 2004-03-09 with a derived age, got %v and %d", created.DateOfBirth, created.Age)
+	}
+	if created.Status != models.StudentEnrolled {
+		t.Errorf("Expected default status enrolled, got %s", created.Status)
+	}
+	if len(created.Contacts) != 1 || len(created.Guardians) != 1 || len(created.Addresses) != 1 {
+		t.Errorf("Expected contacts, guardians and addresses to round-trip, got %+v", created)
+	}
+
+	invalid := `{"name":"Jane Doe","email":"jane@example.com","date_of_birth":"09/03/2004"}`
+	req = httptest.NewRequest("POST", "/students", strings.NewReader(invalid))
+	rr = httptest.NewRecorder()
+	CreateStudent(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a malformed date, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+	"time"
+)
+
+func TestStreamStudentEvents(t *testing.T) {
+	setupTest()
+	services.ResetEvents()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Buffered", Age: 20, Email: "buffered@example.com"})
+
+	server := httptest.NewServer(http.HandlerFunc(StreamStudentEvents))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/students/events", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", contentType)
+	}
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Live", Age: 21, Email: "live@example.com"})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var received []string
+	for len(received) < 1 {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "data: ") {
+				received = append(received, line)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for events, got %v", received)
+		}
+	}
+
+	if !strings.Contains(received[0], "Live") {
+		t.Errorf("Expected live event without backlog for Last-Event-ID 0, got %s", received[0])
+	}
+}
+
+func TestStreamStudentEventsResume(t *testing.T) {
+	setupTest()
+	services.ResetEvents()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "First", Age: 20, Email: "first@example.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Second", Age: 21, Email: "second@example.com"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/students/events", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "1")
+	rr := httptest.NewRecorder()
+
+	cancel()
+	StreamStudentEvents(rr, req)
+
+	body := rr.Body.String()
+	if strings.Contains(body, "First") || !strings.Contains(body, "Second") {
+		t.Errorf("Expected only events after Last-Event-ID 1, got %s", body)
+	}
+	if !strings.Contains(body, "id: 2\nevent: student.created\n") {
+		t.Errorf("Expected SSE id and event fields, got %s", body)
+	}
+}
+
+func TestStreamStudentEventsInvalidLastEventID(t *testing.T) {
+	req := httptest.NewRequest("GET", "/students/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rr := httptest.NewRecorder()
+	StreamStudentEvents(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+func RecordGrade(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/grades")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	var grade models.Grade
+	if err := json.NewDecoder(r.Body).Decode(&grade); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if grade.CourseID <= 0 {
+		http.Error(w, "course_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := grade.Validate(); err != nil {
+		http.Error(w, "Invalid grade: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	grade.StudentID = id
+	recorded, err := services.RecordGrade(r.Context(), grade)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recorded)
+}
+
+func GetStudentGrades(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/grades")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := services.GetStudentByID(r.Context(), id); err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	grades := services.GetStudentGrades(r.Context(), id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grades)
+}
+
+func UpdateGrade(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[1] != "grades" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	gradeID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		http.Error(w, "Invalid grade ID", http.StatusBadRequest)
+		return
+	}
+
+	var grade models.Grade
+	if err := json.NewDecoder(r.Body).Decode(&grade); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := grade.Validate(); err != nil {
+		http.Error(w, "Invalid grade: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := services.UpdateGrade(r.Context(), id, gradeID, grade)
+	if err != nil {
+		status, message := courseErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func GetTranscript(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/transcript")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	transcript, err := services.GetTranscript(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transcript)
+}
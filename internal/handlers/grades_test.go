@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestGradeHandlers(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	course, _ := services.CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro", Capacity: 5})
+	services.CreateCourse(context.Background(), models.Course{Code: "ART101", Title: "Drawing", Capacity: 5})
+	services.EnrollStudent(context.Background(), 1, course.ID)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"record grade", "POST", "/students/1/grades", `{"course_id":1,"assessment":"Midterm","score":45,"max_score":50,"weight":1}`, RecordGrade, http.StatusCreated},
+		{"score above max", "POST", "/students/1/grades", `{"course_id":1,"assessment":"Quiz","score":11,"max_score":10,"weight":1}`, RecordGrade, http.StatusBadRequest},
+		{"missing course_id", "POST", "/students/1/grades", `{"assessment":"Quiz","score":1,"max_score":10,"weight":1}`, RecordGrade, http.StatusBadRequest},
+		{"not enrolled", "POST", "/students/1/grades", `{"course_id":2,"assessment":"Quiz","score":1,"max_score":10,"weight":1}`, RecordGrade, http.StatusConflict},
+		{"missing student", "POST", "/students/99/grades", `{"course_id":1,"assessment":"Quiz","score":1,"max_score":10,"weight":1}`, RecordGrade, http.StatusNotFound},
+		{"update grade", "PUT", "/students/1/grades/1", `{"assessment":"Midterm","score":40,"max_score":50,"weight":2}`, UpdateGrade, http.StatusOK},
+		{"update missing grade", "PUT", "/students/1/grades/99", `{"assessment":"Midterm","score":40,"max_score":50,"weight":2}`, UpdateGrade, http.StatusNotFound},
+		{"invalid grade ID", "PUT", "/students/1/grades/abc", `{}`, UpdateGrade, http.StatusBadRequest},
+		{"list grades", "GET", "/students/1/grades", "", GetStudentGrades, http.StatusOK},
+		{"missing transcript", "GET", "/students/99/transcript", "", GetTranscript, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/students/1/transcript", nil)
+	rr := httptest.NewRecorder()
+	GetTranscript(rr, req)
+
+	var transcript models.Transcript
+	json.NewDecoder(rr.Body).Decode(&transcript)
+	if len(transcript.Courses) != 1 || transcript.Courses[0].Letter != "B" || transcript.GPA == nil || *transcript.GPA != 3 {
+		t.Errorf("Expected a B in CS101 and GPA 3, got %+v", transcript)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+type summaryJobRequest struct {
+	StudentIDs []int                   `json:"student_ids"`
+	Filter     *services.StudentFilter `json:"filter"`
+}
+
+func CreateSummaryJob(w http.ResponseWriter, r *http.Request) {
+	var request summaryJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if (len(request.StudentIDs) == 0) == (request.Filter == nil) {
+		http.Error(w, "Provide either student_ids or filter", http.StatusBadRequest)
+		return
+	}
+
+	studentIDs := request.StudentIDs
+	if request.Filter != nil {
+		if request.Filter.IncludeDeleted {
+			http.Error(w, "Invalid student filter: deleted students cannot be summarised", http.StatusBadRequest)
+			return
+		}
+		if err := request.Filter.Validate(); err != nil {
+			http.Error(w, "Invalid student filter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, student := range services.ListStudents(r.Context(), *request.Filter) {
+			studentIDs = append(studentIDs, student.ID)
+		}
+	}
+
+	job, err := services.CreateSummaryJob(r.Context(), studentIDs)
+	if err != nil {
+		status, message := summaryJobErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/summaries/jobs/"+strconv.Itoa(job.ID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func GetAllSummaryJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := services.ListSummaryJobs(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func GetSummaryJobByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/summaries/jobs/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := services.GetSummaryJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func CancelSummaryJob(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/summaries/jobs/")
+	idStr := strings.TrimSuffix(path, "/cancel")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := services.CancelSummaryJob(r.Context(), id)
+	if errors.Is(err, services.ErrSummaryJobFinished) {
+		http.Error(w, "Job already finished", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func summaryJobErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrSummaryJobEmpty):
+		return http.StatusBadRequest, "No students match the filter"
+	case errors.Is(err, services.ErrStudentNotFound):
+		return http.StatusNotFound, capitalize(err.Error())
+	case errors.Is(err, services.ErrTenantNotFound):
+		return http.StatusNotFound, "Tenant not found"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
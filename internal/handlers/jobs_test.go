@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestCreateSummaryJob(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Bob", Age: 30, Email: "bob@example.com"})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{
+			name:           "Explicit student IDs",
+			body:           `{"student_ids":[1,2]}`,
+			expectedStatus: http.StatusAccepted,
+			expectedTotal:  2,
+		},
+		{
+			name:           "Filter",
+			body:           `{"filter":{"min_age":25}}`,
+			expectedStatus: http.StatusAccepted,
+			expectedTotal:  1,
+		},
+		{
+			name:           "Filter without matches",
+			body:           `{"filter":{"min_age":99}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown student ID",
+			body:           `{"student_ids":[1,99]}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid filter",
+			body:           `{"filter":{"min_age":-1}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown status in filter",
+			body:           `{"filter":{"status":["expelled"]}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Filter including deleted students",
+			body:           `{"filter":{"include_deleted":true}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Both IDs and filter",
+			body:           `{"student_ids":[1],"filter":{}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Neither IDs nor filter",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/summaries/jobs", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			CreateSummaryJob(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusAccepted {
+				var job models.SummaryJob
+				json.Unmarshal(rr.Body.Bytes(), &job)
+				if job.Total != tt.expectedTotal {
+					t.Errorf("Expected %d students, got %d", tt.expectedTotal, job.Total)
+				}
+				if job.Status != models.SummaryJobQueued {
+					t.Errorf("Expected queued job, got %s", job.Status)
+				}
+				if rr.Header().Get("Location") == "" {
+					t.Error("Expected Location header")
+				}
+			}
+		})
+	}
+}
+
+func TestGetAndCancelSummaryJob(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	job, _ := services.CreateSummaryJob(context.Background(), []int{1})
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{
+			name:           "Get existing job",
+			method:         "GET",
+			url:            "/summaries/jobs/1",
+			handler:        GetSummaryJobByID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Get non-existent job",
+			method:         "GET",
+			url:            "/summaries/jobs/999",
+			handler:        GetSummaryJobByID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Get invalid ID",
+			method:         "GET",
+			url:            "/summaries/jobs/abc",
+			handler:        GetSummaryJobByID,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Cancel queued job",
+			method:         "POST",
+			url:            "/summaries/jobs/1/cancel",
+			handler:        CancelSummaryJob,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Cancel finished job",
+			method:         "POST",
+			url:            "/summaries/jobs/1/cancel",
+			handler:        CancelSummaryJob,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Cancel non-existent job",
+			method:         "POST",
+			url:            "/summaries/jobs/999/cancel",
+			handler:        CancelSummaryJob,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/summaries/jobs", nil)
+	rr := httptest.NewRecorder()
+	GetAllSummaryJobs(rr, req)
+
+	var jobs []models.SummaryJob
+	json.Unmarshal(rr.Body.Bytes(), &jobs)
+	if len(jobs) != 1 || jobs[0].ID != job.ID || jobs[0].Status != models.SummaryJobCancelled {
+		t.Errorf("Expected the cancelled job to be listed, got %+v", jobs)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+// ModelHandler exposes the models available at the configured Ollama so they
+// can be managed without shell access to the host. Like the tenant admin
+// endpoints, requests must present AdminToken as a bearer token; with no
+// AdminToken the endpoints are disabled.
+type ModelHandler struct {
+	Client     *services.LLMClient
+	AdminToken string
+}
+
+func (h *ModelHandler) ListModels(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken) {
+		return
+	}
+
+	models, err := h.Client.ListModels(r.Context())
+	if err != nil {
+		status, message := ollamaErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models)
+}
+
+// ShowModel returns the details of one model. Model names may contain ":"
+// and "/", so everything after the prefix is the name.
+func (h *ModelHandler) ShowModel(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken) {
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/admin/models/")
+	if name == "" {
+		http.Error(w, "Model name is required", http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.Client.ShowModel(r.Context(), name)
+	if err != nil {
+		status, message := ollamaErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// PullModel downloads a model at Ollama, streaming its progress as
+// server-sent events: "progress" for each update, then "done" or "error".
+func (h *ModelHandler) PullModel(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken) {
+		return
+	}
+
+	var req struct {
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Model = strings.TrimSpace(req.Model)
+	if req.Model == "" {
+		http.Error(w, "Model name is required", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// As with summary streams, the response is only started once Ollama
+	// accepts the pull so that failures can still be reported with a status.
+	started := false
+	startStream := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	err := h.Client.PullModel(r.Context(), req.Model, func(progress services.OllamaPullProgress) error {
+		startStream()
+		if err := writeSSE(w, "progress", progress); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		if !started {
+			status, message := ollamaErrorStatus(err)
+			http.Error(w, message, status)
+			return
+		}
+		writeSSE(w, "error", map[string]string{"error": err.Error()})
+		flusher.Flush()
+		return
+	}
+
+	startStream()
+	writeSSE(w, "done", map[string]string{"model": req.Model})
+	flusher.Flush()
+}
+
+func ollamaErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrOllamaModelNotFound):
+		return http.StatusNotFound, "Model not found"
+	case errors.Is(err, services.ErrProviderUnavailable):
+		return http.StatusServiceUnavailable, "Ollama unavailable"
+	case errors.Is(err, services.ErrProviderTimeout):
+		return http.StatusGatewayTimeout, "Ollama timed out"
+	case errors.Is(err, services.ErrProviderBadResponse):
+		return http.StatusBadGateway, "Invalid response from Ollama"
+	default:
+		return http.StatusInternalServerError, "Failed to reach Ollama"
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/middleware"
+	"student-api/internal/services"
+	"testing"
+	"time"
+)
+
+// newFakeOllama serves the model management endpoints of the Ollama API,
+// knowing only llama3:latest.
+func newFakeOllama(t *testing.T) *services.LLMClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&req)
+		}
+
+		switch r.URL.Path {
+		case "/api/tags":
+			fmt.Fprint(w, `{"models":[{"name":"llama3:latest","model":"llama3:latest","size":4661224676}]}`)
+		case "/api/show":
+			if req.Model != "llama3:latest" {
+				http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"modelfile":"FROM llama3","details":{"family":"llama"}}`)
+		case "/api/pull":
+			if req.Model == "broken" {
+				fmt.Fprint(w, "{\"status\":\"pulling manifest\"}\n{\"error\":\"pull model manifest: file does not exist\"}\n")
+				return
+			}
+			fmt.Fprint(w, "{\"status\":\"pulling manifest\"}\n{\"status\":\"downloading\",\"total\":10,\"completed\":10}\n{\"status\":\"success\"}\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return services.NewLLMClient(services.HTTPSettings{
+		BaseURL:             server.URL,
+		Timeout:             time.Second,
+		RetryBaseDelay:      time.Millisecond,
+		BreakerThreshold:    5,
+		BreakerResetTimeout: time.Second,
+	})
+}
+
+func TestModelHandler_ListModels(t *testing.T) {
+	handler := &ModelHandler{Client: newFakeOllama(t), AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/admin/models", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	handler.ListModels(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	var models []services.OllamaModel
+	json.NewDecoder(rr.Body).Decode(&models)
+	if len(models) != 1 || models[0].Name != "llama3:latest" {
+		t.Errorf("Unexpected models: %+v", models)
+	}
+}
+
+func TestModelHandler_ShowModel(t *testing.T) {
+	handler := &ModelHandler{Client: newFakeOllama(t), AdminToken: "secret"}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/admin/models/llama3:latest", http.StatusOK},
+		{"/admin/models/missing", http.StatusNotFound},
+		{"/admin/models/", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+		handler.ShowModel(rr, req)
+
+		if rr.Code != tt.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.expectedStatus, rr.Code)
+		}
+	}
+}
+
+func TestModelHandler_PullModel(t *testing.T) {
+	handler := &ModelHandler{Client: newFakeOllama(t), AdminToken: "secret"}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedEvents []string
+	}{
+		{"success", `{"model":"llama3"}`, http.StatusOK, []string{"progress", "progress", "progress", "done"}},
+		{"pull error", `{"model":"broken"}`, http.StatusOK, []string{"progress", "error"}},
+		{"missing model", `{}`, http.StatusBadRequest, nil},
+		{"invalid json", `{`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/admin/models/pull", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			rr := httptest.NewRecorder()
+			handler.PullModel(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			var events []string
+			for _, line := range strings.Split(rr.Body.String(), "\n") {
+				if strings.HasPrefix(line, "event: ") {
+					events = append(events, strings.TrimPrefix(line, "event: "))
+				}
+			}
+			if strings.Join(events, ",") != strings.Join(tt.expectedEvents, ",") {
+				t.Errorf("Expected events %v, got %v", tt.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestModelHandler_AdminToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		expectedStatus int
+	}{
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"no admin token configured", "", "Bearer secret", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &ModelHandler{Client: newFakeOllama(t), AdminToken: tt.adminToken}
+
+			for _, req := range []*http.Request{
+				httptest.NewRequest("GET", "/admin/models", nil),
+				httptest.NewRequest("GET", "/admin/models/llama3:latest", nil),
+				httptest.NewRequest("POST", "/admin/models/pull", strings.NewReader(`{"model":"llama3"}`)),
+			} {
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rr := httptest.NewRecorder()
+				switch {
+				case req.URL.Path == "/admin/models":
+					handler.ListModels(rr, req)
+				case req.Method == "POST":
+					handler.PullModel(rr, req)
+				default:
+					handler.ShowModel(rr, req)
+				}
+
+				if rr.Code != tt.expectedStatus {
+					t.Errorf("%s %s: expected status %d, got %d", req.Method, req.URL.Path, tt.expectedStatus, rr.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestModelHandler_ThroughMiddleware(t *testing.T) {
+	services.ResetTenants()
+	handler := &ModelHandler{Client: newFakeOllama(t), AdminToken: "secret"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/models", handler.ListModels)
+	mux.HandleFunc("/admin/models/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/models/pull" {
+			handler.PullModel(w, r)
+			return
+		}
+		handler.ShowModel(w, r)
+	})
+	server := middleware.RequestContextMiddleware(middleware.TenantMiddleware(middleware.TenantOptions{Header: "X-Tenant-ID", Required: true}, mux))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/admin/models", nil),
+		httptest.NewRequest("GET", "/admin/models/llama3:latest", nil),
+		httptest.NewRequest("POST", "/admin/models/pull", strings.NewReader(`{"model":"llama3"}`)),
+	} {
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s %s: expected status %d, got %d: %s", req.Method, req.URL.Path, http.StatusOK, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestModelHandler_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	baseURL := server.URL
+	server.Close()
+
+	handler := &ModelHandler{Client: services.NewLLMClient(services.HTTPSettings{
+		BaseURL:             baseURL,
+		Timeout:             time.Second,
+		BreakerThreshold:    5,
+		BreakerResetTimeout: time.Second,
+	}), AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/admin/models", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	handler.ListModels(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rr.Code)
+	}
+}
@@ -2,15 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"student-api/internal/models"
 	"student-api/internal/services"
 )
 
 type OllamaHandler struct {
-	OllamaService services.OllamaServiceInterface
+	OllamaService services.SummaryProvider
+	SummaryCache  *services.SummaryCache
 }
 
 func (h *OllamaHandler) GenerateSummary(w http.ResponseWriter, r *http.Request) {
@@ -23,22 +29,305 @@ func (h *OllamaHandler) GenerateSummary(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	student, err := services.GetStudentByID(id)
+	refresh := false
+	if refreshStr := r.URL.Query().Get("refresh"); refreshStr != "" {
+		refresh, err = strconv.ParseBool(refreshStr)
+		if err != nil {
+			http.Error(w, "Invalid refresh parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts, promptTemplate, ok := h.parseSummaryOptions(w, r)
+	if !ok {
+		return
+	}
+
+	student, err := services.GetStudentByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	var cacheKey string
+	if h.SummaryCache != nil {
+		cacheKey = services.SummaryCacheKey(r.Context(), student, opts.Model, promptTemplate.Version, opts.Generation)
+		if !refresh {
+			if summary, remaining, ok := h.SummaryCache.Get(cacheKey); ok {
+				setSummaryCacheHeaders(w, "HIT", remaining)
+				writeSummaryResponse(w, http.StatusOK, summary)
+				return
+			}
+		}
+	}
+
+	summary, err := h.OllamaService.GenerateSummary(r.Context(), student, opts)
+	if err != nil {
+		status, message := summaryErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+	summary, err = services.SaveSummary(r.Context(), summary)
+	if err != nil {
+		status, message := summaryErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	if h.SummaryCache != nil {
+		h.SummaryCache.Set(r.Context(), cacheKey, summary)
+		cacheStatus := "MISS"
+		if refresh {
+			cacheStatus = "REFRESH"
+		}
+		setSummaryCacheHeaders(w, cacheStatus, h.SummaryCache.TTL())
+	}
+
+	writeSummaryResponse(w, http.StatusOK, summary)
+}
+
+// RegenerateSummary generates a new summary for the student regardless of the
+// cache and adds it to the student's summary history.
+func (h *OllamaHandler) RegenerateSummary(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/summaries")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	opts, promptTemplate, ok := h.parseSummaryOptions(w, r)
+	if !ok {
+		return
+	}
+
+	student, err := services.GetStudentByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Student not found", http.StatusNotFound)
 		return
 	}
 
-	summary, err := h.OllamaService.GenerateSummary(student)
+	summary, err := h.OllamaService.GenerateSummary(r.Context(), student, opts)
+	if err != nil {
+		status, message := summaryErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+	summary, err = services.SaveSummary(r.Context(), summary)
 	if err != nil {
-		http.Error(w, "Failed to generate summary", http.StatusInternalServerError)
+		status, message := summaryErrorStatus(err)
+		http.Error(w, message, status)
 		return
 	}
 
-	response := map[string]interface{}{
-		"summary": summary,
+	if h.SummaryCache != nil {
+		h.SummaryCache.Set(r.Context(), services.SummaryCacheKey(r.Context(), student, opts.Model, promptTemplate.Version, opts.Generation), summary)
+	}
+
+	writeSummaryResponse(w, http.StatusCreated, summary)
+}
+
+// parseSummaryOptions reads the per-request summary options from the query
+// string, resolving the default model, and writes a 400 response and returns
+// false when they are invalid.
+func (h *OllamaHandler) parseSummaryOptions(w http.ResponseWriter, r *http.Request) (services.SummaryOptions, *services.PromptTemplate, bool) {
+	query := r.URL.Query()
+	opts := services.SummaryOptions{Style: query.Get("style")}
+
+	promptTemplate, err := services.LookupPrompt(opts.Style)
+	if err != nil {
+		message := fmt.Sprintf("Unknown summary style; available styles: %s", strings.Join(services.PromptStyles(), ", "))
+		http.Error(w, message, http.StatusBadRequest)
+		return services.SummaryOptions{}, nil, false
+	}
+
+	if !h.parseModelOptions(w, r, &opts) {
+		return services.SummaryOptions{}, nil, false
+	}
+	return opts, promptTemplate, true
+}
+
+// parseModelOptions reads the model and generation options from the query
+// string into opts, resolving the default model, and writes a 400 response
+// and returns false when they are invalid.
+func (h *OllamaHandler) parseModelOptions(w http.ResponseWriter, r *http.Request, opts *services.SummaryOptions) bool {
+	query := r.URL.Query()
+	allowed := h.OllamaService.Models()
+	model, err := services.ResolveModel(allowed, query.Get("model"))
+	if err != nil {
+		message := fmt.Sprintf("Model not allowed; available models: %s", strings.Join(allowed, ", "))
+		http.Error(w, message, http.StatusBadRequest)
+		return false
+	}
+	opts.Model = model
+
+	generation, err := parseGenerationOptions(query)
+	if err != nil {
+		http.Error(w, "Invalid generation options: "+err.Error(), http.StatusBadRequest)
+		return false
 	}
+	opts.Generation = generation
+	return true
+}
 
+func parseGenerationOptions(query url.Values) (models.GenerationOptions, error) {
+	var generation models.GenerationOptions
+
+	if temperature := query.Get("temperature"); temperature != "" {
+		value, err := strconv.ParseFloat(temperature, 64)
+		if err != nil || value < 0 || value > 2 {
+			return generation, errors.New("temperature must be a number between 0 and 2")
+		}
+		generation.Temperature = &value
+	}
+	if numPredict := query.Get("num_predict"); numPredict != "" {
+		value, err := strconv.Atoi(numPredict)
+		if err != nil || value <= 0 {
+			return generation, errors.New("num_predict must be a positive integer")
+		}
+		generation.NumPredict = &value
+	}
+	if seed := query.Get("seed"); seed != "" {
+		value, err := strconv.Atoi(seed)
+		if err != nil {
+			return generation, errors.New("seed must be an integer")
+		}
+		generation.Seed = &value
+	}
+	for _, stop := range query["stop"] {
+		if stop == "" {
+			return generation, errors.New("stop sequences must not be empty")
+		}
+		generation.Stop = append(generation.Stop, stop)
+	}
+
+	return generation, nil
+}
+
+func setSummaryCacheHeaders(w http.ResponseWriter, cacheStatus string, maxAge time.Duration) {
+	w.Header().Set("X-Cache", cacheStatus)
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+}
+
+func writeSummaryResponse(w http.ResponseWriter, status int, summary models.Summary) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(summary)
+}
+
+func (h *OllamaHandler) StreamSummary(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/summary/stream")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	opts, _, ok := h.parseSummaryOptions(w, r)
+	if !ok {
+		return
+	}
+
+	student, err := services.GetStudentByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	stream, ok := newTokenStream(w)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	summary, err := h.OllamaService.StreamSummary(r.Context(), student, opts, stream.token)
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		if !stream.started {
+			status, message := summaryErrorStatus(err)
+			http.Error(w, message, status)
+			return
+		}
+		stream.send("error", map[string]string{"error": "Failed to generate summary"})
+		return
+	}
+
+	summary, err = services.SaveSummary(r.Context(), summary)
+	if err != nil {
+		stream.send("error", map[string]string{"error": "Failed to save summary"})
+		return
+	}
+	stream.send("done", summary)
+}
+
+// tokenStream sends generated tokens as server-sent events. The response is
+// only started with the first event so that failures reaching the provider
+// can still be reported with a status code.
+type tokenStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+func newTokenStream(w http.ResponseWriter) (*tokenStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+	return &tokenStream{w: w, flusher: flusher}, true
+}
+
+func (s *tokenStream) token(token string) error {
+	return s.send("token", map[string]string{"token": token})
+}
+
+func (s *tokenStream) send(event string, data interface{}) error {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.Header().Set("Connection", "keep-alive")
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if err := writeSSE(s.w, event, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func summaryErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrTenantNotFound):
+		return http.StatusNotFound, "Tenant not found"
+	case errors.Is(err, services.ErrUnknownPromptStyle):
+		return http.StatusBadRequest, "Unknown summary style"
+	case errors.Is(err, services.ErrModelNotAllowed):
+		return http.StatusBadRequest, "Model not allowed"
+	case errors.Is(err, services.ErrProviderUnavailable):
+		return http.StatusServiceUnavailable, "Summary service unavailable"
+	case errors.Is(err, services.ErrProviderTimeout):
+		return http.StatusGatewayTimeout, "Summary generation timed out"
+	case errors.Is(err, services.ErrProviderBadResponse):
+		return http.StatusBadGateway, "Invalid response from summary service"
+	case errors.Is(err, services.ErrSummaryRejected):
+		return http.StatusBadGateway, "Summary rejected by output filter"
+	default:
+		return http.StatusInternalServerError, "Failed to generate summary"
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
 }
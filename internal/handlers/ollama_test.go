@@ -1,19 +1,23 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"student-api/internal/models"
 	"student-api/internal/services"
 	"testing"
+	"time"
 )
 
 func TestOllamaHandler_GenerateSummary(t *testing.T) {
 	setupTest()
 
 	// Create a test student
-	_ = services.CreateStudent(models.Student{
+	_, _ = services.CreateStudent(context.Background(), models.Student{
 		Name:  "Alice Johnson",
 		Age:   23,
 		Email: "alice@example.com",
@@ -75,20 +79,271 @@ func TestOllamaHandler_GenerateSummary(t *testing.T) {
 				if summary, exists := response["summary"]; !exists || summary == "" {
 					t.Error("Expected summary in response")
 				}
+				if strengths, ok := response["strengths"].([]interface{}); !ok || len(strengths) == 0 {
+					t.Error("Expected strengths in response")
+				}
+				if nextSteps, ok := response["next_steps"].([]interface{}); !ok || len(nextSteps) == 0 {
+					t.Error("Expected next_steps in response")
+				}
+			}
+		})
+	}
+}
+
+func TestOllamaHandler_GenerateSummaryUpstreamErrors(t *testing.T) {
+	setupTest()
+
+	_, _ = services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "Ollama unavailable",
+			err:            fmt.Errorf("%w: circuit breaker open", services.ErrProviderUnavailable),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Ollama timeout",
+			err:            fmt.Errorf("%w: deadline exceeded", services.ErrProviderTimeout),
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "Ollama bad response",
+			err:            fmt.Errorf("%w: unexpected status 404", services.ErrProviderBadResponse),
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "Summary rejected by output filter",
+			err:            fmt.Errorf("%w: contains an email address", services.ErrSummaryRejected),
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &OllamaHandler{
+				OllamaService: &MockOllamaService{Err: tt.err},
+			}
+
+			for _, url := range []string{"/students/1/summary", "/students/1/summary/stream"} {
+				req := httptest.NewRequest("GET", url, nil)
+				rr := httptest.NewRecorder()
+				if strings.HasSuffix(url, "/stream") {
+					handler.StreamSummary(rr, req)
+				} else {
+					handler.GenerateSummary(rr, req)
+				}
+
+				if rr.Code != tt.expectedStatus {
+					t.Errorf("%s: expected status %d, got %d", url, tt.expectedStatus, rr.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestOllamaHandler_GenerateSummaryCache(t *testing.T) {
+	setupTest()
+
+	_, _ = services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+
+	mockOllamaService := &MockOllamaService{}
+	handler := &OllamaHandler{
+		OllamaService: mockOllamaService,
+		SummaryCache:  services.NewSummaryCache(10, time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedCache  string
+		expectedCalls  int
+	}{
+		{
+			name:           "First request misses",
+			url:            "/students/1/summary",
+			expectedStatus: http.StatusOK,
+			expectedCache:  "MISS",
+			expectedCalls:  1,
+		},
+		{
+			name:           "Second request hits",
+			url:            "/students/1/summary",
+			expectedStatus: http.StatusOK,
+			expectedCache:  "HIT",
+			expectedCalls:  1,
+		},
+		{
+			name:           "Refresh bypasses the cache",
+			url:            "/students/1/summary?refresh=true",
+			expectedStatus: http.StatusOK,
+			expectedCache:  "REFRESH",
+			expectedCalls:  2,
+		},
+		{
+			name:           "Invalid refresh",
+			url:            "/students/1/summary?refresh=sometimes",
+			expectedStatus: http.StatusBadRequest,
+			expectedCalls:  2,
+		},
+		{
+			name:           "Different style misses cache",
+			url:            "/students/1/summary?style=brief",
+			expectedStatus: http.StatusOK,
+			expectedCache:  "MISS",
+			expectedCalls:  3,
+		},
+		{
+			name:           "Same style hits cache",
+			url:            "/students/1/summary?style=brief",
+			expectedStatus: http.StatusOK,
+			expectedCache:  "HIT",
+			expectedCalls:  3,
+		},
+		{
+			name:           "Unknown style",
+			url:            "/students/1/summary?style=haiku",
+			expectedStatus: http.StatusBadRequest,
+			expectedCalls:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.GenerateSummary(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if cacheStatus := rr.Header().Get("X-Cache"); cacheStatus != tt.expectedCache {
+				t.Errorf("Expected X-Cache %q, got %q", tt.expectedCache, cacheStatus)
+			}
+			if tt.expectedStatus == http.StatusOK && !strings.HasPrefix(rr.Header().Get("Cache-Control"), "private, max-age=") {
+				t.Errorf("Expected Cache-Control header, got %q", rr.Header().Get("Cache-Control"))
+			}
+			if mockOllamaService.Calls != tt.expectedCalls {
+				t.Errorf("Expected %d generations, got %d", tt.expectedCalls, mockOllamaService.Calls)
+			}
+			if saved := services.GetStudentSummaries(context.Background(), 1); len(saved) != tt.expectedCalls {
+				t.Errorf("Expected only the %d generated summaries in the history, got %d", tt.expectedCalls, len(saved))
 			}
 		})
 	}
+
+	services.UpdateStudent(context.Background(), 1, models.Student{Name: "Alice Johnson", Age: 24, Email: "alice@example.com"})
+	req := httptest.NewRequest("GET", "/students/1/summary", nil)
+	rr := httptest.NewRecorder()
+	handler.GenerateSummary(rr, req)
+	if rr.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected changed student to miss the cache, got %q", rr.Header().Get("X-Cache"))
+	}
 }
 
 type MockOllamaService struct {
 	ShouldError bool
+	Err         error
+	Calls       int
+	LastOptions services.SummaryOptions
+	// QueryFilter is the filter TranslateStudentQuery returns.
+	QueryFilter services.StudentFilter
+}
+
+func (m *MockOllamaService) Models() []string {
+	return []string{services.SummaryModel, "mistral"}
+}
+
+func (m *MockOllamaService) GenerateSummary(ctx context.Context, student models.Student, opts services.SummaryOptions) (models.Summary, error) {
+	m.Calls++
+	m.LastOptions = opts
+	if m.Err != nil {
+		return models.Summary{}, m.Err
+	}
+	if m.ShouldError {
+		return models.Summary{}, &mockError{message: "mock ollama error"}
+	}
+	summary := mockSummary(student, opts, "Mock summary for "+student.Name)
+	summary.Strengths = []string{"Consistent effort"}
+	summary.NextSteps = []string{"Meet with an advisor"}
+	return summary, nil
+}
+
+func (m *MockOllamaService) StreamSummary(ctx context.Context, student models.Student, opts services.SummaryOptions, onToken func(token string) error) (models.Summary, error) {
+	if m.Err != nil {
+		return models.Summary{}, m.Err
+	}
+	if m.ShouldError {
+		return models.Summary{}, &mockError{message: "mock ollama error"}
+	}
+	tokens := []string{"Mock ", "summary ", "for ", student.Name}
+	for _, token := range tokens {
+		if err := onToken(token); err != nil {
+			return models.Summary{}, err
+		}
+	}
+	return mockSummary(student, opts, strings.Join(tokens, "")), nil
 }
 
-func (m *MockOllamaService) GenerateSummary(student models.Student) (string, error) {
+func (m *MockOllamaService) StreamCohortReport(ctx context.Context, cohort models.Cohort, opts services.SummaryOptions, onToken func(token string) error) (models.CohortReport, error) {
+	m.Calls++
+	m.LastOptions = opts
+	if m.Err != nil {
+		return models.CohortReport{}, m.Err
+	}
 	if m.ShouldError {
-		return "", &mockError{message: "mock ollama error"}
+		return models.CohortReport{}, &mockError{message: "mock ollama error"}
+	}
+	tokens := []string{"## Overview\n\n", "Mock ", "report ", "for ", cohort.Title}
+	for _, token := range tokens {
+		if err := onToken(token); err != nil {
+			return models.CohortReport{}, err
+		}
+	}
+	return models.CohortReport{
+		Title:        cohort.Title,
+		StudentCount: cohort.Stats.Count,
+		Stats:        cohort.Stats,
+		Narrative:    strings.Join(tokens, ""),
+		Model:        opts.Model,
+		GeneratedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func (m *MockOllamaService) TranslateStudentQuery(ctx context.Context, question string, opts services.SummaryOptions) (services.StudentQuery, error) {
+	m.Calls++
+	m.LastOptions = opts
+	if m.Err != nil {
+		return services.StudentQuery{}, m.Err
+	}
+	if m.ShouldError {
+		return services.StudentQuery{}, &mockError{message: "mock ollama error"}
+	}
+	return services.StudentQuery{Question: question, Filter: m.QueryFilter, Model: opts.Model}, nil
+}
+
+func mockSummary(student models.Student, opts services.SummaryOptions, text string) models.Summary {
+	promptTemplate, _ := services.LookupPrompt(opts.Style)
+	return models.Summary{
+		StudentID:     student.ID,
+		Text:          text,
+		Model:         opts.Model,
+		Style:         promptTemplate.Style,
+		PromptVersion: promptTemplate.Version,
+		GeneratedAt:   time.Now().UTC(),
 	}
-	return "Mock summary for " + student.Name, nil
 }
 
 type mockError struct {
@@ -98,3 +353,160 @@ type mockError struct {
 func (e *mockError) Error() string {
 	return e.message
 }
+
+func TestOllamaHandler_StreamSummary(t *testing.T) {
+	setupTest()
+
+	_, _ = services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+
+	mockOllamaService := &MockOllamaService{}
+	handler := &OllamaHandler{
+		OllamaService: mockOllamaService,
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		mockError      bool
+		expectedEvent  string
+	}{
+		{
+			name:           "Valid summary stream",
+			url:            "/students/1/summary/stream",
+			expectedStatus: http.StatusOK,
+			expectedEvent:  "event: done\ndata: {\"id\":1,\"student_id\":1,\"summary\":\"Mock summary for Alice Johnson\"",
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/summary/stream",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID format",
+			url:            "/students/abc/summary/stream",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Ollama service error before streaming",
+			url:            "/students/1/summary/stream",
+			expectedStatus: http.StatusInternalServerError,
+			mockError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOllamaService.ShouldError = tt.mockError
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.StreamSummary(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			body := rr.Body.String()
+			if tt.expectedEvent != "" && !strings.Contains(body, tt.expectedEvent) {
+				t.Errorf("Expected body to contain %q, got %q", tt.expectedEvent, body)
+			}
+			if tt.expectedStatus == http.StatusOK && !tt.mockError {
+				if strings.Count(body, "event: token\n") != 4 {
+					t.Errorf("Expected 4 token events, got %q", body)
+				}
+				if rr.Header().Get("Content-Type") != "text/event-stream" {
+					t.Errorf("Expected text/event-stream, got %s", rr.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestOllamaHandler_ModelAndOptions(t *testing.T) {
+	setupTest()
+
+	_, _ = services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedModel  string
+	}{
+		{
+			name:           "Default model",
+			url:            "/students/1/summary",
+			expectedStatus: http.StatusOK,
+			expectedModel:  services.SummaryModel,
+		},
+		{
+			name:           "Allowed model with options",
+			url:            "/students/1/summary?model=mistral&temperature=0.3&num_predict=80&seed=5&stop=END&stop=%0A%0A",
+			expectedStatus: http.StatusOK,
+			expectedModel:  "mistral",
+		},
+		{
+			name:           "Model not in allowlist",
+			url:            "/students/1/summary?model=gpt-4",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid temperature",
+			url:            "/students/1/summary?temperature=hot",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid num_predict",
+			url:            "/students/1/summary?num_predict=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid seed",
+			url:            "/students/1/summary?seed=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &OllamaHandler{OllamaService: &MockOllamaService{}}
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.GenerateSummary(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var summary models.Summary
+				json.Unmarshal(rr.Body.Bytes(), &summary)
+				if summary.Model != tt.expectedModel {
+					t.Errorf("Expected model %s, got %s", tt.expectedModel, summary.Model)
+				}
+			}
+		})
+	}
+
+	mock := &MockOllamaService{}
+	handler := &OllamaHandler{OllamaService: mock}
+	req := httptest.NewRequest("POST", "/students/1/summaries?temperature=0.3&seed=5&stop=END&stop=STOP", nil)
+	handler.RegenerateSummary(httptest.NewRecorder(), req)
+
+	generation := mock.LastOptions.Generation
+	if generation.Temperature == nil || *generation.Temperature != 0.3 || generation.Seed == nil || *generation.Seed != 5 {
+		t.Errorf("Expected temperature and seed to be passed through, got %+v", generation)
+	}
+	if len(generation.Stop) != 2 || generation.NumPredict != nil {
+		t.Errorf("Expected two stop sequences and no num_predict, got %+v", generation)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+type cohortReportRequest struct {
+	Title  string                 `json:"title"`
+	Filter services.StudentFilter `json:"filter"`
+}
+
+// CreateCohortReport streams a generated report about the students matching
+// the request's filter as server-sent events, like StreamSummary, and saves
+// it once complete. The done event carries the saved report. Providers that
+// cannot write reports answer 501.
+func (h *OllamaHandler) CreateCohortReport(w http.ResponseWriter, r *http.Request) {
+	reporter, ok := h.OllamaService.(services.CohortReporter)
+	if !ok {
+		http.Error(w, "The summary provider cannot write cohort reports", http.StatusNotImplemented)
+		return
+	}
+
+	var request cohortReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateReportTitle(request.Title); err != nil {
+		http.Error(w, "Invalid report: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := request.Filter.Validate(); err != nil {
+		http.Error(w, "Invalid student filter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var opts services.SummaryOptions
+	if !h.parseModelOptions(w, r, &opts) {
+		return
+	}
+
+	cohort, err := services.BuildCohort(r.Context(), strings.TrimSpace(request.Title), request.Filter)
+	if errors.Is(err, services.ErrCohortEmpty) {
+		http.Error(w, "No students match the filter", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to build cohort", http.StatusInternalServerError)
+		return
+	}
+
+	stream, ok := newTokenStream(w)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := reporter.StreamCohortReport(r.Context(), cohort, opts, stream.token)
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		if !stream.started {
+			status, message := summaryErrorStatus(err)
+			http.Error(w, message, status)
+			return
+		}
+		stream.send("error", map[string]string{"error": "Failed to generate report"})
+		return
+	}
+
+	report, err = services.SaveCohortReport(r.Context(), request.Filter, report)
+	if err != nil {
+		stream.send("error", map[string]string{"error": "Failed to save report"})
+		return
+	}
+	stream.send("done", report)
+}
+
+func GetCohortReports(w http.ResponseWriter, r *http.Request) {
+	reports := services.ListCohortReports(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+func GetCohortReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/reports/cohort/"))
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	report, err := services.GetCohortReport(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// DownloadCohortReport serves a report as a Markdown document, or as HTML
+// with ?format=html.
+func DownloadCohortReport(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/reports/cohort/")
+	id, err := strconv.Atoi(strings.TrimSuffix(path, "/download"))
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	format := models.ReportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = models.ReportMarkdown
+	}
+	if !format.IsValid() {
+		http.Error(w, "Invalid format parameter; expected markdown or html", http.StatusBadRequest)
+		return
+	}
+
+	report, err := services.GetCohortReport(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	content, contentType, extension := services.CohortReportMarkdown(report), "text/markdown; charset=utf-8", "md"
+	if format == models.ReportHTML {
+		content, contentType, extension = services.CohortReportHTML(report), "text/html; charset=utf-8", "html"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"cohort-report-%d.%s\"", report.ID, extension))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write([]byte(content))
+}
+
+func DeleteCohortReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/reports/cohort/"))
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.DeleteCohortReport(r.Context(), id); err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestCreateCohortReport(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Young", Age: 18, Email: "young@school.edu"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Older", Age: 30, Email: "older@example.com"})
+
+	mockOllamaService := &MockOllamaService{}
+	handler := &OllamaHandler{OllamaService: mockOllamaService}
+
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		mockError      bool
+		expectedStatus int
+		expectedEvent  string
+	}{
+		{
+			name:           "Provider error before streaming",
+			url:            "/reports/cohort",
+			body:           `{}`,
+			mockError:      true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Valid report",
+			url:            "/reports/cohort?model=mistral",
+			body:           `{"title":"School students","filter":{"email_domain":"school.edu"}}`,
+			expectedStatus: http.StatusOK,
+			expectedEvent:  "event: done\ndata: {\"id\":1,\"title\":\"School students\",\"filter\":{\"email_domain\":\"school.edu\"},\"student_count\":1,",
+		},
+		{
+			name:           "No matching students",
+			url:            "/reports/cohort",
+			body:           `{"filter":{"min_age":90}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid filter",
+			url:            "/reports/cohort",
+			body:           `{"filter":{"status":["expelled"]}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Title too long",
+			url:            "/reports/cohort",
+			body:           `{"title":"` + strings.Repeat("x", 201) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Model not allowed",
+			url:            "/reports/cohort?model=gpt-4",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			url:            "/reports/cohort",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOllamaService.ShouldError = tt.mockError
+
+			req := httptest.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			handler.CreateCohortReport(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedEvent != "" && !strings.Contains(rr.Body.String(), tt.expectedEvent) {
+				t.Errorf("Expected body to contain %q, got %q", tt.expectedEvent, rr.Body.String())
+			}
+		})
+	}
+
+	if mockOllamaService.LastOptions.Model != "mistral" {
+		t.Errorf("Expected the requested model to be passed on, got %q", mockOllamaService.LastOptions.Model)
+	}
+	if reports := services.ListCohortReports(context.Background()); len(reports) != 1 {
+		t.Errorf("Expected only the streamed report to be saved, got %d", len(reports))
+	}
+}
+
+func TestCreateCohortReportUnsupported(t *testing.T) {
+	setupTest()
+
+	// Embedding the interface hides the mock's StreamCohortReport.
+	handler := &OllamaHandler{OllamaService: struct{ services.SummaryProvider }{&MockOllamaService{}}}
+	req := httptest.NewRequest("POST", "/reports/cohort", bytes.NewBufferString(`{}`))
+	rr := httptest.NewRecorder()
+	handler.CreateCohortReport(rr, req)
+
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501, got %d", rr.Code)
+	}
+}
+
+func TestCohortReportEndpoints(t *testing.T) {
+	setupTest()
+
+	cohort := models.Cohort{Title: "Seniors", Stats: models.StudentStats{Count: 2}}
+	services.SaveCohortReport(context.Background(), services.StudentFilter{}, models.CohortReport{Title: cohort.Title, StudentCount: 2, Narrative: "## Overview\n\nA <b>steady</b> cohort."})
+
+	rr := httptest.NewRecorder()
+	GetCohortReports(rr, httptest.NewRequest("GET", "/reports/cohort", nil))
+	var reports []models.CohortReport
+	json.Unmarshal(rr.Body.Bytes(), &reports)
+	if rr.Code != http.StatusOK || len(reports) != 1 || reports[0].Title != "Seniors" {
+		t.Errorf("Expected the saved report, got %d %+v", rr.Code, reports)
+	}
+
+	tests := []struct {
+		name                string
+		url                 string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "Markdown download",
+			url:                 "/reports/cohort/1/download",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedBody:        "# Seniors\n",
+		},
+		{
+			name:                "HTML download",
+			url:                 "/reports/cohort/1/download?format=html",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "<p>A &lt;b&gt;steady&lt;/b&gt; cohort.</p>",
+		},
+		{
+			name:           "Unknown format",
+			url:            "/reports/cohort/1/download?format=pdf",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Non-existent report",
+			url:            "/reports/cohort/99/download",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			DownloadCohortReport(rr, httptest.NewRequest("GET", tt.url, nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("Expected content type %q, got %q", tt.expectedContentType, contentType)
+			}
+			if !strings.HasPrefix(rr.Header().Get("Content-Disposition"), "attachment; filename=\"cohort-report-1.") {
+				t.Errorf("Expected an attachment, got %q", rr.Header().Get("Content-Disposition"))
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+
+	rr = httptest.NewRecorder()
+	DeleteCohortReport(rr, httptest.NewRequest("DELETE", "/reports/cohort/1", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	GetCohortReport(rr, httptest.NewRequest("GET", "/reports/cohort/1", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected a deleted report to be gone, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+// GetStudentStats aggregates the students matching the same filters as
+// GET /students. ?age_bucket= sets the histogram bucket width in years and
+// ?group_by= lists comma-separated fields to break the students down by.
+func GetStudentStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStudentFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid student filter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var options services.StatsOptions
+	if width := query.Get("age_bucket"); width != "" {
+		value, err := strconv.Atoi(width)
+		if err != nil || value <= 0 {
+			http.Error(w, "Invalid age_bucket parameter", http.StatusBadRequest)
+			return
+		}
+		options.AgeBucketWidth = value
+	}
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		for _, field := range strings.Split(groupBy, ",") {
+			options.GroupBy = append(options.GroupBy, strings.TrimSpace(field))
+		}
+	}
+
+	stats, err := services.GetStudentStats(r.Context(), filter, options)
+	if err != nil {
+		http.Error(w, capitalize(err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
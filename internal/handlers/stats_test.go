@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestGetStudentStats(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Young", Age: 18, Email: "young@school.edu"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Older", Age: 30, Email: "older@example.com", Status: models.StudentApplied})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "All students",
+			url:            "/students/stats",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Filtered and grouped",
+			url:            "/students/stats?status=applied&group_by=status,email&age_bucket=10",
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "Invalid age_bucket",
+			url:            "/students/stats?age_bucket=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid group_by",
+			url:            "/students/stats?group_by=contacts",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid filter",
+			url:            "/students/stats?min_age=old",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			GetStudentStats(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var stats models.StudentStats
+				json.Unmarshal(rr.Body.Bytes(), &stats)
+				if stats.Count != tt.expectedCount {
+					t.Errorf("Expected %d students, got %d", tt.expectedCount, stats.Count)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,12 +19,20 @@ func CreateStudent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if student.Name == "" || student.Email == "" || student.Age <= 0 {
-		http.Error(w, "Invalid student data", http.StatusBadRequest)
+	if err := validateStudent(r.Context(), &student); err != nil {
+		http.Error(w, "Invalid student data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if student.Status != "" && !student.Status.IsInitial() {
+		http.Error(w, "Invalid student data: a new student's status must be applied or enrolled", http.StatusBadRequest)
 		return
 	}
 
-	createdStudent := services.CreateStudent(student)
+	createdStudent, err := services.CreateStudent(r.Context(), student)
+	if err != nil {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -30,7 +40,13 @@ func CreateStudent(w http.ResponseWriter, r *http.Request) {
 }
 
 func GetAllStudents(w http.ResponseWriter, r *http.Request) {
-	students := services.GetAllStudents()
+	filter, err := parseStudentFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid student filter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	students := services.ListStudents(r.Context(), filter)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(students)
@@ -44,7 +60,7 @@ func GetStudentByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	student, err := services.GetStudentByID(id)
+	student, err := services.GetStudentByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Student not found", http.StatusNotFound)
 		return
@@ -68,12 +84,16 @@ func UpdateStudent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if student.Name == "" || student.Email == "" || student.Age <= 0 {
-		http.Error(w, "Invalid student data", http.StatusBadRequest)
+	if err := validateStudent(r.Context(), &student); err != nil {
+		http.Error(w, "Invalid student data: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	updatedStudent, err := services.UpdateStudent(id, student)
+	updatedStudent, err := services.UpdateStudent(r.Context(), id, student)
+	if errors.Is(err, services.ErrStatusChangeNotAllowed) {
+		http.Error(w, "Status can only be changed through a transition", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Student not found", http.StatusNotFound)
 		return
@@ -91,10 +111,131 @@ func DeleteStudent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := services.DeleteStudent(id); err != nil {
+	if err := services.DeleteStudent(r.Context(), id); err != nil {
 		http.Error(w, "Student not found", http.StatusNotFound)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func RestoreStudent(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/restore")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	student, err := services.RestoreStudent(r.Context(), id)
+	if errors.Is(err, services.ErrStudentNotDeleted) {
+		http.Error(w, "Student is not deleted", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(student)
+}
+
+// TransitionStudent moves a student through the lifecycle, e.g. from enrolled
+// to suspended, recording the reason given.
+func TransitionStudent(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	id, err := strconv.Atoi(strings.TrimSuffix(path, "/transitions"))
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.TransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		http.Error(w, "Invalid transition: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transition, err := services.TransitionStudent(r.Context(), id, req)
+	if errors.Is(err, services.ErrInvalidStudentTransition) {
+		http.Error(w, capitalize(err.Error()), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transition)
+}
+
+func GetStudentTransitions(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	id, err := strconv.Atoi(strings.TrimSuffix(path, "/transitions"))
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	transitions, err := services.GetStudentTransitions(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transitions)
+}
+
+// validateStudent checks the student's fields and its custom field values
+// against the admin-defined custom fields.
+func validateStudent(ctx context.Context, student *models.Student) error {
+	if err := student.Validate(); err != nil {
+		return err
+	}
+	return services.ValidateCustomFields(ctx, student.CustomFields)
+}
+
+func parseStudentFilter(r *http.Request) (services.StudentFilter, error) {
+	query := r.URL.Query()
+	var filter services.StudentFilter
+
+	if includeDeleted := query.Get("include_deleted"); includeDeleted != "" {
+		value, err := strconv.ParseBool(includeDeleted)
+		if err != nil {
+			return filter, errors.New("invalid include_deleted parameter")
+		}
+		filter.IncludeDeleted = value
+	}
+	if minAge := query.Get("min_age"); minAge != "" {
+		value, err := strconv.Atoi(minAge)
+		if err != nil {
+			return filter, errors.New("invalid min_age parameter")
+		}
+		filter.MinAge = value
+	}
+	if maxAge := query.Get("max_age"); maxAge != "" {
+		value, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return filter, errors.New("invalid max_age parameter")
+		}
+		filter.MaxAge = value
+	}
+	filter.EmailDomain = query.Get("email_domain")
+	if status := query.Get("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			filter.Status = append(filter.Status, models.StudentStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	return filter, filter.Validate()
+}
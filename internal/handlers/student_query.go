@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+type studentQueryRequest struct {
+	Question string `json:"question"`
+}
+
+type studentQueryResponse struct {
+	services.StudentQuery
+	Count    int              `json:"count"`
+	Students []models.Student `json:"students"`
+}
+
+// AskStudents answers a natural-language question about students. The
+// summary provider translates the question into a validated StudentFilter,
+// which is run like the filters of GET /students; the response carries
+// the filter alongside the matching students so callers can see how the
+// question was understood. Providers that cannot translate questions answer
+// 501.
+func (h *OllamaHandler) AskStudents(w http.ResponseWriter, r *http.Request) {
+	translator, ok := h.OllamaService.(services.StudentQueryTranslator)
+	if !ok {
+		http.Error(w, "The summary provider cannot answer questions", http.StatusNotImplemented)
+		return
+	}
+
+	var request studentQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := services.ValidateStudentQuestion(request.Question); err != nil {
+		http.Error(w, "Invalid question: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var opts services.SummaryOptions
+	if !h.parseModelOptions(w, r, &opts) {
+		return
+	}
+
+	query, err := translator.TranslateStudentQuery(r.Context(), request.Question, opts)
+	if err != nil {
+		status, message := summaryErrorStatus(err)
+		http.Error(w, message, status)
+		return
+	}
+
+	students := services.ListStudents(r.Context(), query.Filter)
+	sort.Slice(students, func(i, j int) bool { return students[i].ID < students[j].ID })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(studentQueryResponse{
+		StudentQuery: query,
+		Count:        len(students),
+		Students:     students,
+	})
+}
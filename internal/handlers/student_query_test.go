@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestAskStudents(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Young", Age: 18, Email: "young@gmail.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Older", Age: 25, Email: "older@gmail.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Staff", Age: 40, Email: "staff@school.edu"})
+
+	tests := []struct {
+		name             string
+		url              string
+		body             string
+		service          *MockOllamaService
+		expectedStatus   int
+		expectedStudents []string
+	}{
+		{
+			name:             "Valid question",
+			url:              "/students/ask?model=mistral",
+			body:             `{"question":"students over 20 with gmail addresses"}`,
+			service:          &MockOllamaService{QueryFilter: services.StudentFilter{MinAge: 21, EmailDomain: "gmail.com"}},
+			expectedStatus:   http.StatusOK,
+			expectedStudents: []string{"Older"},
+		},
+		{
+			name:             "Question without criteria",
+			url:              "/students/ask",
+			body:             `{"question":"all students"}`,
+			service:          &MockOllamaService{},
+			expectedStatus:   http.StatusOK,
+			expectedStudents: []string{"Young", "Older", "Staff"},
+		},
+		{
+			name:           "Invalid generated filter",
+			url:            "/students/ask",
+			body:           `{"question":"expelled students"}`,
+			service:        &MockOllamaService{Err: services.ErrProviderBadResponse},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "Provider unavailable",
+			url:            "/students/ask",
+			body:           `{"question":"all students"}`,
+			service:        &MockOllamaService{Err: services.ErrProviderUnavailable},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Blank question",
+			url:            "/students/ask",
+			body:           `{"question":" "}`,
+			service:        &MockOllamaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Question too long",
+			url:            "/students/ask",
+			body:           `{"question":"` + strings.Repeat("a", services.MaxStudentQuestionLength+1) + `"}`,
+			service:        &MockOllamaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Model not allowed",
+			url:            "/students/ask?model=gpt-4",
+			body:           `{"question":"all students"}`,
+			service:        &MockOllamaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			url:            "/students/ask",
+			body:           `{`,
+			service:        &MockOllamaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &OllamaHandler{OllamaService: tt.service}
+
+			req := httptest.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			handler.AskStudents(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Question string                 `json:"question"`
+				Filter   services.StudentFilter `json:"filter"`
+				Model    string                 `json:"model"`
+				Count    int                    `json:"count"`
+				Students []models.Student       `json:"students"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var names []string
+			for _, student := range response.Students {
+				names = append(names, student.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedStudents, ",") || response.Count != len(tt.expectedStudents) {
+				t.Errorf("Expected students %v, got %v (count %d)", tt.expectedStudents, names, response.Count)
+			}
+			if response.Filter.MinAge != tt.service.QueryFilter.MinAge || response.Filter.EmailDomain != tt.service.QueryFilter.EmailDomain {
+				t.Errorf("Expected the interpreted filter %+v, got %+v", tt.service.QueryFilter, response.Filter)
+			}
+			if response.Question == "" || response.Model != tt.service.LastOptions.Model {
+				t.Errorf("Expected the question and model in the response, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestAskStudentsUnsupported(t *testing.T) {
+	setupTest()
+
+	// Embedding the interface hides the mock's TranslateStudentQuery.
+	handler := &OllamaHandler{OllamaService: struct{ services.SummaryProvider }{&MockOllamaService{}}}
+	req := httptest.NewRequest("POST", "/students/ask", bytes.NewBufferString(`{"question":"all students"}`))
+	rr := httptest.NewRecorder()
+	handler.AskStudents(rr, req)
+
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501, got %d", rr.Code)
+	}
+}
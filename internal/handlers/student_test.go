@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -11,7 +12,17 @@ import (
 )
 
 func setupTest() {
+	services.ResetTenants()
 	services.ResetStudents()
+	services.ResetAuditLog()
+	services.ResetSummaries()
+	services.ResetCohortReports()
+	services.ResetSummaryJobs()
+	services.ResetCourses()
+	services.ResetGrades()
+	services.ResetAttendance()
+	services.ResetCustomFields()
+	services.ResetAttachments()
 }
 
 func TestCreateStudent(t *testing.T) {
@@ -109,7 +120,7 @@ func TestGetAllStudents(t *testing.T) {
 		t.Errorf("Expected empty list, got %d students", len(students))
 	}
 
-	services.CreateStudent(models.Student{Name: "Test", Age: 25, Email: "test@example.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Test", Age: 25, Email: "test@example.com"})
 
 	req = httptest.NewRequest("GET", "/students", nil)
 	rr = httptest.NewRecorder()
@@ -124,7 +135,7 @@ func TestGetAllStudents(t *testing.T) {
 func TestGetStudentByID(t *testing.T) {
 	setupTest()
 
-	_ = services.CreateStudent(models.Student{
+	_, _ = services.CreateStudent(context.Background(), models.Student{
 		Name:  "Jane Doe",
 		Age:   22,
 		Email: "jane@example.com",
@@ -178,7 +189,7 @@ func TestGetStudentByID(t *testing.T) {
 func TestUpdateStudent(t *testing.T) {
 	setupTest()
 
-	services.CreateStudent(models.Student{
+	services.CreateStudent(context.Background(), models.Student{
 		Name:  "Original Name",
 		Age:   20,
 		Email: "original@example.com",
@@ -257,7 +268,7 @@ func TestUpdateStudent(t *testing.T) {
 func TestDeleteStudent(t *testing.T) {
 	setupTest()
 
-	services.CreateStudent(models.Student{
+	services.CreateStudent(context.Background(), models.Student{
 		Name:  "To Be Deleted",
 		Age:   20,
 		Email: "delete@example.com",
@@ -297,3 +308,291 @@ func TestDeleteStudent(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllStudentsIncludeDeleted(t *testing.T) {
+	setupTest()
+
+	student, _ := services.CreateStudent(context.Background(), models.Student{Name: "Deleted", Age: 20, Email: "deleted@example.com"})
+	services.DeleteStudent(context.Background(), student.ID)
+
+	tests := []struct {
+		name             string
+		url              string
+		expectedStatus   int
+		expectedStudents int
+	}{
+		{
+			name:             "Deleted students hidden by default",
+			url:              "/students",
+			expectedStatus:   http.StatusOK,
+			expectedStudents: 0,
+		},
+		{
+			name:             "Deleted students included",
+			url:              "/students?include_deleted=true",
+			expectedStatus:   http.StatusOK,
+			expectedStudents: 1,
+		},
+		{
+			name:           "Invalid include_deleted",
+			url:            "/students?include_deleted=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			GetAllStudents(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var students []models.Student
+				json.Unmarshal(rr.Body.Bytes(), &students)
+				if len(students) != tt.expectedStudents {
+					t.Errorf("Expected %d students, got %d", tt.expectedStudents, len(students))
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllStudentsFiltered(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Young", Age: 18, Email: "young@school.edu"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Older", Age: 30, Email: "older@example.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Middle", Age: 24, Email: "middle@SCHOOL.edu"})
+
+	tests := []struct {
+		name             string
+		url              string
+		expectedStatus   int
+		expectedStudents int
+	}{
+		{
+			name:             "Minimum age",
+			url:              "/students?min_age=20",
+			expectedStatus:   http.StatusOK,
+			expectedStudents: 2,
+		},
+		{
+			name:             "Age range",
+			url:              "/students?min_age=20&max_age=25",
+			expectedStatus:   http.StatusOK,
+			expectedStudents: 1,
+		},
+		{
+			name:             "Email domain",
+			url:              "/students?email_domain=school.edu",
+			expectedStatus:   http.StatusOK,
+			expectedStudents: 2,
+		},
+		{
+			name:           "Invalid min_age",
+			url:            "/students?min_age=old",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Inverted age range",
+			url:            "/students?min_age=30&max_age=20",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Negative age",
+			url:            "/students?max_age=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			GetAllStudents(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var students []models.Student
+				json.Unmarshal(rr.Body.Bytes(), &students)
+				if len(students) != tt.expectedStudents {
+					t.Errorf("Expected %d students, got %d", tt.expectedStudents, len(students))
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreStudent(t *testing.T) {
+	setupTest()
+
+	student, _ := services.CreateStudent(context.Background(), models.Student{Name: "Restored", Age: 20, Email: "restored@example.com"})
+	services.DeleteStudent(context.Background(), student.ID)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{
+			name:           "Restore deleted student",
+			url:            "/students/1/restore",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Restore student that is not deleted",
+			url:            "/students/1/restore",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/restore",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID format",
+			url:            "/students/abc/restore",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, nil)
+			rr := httptest.NewRecorder()
+			RestoreStudent(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestTransitionStudent(t *testing.T) {
+	setupTest()
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Applicant", Age: 18, Email: "applicant@example.com", Status: models.StudentApplied})
+
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "Enroll applicant",
+			url:            "/students/1/transitions",
+			body:           `{"status":"enrolled","reason":"Accepted"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid transition",
+			url:            "/students/1/transitions",
+			body:           `{"status":"applied","reason":"Reapplied"}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Missing reason",
+			url:            "/students/1/transitions",
+			body:           `{"status":"suspended"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown status",
+			url:            "/students/1/transitions",
+			body:           `{"status":"expelled","reason":"Misconduct"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/transitions",
+			body:           `{"status":"enrolled","reason":"Accepted"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID format",
+			url:            "/students/abc/transitions",
+			body:           `{"status":"enrolled","reason":"Accepted"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			TransitionStudent(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/students/1/transitions", nil)
+	rr := httptest.NewRecorder()
+	GetStudentTransitions(rr, req)
+
+	var transitions []models.StatusTransition
+	json.Unmarshal(rr.Body.Bytes(), &transitions)
+	if rr.Code != http.StatusOK || len(transitions) != 1 || transitions[0].From != models.StudentApplied || transitions[0].To != models.StudentEnrolled {
+		t.Errorf("Expected one applied to enrolled transition, got %d %+v", rr.Code, transitions)
+	}
+}
+
+func TestStudentStatusRules(t *testing.T) {
+	setupTest()
+
+	req := httptest.NewRequest("POST", "/students", bytes.NewBufferString(`{"name":"Alumna","age":25,"email":"alumna@example.com","status":"graduated"}`))
+	rr := httptest.NewRecorder()
+	CreateStudent(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected a new graduated student to be rejected, got %d", rr.Code)
+	}
+
+	services.CreateStudent(context.Background(), models.Student{Name: "Current", Age: 20, Email: "current@example.com"})
+	services.CreateStudent(context.Background(), models.Student{Name: "Applicant", Age: 18, Email: "applicant@example.com", Status: models.StudentApplied})
+
+	req = httptest.NewRequest("PUT", "/students/1", bytes.NewBufferString(`{"name":"Current","age":20,"email":"current@example.com","status":"graduated"}`))
+	rr = httptest.NewRecorder()
+	UpdateStudent(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected a status change through update to be rejected, got %d", rr.Code)
+	}
+
+	tests := []struct {
+		url              string
+		expectedStatus   int
+		expectedStudents int
+	}{
+		{url: "/students?status=applied", expectedStatus: http.StatusOK, expectedStudents: 1},
+		{url: "/students?status=applied,enrolled", expectedStatus: http.StatusOK, expectedStudents: 2},
+		{url: "/students?status=expelled", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		rr := httptest.NewRecorder()
+		GetAllStudents(rr, req)
+
+		if rr.Code != tt.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.url, tt.expectedStatus, rr.Code)
+			continue
+		}
+		if tt.expectedStatus == http.StatusOK {
+			var students []models.Student
+			json.Unmarshal(rr.Body.Bytes(), &students)
+			if len(students) != tt.expectedStudents {
+				t.Errorf("%s: expected %d students, got %d", tt.url, tt.expectedStudents, len(students))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-api/internal/services"
+)
+
+func GetStudentSummaries(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	idStr := strings.TrimSuffix(path, "/summaries")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := services.GetStudentByID(r.Context(), id); err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	summaries := services.GetStudentSummaries(r.Context(), id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+func PinStudentSummary(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/students/")
+	parts := strings.Split(strings.TrimSuffix(path, "/pin"), "/")
+	if len(parts) != 3 || parts[1] != "summaries" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	summaryID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		http.Error(w, "Invalid summary ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := services.GetStudentByID(r.Context(), id); err != nil {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+
+	summary, err := services.PinSummary(r.Context(), id, summaryID)
+	if err != nil {
+		http.Error(w, "Summary not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
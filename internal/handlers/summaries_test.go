@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestRegenerateAndListSummaries(t *testing.T) {
+	setupTest()
+
+	_, _ = services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+
+	handler := &OllamaHandler{
+		OllamaService: &MockOllamaService{},
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{
+			name:           "Regenerate summary",
+			url:            "/students/1/summaries",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Regenerate again",
+			url:            "/students/1/summaries",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/summaries",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID format",
+			url:            "/students/abc/summaries",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.RegenerateSummary(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var summary models.Summary
+				json.Unmarshal(rr.Body.Bytes(), &summary)
+				if summary.ID == 0 || summary.Model == "" || summary.PromptVersion == "" || summary.GeneratedAt.IsZero() {
+					t.Errorf("Expected stored summary with metadata, got %+v", summary)
+				}
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/students/1/summary", nil)
+	handler.GenerateSummary(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest("GET", "/students/1/summaries", nil)
+	rr := httptest.NewRecorder()
+	GetStudentSummaries(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+
+	var summaries []models.Summary
+	json.Unmarshal(rr.Body.Bytes(), &summaries)
+	if len(summaries) != 3 {
+		t.Errorf("Expected 3 stored summaries, got %d", len(summaries))
+	}
+
+	req = httptest.NewRequest("GET", "/students/999/summaries", nil)
+	rr = httptest.NewRecorder()
+	GetStudentSummaries(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+}
+
+func TestPinStudentSummary(t *testing.T) {
+	setupTest()
+
+	student, _ := services.CreateStudent(context.Background(), models.Student{
+		Name:  "Alice Johnson",
+		Age:   23,
+		Email: "alice@example.com",
+	})
+	services.SaveSummary(context.Background(), models.Summary{StudentID: student.ID, Text: "Alice is diligent."})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{
+			name:           "Pin summary",
+			url:            "/students/1/summaries/1/pin",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Non-existent summary",
+			url:            "/students/1/summaries/999/pin",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Non-existent student",
+			url:            "/students/999/summaries/1/pin",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid summary ID",
+			url:            "/students/1/summaries/abc/pin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid student ID",
+			url:            "/students/abc/summaries/1/pin",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, nil)
+			rr := httptest.NewRecorder()
+			PinStudentSummary(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var summary models.Summary
+				json.Unmarshal(rr.Body.Bytes(), &summary)
+				if !summary.Pinned {
+					t.Error("Expected summary to be pinned")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"student-api/internal/models"
+	"student-api/internal/services"
+)
+
+// TenantHandler serves the tenant admin endpoints. Requests must present
+// AdminToken as a bearer token; with no AdminToken the endpoints are
+// disabled.
+type TenantHandler struct {
+	AdminToken string
+}
+
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var tenant models.Tenant
+	if err := json.NewDecoder(r.Body).Decode(&tenant); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := tenant.Validate(); err != nil {
+		http.Error(w, "Invalid tenant: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := services.CreateTenant(tenant)
+	if errors.Is(err, services.ErrTenantExists) {
+		http.Error(w, "Tenant already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to create tenant", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services.ListTenants())
+}
+
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	tenant, err := services.GetTenant(strings.TrimPrefix(r.URL.Path, "/admin/tenants/"))
+	if err != nil {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tenant)
+}
+
+func (h *TenantHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	err := services.DeleteTenant(strings.TrimPrefix(r.URL.Path, "/admin/tenants/"))
+	if errors.Is(err, services.ErrDefaultTenant) {
+		http.Error(w, "The default tenant cannot be deleted", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// IssueTenantToken creates a bearer token for the tenant. The token is only
+// returned once.
+func (h *TenantHandler) IssueTenantToken(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	token, err := services.IssueTenantToken(tenantTokensID(r))
+	if errors.Is(err, services.ErrTenantNotFound) {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
+
+func (h *TenantHandler) RevokeTenantTokens(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	if err := services.RevokeTenantTokens(tenantTokensID(r)); err != nil {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TenantHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	return authorizeAdmin(w, r, h.AdminToken)
+}
+
+// authorizeAdmin checks that a request to an admin endpoint presents
+// adminToken as a bearer token, and writes an error response and returns
+// false when it does not. An empty adminToken disables the endpoint rather
+// than leaving it open.
+func authorizeAdmin(w http.ResponseWriter, r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		http.Error(w, "Admin endpoints are disabled; set TENANT_ADMIN_TOKEN to enable them", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		http.Error(w, "Admin token required", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func tenantTokensID(r *http.Request) string {
+	path := strings.TrimPrefix(r.URL.Path, "/admin/tenants/")
+	return strings.TrimSuffix(path, "/tokens")
+}
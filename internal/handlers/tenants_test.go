@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"student-api/internal/middleware"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestTenantHandlers(t *testing.T) {
+	setupTest()
+	h := &TenantHandler{AdminToken: "secret"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"create tenant", "POST", "/admin/tenants", `{"id":"north","name":"North School"}`, h.CreateTenant, http.StatusCreated},
+		{"duplicate tenant", "POST", "/admin/tenants", `{"id":"north","name":"North School"}`, h.CreateTenant, http.StatusConflict},
+		{"invalid tenant", "POST", "/admin/tenants", `{"id":"North","name":"North School"}`, h.CreateTenant, http.StatusBadRequest},
+		{"list tenants", "GET", "/admin/tenants", "", h.ListTenants, http.StatusOK},
+		{"get tenant", "GET", "/admin/tenants/north", "", h.GetTenant, http.StatusOK},
+		{"get missing tenant", "GET", "/admin/tenants/south", "", h.GetTenant, http.StatusNotFound},
+		{"issue token", "POST", "/admin/tenants/north/tokens", "", h.IssueTenantToken, http.StatusCreated},
+		{"issue token for missing tenant", "POST", "/admin/tenants/south/tokens", "", h.IssueTenantToken, http.StatusNotFound},
+		{"revoke tokens", "DELETE", "/admin/tenants/north/tokens", "", h.RevokeTenantTokens, http.StatusNoContent},
+		{"delete default tenant", "DELETE", "/admin/tenants/default", "", h.DeleteTenant, http.StatusConflict},
+		{"delete tenant", "DELETE", "/admin/tenants/north", "", h.DeleteTenant, http.StatusNoContent},
+		{"delete missing tenant", "DELETE", "/admin/tenants/north", "", h.DeleteTenant, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenantHandlerAdminToken(t *testing.T) {
+	setupTest()
+	h := &TenantHandler{AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/admin/tenants", nil)
+	rr := httptest.NewRecorder()
+	h.ListTenants(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without the admin token, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/admin/tenants", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	h.ListTenants(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d with the admin token, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestTenantHandlerNoAdminToken(t *testing.T) {
+	setupTest()
+	h := &TenantHandler{}
+
+	for _, handler := range []http.HandlerFunc{h.ListTenants, h.CreateTenant, h.IssueTenantToken} {
+		req := httptest.NewRequest("POST", "/admin/tenants/default/tokens", strings.NewReader(`{"id":"north","name":"North School"}`))
+		req.Header.Set("Authorization", "Bearer ")
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status %d without a configured admin token, got %d", http.StatusForbidden, rr.Code)
+		}
+	}
+	if len(services.ListTenants()) != 1 {
+		t.Error("Expected no tenant to be created without a configured admin token")
+	}
+}
+
+func TestTenantIsolationThroughHandlers(t *testing.T) {
+	setupTest()
+	h := &TenantHandler{AdminToken: "secret"}
+
+	req := httptest.NewRequest("POST", "/admin/tenants", strings.NewReader(`{"id":"north","name":"North School"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	h.CreateTenant(rr, req)
+	req = httptest.NewRequest("POST", "/admin/tenants/north/tokens", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	h.IssueTenantToken(rr, req)
+	var issued map[string]string
+	json.NewDecoder(rr.Body).Decode(&issued)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/students", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			CreateStudent(w, r)
+		} else {
+			GetAllStudents(w, r)
+		}
+	})
+	mux.HandleFunc("/students/", GetStudentByID)
+	handler := middleware.TenantMiddleware(middleware.TenantOptions{Header: "X-Tenant-ID"}, mux)
+
+	req = httptest.NewRequest("POST", "/students", strings.NewReader(`{"name":"Alice","age":20,"email":"alice@north.edu"}`))
+	req.Header.Set("Authorization", "Bearer "+issued["token"])
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/students/1", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected north's student to be invisible to the default tenant, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/students", nil)
+	req.Header.Set("X-Tenant-ID", "north")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the tenant header alone to be rejected, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/students", nil)
+	req.Header.Set("Authorization", "Bearer "+issued["token"])
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	var students []models.Student
+	json.NewDecoder(rr.Body).Decode(&students)
+	if len(students) != 1 || students[0].Name != "Alice" {
+		t.Errorf("Expected north to list Alice, got %+v", students)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"student-api/internal/models"
+	"student-api/internal/services"
+	"testing"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	services.ResetWebhooks()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "Valid webhook",
+			requestBody:    models.Webhook{URL: "https://lms.example.com/hooks", Events: []models.StudentEventType{models.StudentCreated}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid URL",
+			requestBody:    models.Webhook{URL: "lms.example.com"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown event type",
+			requestBody:    models.Webhook{URL: "https://lms.example.com/hooks", Events: []models.StudentEventType{"student.expelled"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			requestBody:    "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/webhooks", bytes.NewBuffer(jsonData))
+			rr := httptest.NewRecorder()
+			CreateWebhook(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var webhook models.Webhook
+				json.Unmarshal(rr.Body.Bytes(), &webhook)
+				if webhook.ID != 1 || webhook.Secret == "" {
+					t.Errorf("Expected ID 1 and the signing secret, got %+v", webhook)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookEndpoints(t *testing.T) {
+	services.ResetWebhooks()
+	services.RegisterWebhook(context.Background(), models.Webhook{URL: "https://billing.example.com/hooks"})
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{"List webhooks", "GET", "/webhooks", GetAllWebhooks, http.StatusOK},
+		{"Get webhook", "GET", "/webhooks/1", GetWebhookByID, http.StatusOK},
+		{"Get non-existent webhook", "GET", "/webhooks/999", GetWebhookByID, http.StatusNotFound},
+		{"Get webhook invalid ID", "GET", "/webhooks/abc", GetWebhookByID, http.StatusBadRequest},
+		{"Get deliveries", "GET", "/webhooks/1/deliveries", GetWebhookDeliveries, http.StatusOK},
+		{"Get deliveries non-existent webhook", "GET", "/webhooks/999/deliveries", GetWebhookDeliveries, http.StatusNotFound},
+		{"Get dead letters", "GET", "/webhooks/dead-letters", GetWebhookDeadLetters, http.StatusOK},
+		{"Delete webhook", "DELETE", "/webhooks/1", DeleteWebhook, http.StatusNoContent},
+		{"Delete non-existent webhook", "DELETE", "/webhooks/1", DeleteWebhook, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/webhooks", nil)
+	rr := httptest.NewRecorder()
+	services.RegisterWebhook(context.Background(), models.Webhook{URL: "https://lms.example.com/hooks"})
+	GetAllWebhooks(rr, req)
+
+	var webhooks []models.Webhook
+	json.Unmarshal(rr.Body.Bytes(), &webhooks)
+	if len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Errorf("Expected one webhook with a redacted secret, got %+v", webhooks)
+	}
+}
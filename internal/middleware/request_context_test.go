@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"student-api/internal/reqctx"
+)
+
+func TestRequestContextMiddleware(t *testing.T) {
+	var gotActor, gotRequestID string
+	handler := RequestContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = reqctx.Actor(r.Context())
+		gotRequestID = reqctx.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/students", nil)
+	req.Header.Set(ActorHeader, "registrar")
+	req.Header.Set(RequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if gotActor != "registrar" {
+		t.Errorf("Expected actor registrar, got %s", gotActor)
+	}
+	if gotRequestID != "req-1" {
+		t.Errorf("Expected request ID req-1, got %s", gotRequestID)
+	}
+	if rr.Header().Get(RequestIDHeader) != "req-1" {
+		t.Errorf("Expected response request ID req-1, got %s", rr.Header().Get(RequestIDHeader))
+	}
+
+	req = httptest.NewRequest("GET", "/students", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if gotActor != reqctx.DefaultActor {
+		t.Errorf("Expected default actor, got %s", gotActor)
+	}
+	if gotRequestID == "" || rr.Header().Get(RequestIDHeader) != gotRequestID {
+		t.Error("Expected generated request ID to be set on context and response")
+	}
+}
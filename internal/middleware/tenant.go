@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"student-api/internal/reqctx"
+	"student-api/internal/services"
+)
+
+// serverAdminPaths are the admin endpoints that act on the whole server
+// rather than on a tenant. They authenticate with the admin token, which is
+// sent as a bearer token and so must not be taken for a tenant token.
+var serverAdminPaths = []string{"/admin/tenants", "/admin/models"}
+
+// The identities recorded as the actor of requests authenticated with the
+// admin token or with a tenant's token.
+const (
+	adminIdentity        = "admin"
+	tenantIdentityPrefix = "tenant:"
+)
+
+// TenantOptions controls how requests are assigned to tenants. Header names
+// the request header carrying a tenant ID and is ignored when empty. The
+// header alone only selects a tenant when TrustHeader is set, for servers
+// behind a proxy that authenticates callers and sets it. When Required is
+// set, requests naming no tenant are rejected instead of being served from
+// the default tenant. A request presenting AdminToken as its bearer token
+// acts for the tenant named by the header, or the default tenant, so admins
+// can reach tenant-scoped admin endpoints such as custom fields.
+type TenantOptions struct {
+	Header      string
+	TrustHeader bool
+	Required    bool
+	AdminToken  string
+}
+
+// TenantMiddleware resolves the tenant of every request outside the server
+// admin endpoints, and records the identity of a token as the actor. The admin token lets the tenant header select any
+// existing tenant. A bearer token issued for a tenant takes precedence over
+// the tenant header, which must then name the same tenant. A tenant with
+// issued tokens can only be selected with one of them, even when the header
+// is trusted.
+func TenantMiddleware(options TenantOptions, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isServerAdminPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var headerTenant string
+		if options.Header != "" {
+			headerTenant = r.Header.Get(options.Header)
+		}
+
+		var tenant, identity string
+		if token, ok := bearerToken(r); ok && isAdminToken(options, token) {
+			identity = adminIdentity
+			tenant = reqctx.DefaultTenant
+			if headerTenant != "" {
+				if !services.TenantExists(headerTenant) {
+					http.Error(w, "Tenant not found", http.StatusNotFound)
+					return
+				}
+				tenant = headerTenant
+			}
+		} else if ok {
+			resolved, err := services.ResolveTenantToken(token)
+			if err != nil {
+				http.Error(w, "Invalid tenant token", http.StatusUnauthorized)
+				return
+			}
+			if headerTenant != "" && headerTenant != resolved {
+				http.Error(w, "Tenant header does not match the token", http.StatusForbidden)
+				return
+			}
+			identity = tenantIdentityPrefix + resolved
+			tenant = resolved
+		} else if headerTenant != "" {
+			if !options.TrustHeader {
+				http.Error(w, "Tenant token required", http.StatusUnauthorized)
+				return
+			}
+			if !services.TenantExists(headerTenant) {
+				http.Error(w, "Tenant not found", http.StatusNotFound)
+				return
+			}
+			if services.TenantHasTokens(headerTenant) {
+				http.Error(w, "Tenant token required", http.StatusUnauthorized)
+				return
+			}
+			tenant = headerTenant
+		} else if options.Required {
+			http.Error(w, "Tenant required", http.StatusUnauthorized)
+			return
+		} else {
+			tenant = reqctx.DefaultTenant
+		}
+
+		ctx := reqctx.WithTenant(r.Context(), tenant)
+		if identity != "" {
+			ctx = reqctx.WithActor(ctx, authenticatedActor(identity, reqctx.Actor(ctx)))
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticatedActor returns the actor of a request authenticated as
+// identity. The X-Actor header cannot be verified, so it only names the
+// person behind the identity, as in "tenant:north/registrar".
+func authenticatedActor(identity, claimed string) string {
+	if claimed == reqctx.DefaultActor {
+		return identity
+	}
+	return identity + "/" + claimed
+}
+
+func isAdminToken(options TenantOptions, token string) bool {
+	return options.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(options.AdminToken)) == 1
+}
+
+func isServerAdminPath(path string) bool {
+	for _, prefix := range serverAdminPaths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"student-api/internal/services"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	services.ResetTenants()
+	for _, id := range []string{"north", "south"} {
+		if _, err := services.CreateTenant(models.Tenant{ID: id, Name: id}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	token, err := services.IssueTenantToken("north")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var gotTenant string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTenant = reqctx.Tenant(r.Context())
+	})
+	handler := TenantMiddleware(TenantOptions{Header: "X-Tenant-ID", AdminToken: "admin-secret"}, next)
+	trustingHandler := TenantMiddleware(TenantOptions{Header: "X-Tenant-ID", TrustHeader: true}, next)
+
+	tests := []struct {
+		name           string
+		header         string
+		token          string
+		trustHeader    bool
+		expectedStatus int
+		expectedTenant string
+	}{
+		{name: "no tenant", expectedStatus: http.StatusOK, expectedTenant: reqctx.DefaultTenant},
+		{name: "untrusted header", header: "south", expectedStatus: http.StatusUnauthorized},
+		{name: "trusted header", header: "south", trustHeader: true, expectedStatus: http.StatusOK, expectedTenant: "south"},
+		{name: "trusted header for a tenant with tokens", header: "north", trustHeader: true, expectedStatus: http.StatusUnauthorized},
+		{name: "unknown header", header: "west", trustHeader: true, expectedStatus: http.StatusNotFound},
+		{name: "token", token: token, expectedStatus: http.StatusOK, expectedTenant: "north"},
+		{name: "token and matching header", token: token, header: "north", expectedStatus: http.StatusOK, expectedTenant: "north"},
+		{name: "token and other header", token: token, header: "south", expectedStatus: http.StatusForbidden},
+		{name: "invalid token", token: "invalid", expectedStatus: http.StatusUnauthorized},
+		{name: "admin token", token: "admin-secret", expectedStatus: http.StatusOK, expectedTenant: reqctx.DefaultTenant},
+		{name: "admin token and header", token: "admin-secret", header: "north", expectedStatus: http.StatusOK, expectedTenant: "north"},
+		{name: "admin token and unknown header", token: "admin-secret", header: "west", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTenant = ""
+			handler := handler
+			if tt.trustHeader {
+				handler = trustingHandler
+			}
+			req := httptest.NewRequest("GET", "/students", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if gotTenant != tt.expectedTenant {
+				t.Errorf("Expected tenant %q, got %q", tt.expectedTenant, gotTenant)
+			}
+		})
+	}
+}
+
+func TestTenantMiddlewareRequired(t *testing.T) {
+	services.ResetTenants()
+
+	called := false
+	handler := TenantMiddleware(TenantOptions{Required: true}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/students", nil))
+	if rr.Code != http.StatusUnauthorized || called {
+		t.Errorf("Expected a request without a tenant to be rejected, got %d", rr.Code)
+	}
+
+	for _, path := range []string{"/admin/tenants", "/admin/tenants/north", "/admin/models", "/admin/models/llama3:latest"} {
+		called = false
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || !called {
+			t.Errorf("Expected %s to be exempt, got %d", path, rr.Code)
+		}
+	}
+}
+
+func TestTenantMiddlewareActor(t *testing.T) {
+	services.ResetTenants()
+	if _, err := services.CreateTenant(models.Tenant{ID: "north", Name: "north"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	token, err := services.IssueTenantToken("north")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var gotActor string
+	handler := RequestContextMiddleware(TenantMiddleware(TenantOptions{Header: "X-Tenant-ID", AdminToken: "admin-secret"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = reqctx.Actor(r.Context())
+	})))
+
+	tests := []struct {
+		name          string
+		token         string
+		claimed       string
+		expectedActor string
+	}{
+		{name: "unauthenticated", claimed: "registrar", expectedActor: "registrar"},
+		{name: "tenant token", token: token, expectedActor: "tenant:north"},
+		{name: "tenant token with a claimed actor", token: token, claimed: "registrar", expectedActor: "tenant:north/registrar"},
+		{name: "admin token", token: "admin-secret", claimed: "root", expectedActor: "admin/root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/students", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.claimed != "" {
+				req.Header.Set(ActorHeader, tt.claimed)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotActor != tt.expectedActor {
+				t.Errorf("Expected actor %q, got %q", tt.expectedActor, gotActor)
+			}
+		})
+	}
+}
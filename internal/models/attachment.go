@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Attachment describes a file attached to a student record, such as an ID
+// photo or a transcript PDF. The content lives in the blob store under
+// BlobKey. ContentType is sniffed from the content rather than taken from
+// the upload, and Checksum is the hex-encoded SHA-256 of the content.
+type Attachment struct {
+	ID          int       `json:"id"`
+	StudentID   int       `json:"student_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	UploadedBy  string    `json:"uploaded_by"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+	BlobKey     string    `json:"-"`
+}
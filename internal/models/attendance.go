@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type AttendanceStatus string
+
+const (
+	AttendancePresent AttendanceStatus = "present"
+	AttendanceAbsent  AttendanceStatus = "absent"
+	AttendanceLate    AttendanceStatus = "late"
+	AttendanceExcused AttendanceStatus = "excused"
+)
+
+func (s AttendanceStatus) IsValid() bool {
+	switch s {
+	case AttendancePresent, AttendanceAbsent, AttendanceLate, AttendanceExcused:
+		return true
+	}
+	return false
+}
+
+// AttendanceRecord is a student's attendance at one session of a course. A
+// student has at most one record per course and date.
+type AttendanceRecord struct {
+	ID         int              `json:"id"`
+	StudentID  int              `json:"student_id"`
+	CourseID   int              `json:"course_id"`
+	Date       string           `json:"date"`
+	Status     AttendanceStatus `json:"status"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+func (a *AttendanceRecord) Validate() error {
+	if _, err := time.Parse(DateLayout, a.Date); err != nil {
+		return errors.New("date must be formatted as YYYY-MM-DD")
+	}
+	if !a.Status.IsValid() {
+		return errors.New("status must be one of present, absent, late or excused")
+	}
+	return nil
+}
+
+// AttendanceSummary counts a student's attendance records. Rate is the share
+// of sessions attended, counting late arrivals as attended and leaving out
+// excused absences; it is absent when there are no such sessions.
+type AttendanceSummary struct {
+	StudentID   int      `json:"student_id"`
+	StudentName string   `json:"student_name"`
+	Sessions    int      `json:"sessions"`
+	Present     int      `json:"present"`
+	Absent      int      `json:"absent"`
+	Late        int      `json:"late"`
+	Excused     int      `json:"excused"`
+	Rate        *float64 `json:"rate"`
+}
+
+// Add counts record in the summary and updates the rate.
+func (s *AttendanceSummary) Add(record AttendanceRecord) {
+	s.Sessions++
+	switch record.Status {
+	case AttendancePresent:
+		s.Present++
+	case AttendanceAbsent:
+		s.Absent++
+	case AttendanceLate:
+		s.Late++
+	case AttendanceExcused:
+		s.Excused++
+	}
+
+	s.Rate = nil
+	if counted := s.Sessions - s.Excused; counted > 0 {
+		rate := float64(s.Present+s.Late) / float64(counted)
+		s.Rate = &rate
+	}
+}
+
+// StudentAttendance is one student's attendance summary with the records it
+// was computed from.
+type StudentAttendance struct {
+	AttendanceSummary
+	Records []AttendanceRecord `json:"records"`
+}
+
+// AttendanceReport summarises attendance per student over an optional date
+// range and course. Threshold is set when the report only lists students
+// whose rate is below it.
+type AttendanceReport struct {
+	From      string              `json:"from,omitempty"`
+	To        string              `json:"to,omitempty"`
+	CourseID  int                 `json:"course_id,omitempty"`
+	Threshold *float64            `json:"threshold,omitempty"`
+	Students  []AttendanceSummary `json:"students"`
+}
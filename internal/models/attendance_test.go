@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestAttendanceRecordValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      AttendanceRecord
+		expectError bool
+	}{
+		{"Valid record", AttendanceRecord{Date: "2026-10-19", Status: AttendanceLate}, false},
+		{"Invalid date", AttendanceRecord{Date: "19/10/2026", Status: AttendancePresent}, true},
+		{"Missing date", AttendanceRecord{Status: AttendancePresent}, true},
+		{"Unknown status", AttendanceRecord{Date: "2026-10-19", Status: "sick"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.record.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestAttendanceSummaryRate(t *testing.T) {
+	var summary AttendanceSummary
+	summary.Add(AttendanceRecord{Status: AttendanceExcused})
+	if summary.Rate != nil {
+		t.Errorf("Expected no rate with only excused absences, got %v", *summary.Rate)
+	}
+
+	for _, status := range []AttendanceStatus{AttendancePresent, AttendanceLate, AttendanceAbsent, AttendancePresent} {
+		summary.Add(AttendanceRecord{Status: status})
+	}
+	if summary.Sessions != 5 || summary.Present != 2 || summary.Late != 1 || summary.Absent != 1 || summary.Excused != 1 {
+		t.Errorf("Unexpected counts: %+v", summary)
+	}
+	if summary.Rate == nil || *summary.Rate != 0.75 {
+		t.Errorf("Expected rate 0.75, got %v", summary.Rate)
+	}
+}
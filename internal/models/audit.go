@@ -0,0 +1,74 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate     AuditAction = "create"
+	AuditActionUpdate     AuditAction = "update"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionRestore    AuditAction = "restore"
+	AuditActionPurge      AuditAction = "purge"
+	AuditActionTransition AuditAction = "transition"
+)
+
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditEvent struct {
+	ID        int           `json:"id"`
+	Tenant    string        `json:"tenant,omitempty"`
+	StudentID int           `json:"student_id"`
+	Action    AuditAction   `json:"action"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// DiffStudents compares two student snapshots field by field, using the JSON
+// field names. A nil before or after means the student did not exist on that
+// side, in which case fields left at their zero value are omitted.
+func DiffStudents(before, after *Student) []FieldChange {
+	changes := []FieldChange{}
+
+	studentType := reflect.TypeOf(Student{})
+	for i := 0; i < studentType.NumField(); i++ {
+		field := studentType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || name == "id" {
+			continue
+		}
+
+		var beforeValue, afterValue interface{}
+		if before != nil {
+			value := reflect.ValueOf(*before).Field(i)
+			if after == nil && value.IsZero() {
+				continue
+			}
+			beforeValue = value.Interface()
+		}
+		if after != nil {
+			value := reflect.ValueOf(*after).Field(i)
+			if before == nil && value.IsZero() {
+				continue
+			}
+			afterValue = value.Interface()
+		}
+
+		if before != nil && after != nil && reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: beforeValue, After: afterValue})
+	}
+
+	return changes
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestDiffStudents(t *testing.T) {
+	original := Student{ID: 1, Name: "John Doe", Age: 20, Email: "john@example.com"}
+	updated := Student{ID: 1, Name: "John Doe", Age: 21, Email: "john.doe@example.com"}
+
+	tests := []struct {
+		name           string
+		before         *Student
+		after          *Student
+		expectedFields []string
+	}{
+		{
+			name:           "Create records every field",
+			before:         nil,
+			after:          &original,
+			expectedFields: []string{"name", "age", "email"},
+		},
+		{
+			name:           "Update records only changed fields",
+			before:         &original,
+			after:          &updated,
+			expectedFields: []string{"age", "email"},
+		},
+		{
+			name:           "Delete records every field",
+			before:         &original,
+			after:          nil,
+			expectedFields: []string{"name", "age", "email"},
+		},
+		{
+			name:           "No changes",
+			before:         &original,
+			after:          &original,
+			expectedFields: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffStudents(tt.before, tt.after)
+			if len(changes) != len(tt.expectedFields) {
+				t.Fatalf("Expected %d changes, got %d: %+v", len(tt.expectedFields), len(changes), changes)
+			}
+			for i, field := range tt.expectedFields {
+				if changes[i].Field != field {
+					t.Errorf("Expected change %d on field %s, got %s", i, field, changes[i].Field)
+				}
+			}
+		})
+	}
+
+	changes := DiffStudents(&original, &updated)
+	if changes[0].Before != 20 || changes[0].After != 21 {
+		t.Errorf("Expected age change 20 -> 21, got %v -> %v", changes[0].Before, changes[0].After)
+	}
+}
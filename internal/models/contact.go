@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+type ContactType string
+
+const (
+	ContactEmail ContactType = "email"
+	ContactPhone ContactType = "phone"
+)
+
+// Contact is a way of reaching a student or guardian.
+type Contact struct {
+	Type    ContactType `json:"type"`
+	Value   string      `json:"value"`
+	Label   string      `json:"label,omitempty"`
+	Primary bool        `json:"primary,omitempty"`
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+
+func (c *Contact) Validate() error {
+	switch c.Type {
+	case ContactEmail:
+		if !isValidEmail(c.Value) {
+			return errors.New("invalid email format")
+		}
+	case ContactPhone:
+		if !isValidPhone(c.Value) {
+			return errors.New("phone numbers must have 7 to 15 digits, optionally with a leading + and separators")
+		}
+	default:
+		return errors.New("type must be email or phone")
+	}
+	return nil
+}
+
+// isValidPhone accepts international and national numbers of 7 to 15 digits,
+// written with spaces, dots, dashes or parentheses between them.
+func isValidPhone(phone string) bool {
+	if !phonePattern.MatchString(phone) {
+		return false
+	}
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+type GuardianRelationship string
+
+const (
+	RelationshipParent      GuardianRelationship = "parent"
+	RelationshipMother      GuardianRelationship = "mother"
+	RelationshipFather      GuardianRelationship = "father"
+	RelationshipGuardian    GuardianRelationship = "guardian"
+	RelationshipGrandparent GuardianRelationship = "grandparent"
+	RelationshipSibling     GuardianRelationship = "sibling"
+	RelationshipOther       GuardianRelationship = "other"
+)
+
+func (r GuardianRelationship) IsValid() bool {
+	switch r {
+	case RelationshipParent, RelationshipMother, RelationshipFather, RelationshipGuardian,
+		RelationshipGrandparent, RelationshipSibling, RelationshipOther:
+		return true
+	}
+	return false
+}
+
+type Guardian struct {
+	Name         string               `json:"name"`
+	Relationship GuardianRelationship `json:"relationship"`
+	Contacts     []Contact            `json:"contacts,omitempty"`
+}
+
+func (g *Guardian) Validate() error {
+	if g.Name == "" {
+		return errors.New("name is required")
+	}
+	if !g.Relationship.IsValid() {
+		return errors.New("relationship must be one of parent, mother, father, guardian, grandparent, sibling or other")
+	}
+	for i := range g.Contacts {
+		if err := g.Contacts[i].Validate(); err != nil {
+			return fmt.Errorf("contacts[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+type AddressType string
+
+const (
+	AddressHome    AddressType = "home"
+	AddressMailing AddressType = "mailing"
+	AddressOther   AddressType = "other"
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Address is a postal address. Country is an ISO 3166-1 alpha-2 code.
+type Address struct {
+	Type       AddressType `json:"type"`
+	Line1      string      `json:"line1"`
+	Line2      string      `json:"line2,omitempty"`
+	City       string      `json:"city"`
+	Region     string      `json:"region,omitempty"`
+	PostalCode string      `json:"postal_code,omitempty"`
+	Country    string      `json:"country"`
+}
+
+func (a *Address) Validate() error {
+	switch a.Type {
+	case AddressHome, AddressMailing, AddressOther:
+	default:
+		return errors.New("type must be home, mailing or other")
+	}
+	if a.Line1 == "" || a.City == "" {
+		return errors.New("line1 and city are required")
+	}
+	if !countryCodePattern.MatchString(a.Country) {
+		return errors.New("country must be a two-letter ISO 3166 code such as US")
+	}
+	return nil
+}
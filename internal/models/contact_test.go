@@ -0,0 +1,72 @@
+package models
+
+import "testing"
+
+func TestContactValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact Contact
+		valid   bool
+	}{
+		{"Email", Contact{Type: ContactEmail, Value: "jane@example.com"}, true},
+		{"Invalid email", Contact{Type: ContactEmail, Value: "jane"}, false},
+		{"International phone", Contact{Type: ContactPhone, Value: "+44 20 7946 0958"}, true},
+		{"National phone", Contact{Type: ContactPhone, Value: "(555) 123-4567"}, true},
+		{"Short phone", Contact{Type: ContactPhone, Value: "12345"}, false},
+		{"Phone with letters", Contact{Type: ContactPhone, Value: "555-CALL-NOW"}, false},
+		{"Unknown type", Contact{Type: "fax", Value: "5551234567"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.contact.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error, got none")
+			}
+		})
+	}
+}
+
+func TestGuardianValidation(t *testing.T) {
+	guardian := Guardian{
+		Name:         "Mary Doe",
+		Relationship: RelationshipMother,
+		Contacts:     []Contact{{Type: ContactPhone, Value: "+1 555 123 4567"}},
+	}
+	if err := guardian.Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	guardian.Relationship = "neighbour"
+	if err := guardian.Validate(); err == nil {
+		t.Error("Expected error for an unknown relationship")
+	}
+
+	guardian.Relationship = RelationshipMother
+	guardian.Contacts[0].Value = "123"
+	if err := guardian.Validate(); err == nil || err.Error()[:12] != "contacts[0]:" {
+		t.Errorf("Expected error for contacts[0], got %v", err)
+	}
+}
+
+func TestAddressValidation(t *testing.T) {
+	address := Address{Type: AddressHome, Line1: "1 Main St", City: "Springfield", Country: "US"}
+	if err := address.Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	for _, country := range []string{"", "us", "USA"} {
+		address.Country = country
+		if err := address.Validate(); err == nil {
+			t.Errorf("Expected error for country %q", country)
+		}
+	}
+
+	address = Address{Type: AddressMailing, City: "Springfield", Country: "US"}
+	if err := address.Validate(); err == nil {
+		t.Error("Expected error for a missing line1")
+	}
+}
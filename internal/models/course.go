@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type Course struct {
+	ID       int    `json:"id"`
+	Code     string `json:"code"`
+	Title    string `json:"title"`
+	Capacity int    `json:"capacity"`
+}
+
+func (c *Course) Validate() error {
+	if c.Code == "" {
+		return errors.New("code is required")
+	}
+	if c.Title == "" {
+		return errors.New("title is required")
+	}
+	if c.Capacity <= 0 {
+		return errors.New("capacity must be a positive integer")
+	}
+	return nil
+}
+
+type EnrollmentStatus string
+
+const (
+	EnrollmentActive    EnrollmentStatus = "active"
+	EnrollmentCompleted EnrollmentStatus = "completed"
+	EnrollmentDropped   EnrollmentStatus = "dropped"
+)
+
+// enrollmentTransitions lists the statuses each status may move to. A
+// completed enrollment is final; a dropped one can be reactivated while the
+// course has room.
+var enrollmentTransitions = map[EnrollmentStatus][]EnrollmentStatus{
+	EnrollmentActive:  {EnrollmentCompleted, EnrollmentDropped},
+	EnrollmentDropped: {EnrollmentActive},
+}
+
+func (s EnrollmentStatus) IsValid() bool {
+	switch s {
+	case EnrollmentActive, EnrollmentCompleted, EnrollmentDropped:
+		return true
+	}
+	return false
+}
+
+func (s EnrollmentStatus) CanTransitionTo(next EnrollmentStatus) bool {
+	for _, allowed := range enrollmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Enrollment links a student to a course. Only active enrollments take up
+// a seat. Student is populated when listing a course's roster.
+type Enrollment struct {
+	ID         int              `json:"id"`
+	StudentID  int              `json:"student_id"`
+	CourseID   int              `json:"course_id"`
+	Status     EnrollmentStatus `json:"status"`
+	EnrolledAt time.Time        `json:"enrolled_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	Student    *Student         `json:"student,omitempty"`
+}
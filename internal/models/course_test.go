@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCourseValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		course      Course
+		expectError bool
+	}{
+		{
+			name:        "Valid course",
+			course:      Course{Code: "CS101", Title: "Intro to Programming", Capacity: 30},
+			expectError: false,
+		},
+		{
+			name:        "Missing code",
+			course:      Course{Title: "Intro to Programming", Capacity: 30},
+			expectError: true,
+		},
+		{
+			name:        "Missing title",
+			course:      Course{Code: "CS101", Capacity: 30},
+			expectError: true,
+		},
+		{
+			name:        "Zero capacity",
+			course:      Course{Code: "CS101", Title: "Intro to Programming"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.course.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnrollmentStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to EnrollmentStatus
+		allowed  bool
+	}{
+		{EnrollmentActive, EnrollmentCompleted, true},
+		{EnrollmentActive, EnrollmentDropped, true},
+		{EnrollmentDropped, EnrollmentActive, true},
+		{EnrollmentCompleted, EnrollmentActive, false},
+		{EnrollmentCompleted, EnrollmentDropped, false},
+		{EnrollmentDropped, EnrollmentCompleted, false},
+		{EnrollmentActive, EnrollmentActive, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldString  CustomFieldType = "string"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldInteger CustomFieldType = "integer"
+	CustomFieldBoolean CustomFieldType = "boolean"
+	CustomFieldDate    CustomFieldType = "date"
+	CustomFieldEnum    CustomFieldType = "enum"
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// CustomFieldDefinition is an admin-defined student field. Values are
+// validated against its type: enum values must be one of Options and date
+// values are "YYYY-MM-DD" strings.
+type CustomFieldDefinition struct {
+	Key      string          `json:"key"`
+	Label    string          `json:"label"`
+	Type     CustomFieldType `json:"type"`
+	Required bool            `json:"required"`
+	Options  []string        `json:"options,omitempty"`
+}
+
+func (d *CustomFieldDefinition) Validate() error {
+	if !customFieldKeyPattern.MatchString(d.Key) {
+		return errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if d.Label == "" {
+		return errors.New("label is required")
+	}
+	switch d.Type {
+	case CustomFieldString, CustomFieldNumber, CustomFieldInteger, CustomFieldBoolean, CustomFieldDate:
+		if len(d.Options) > 0 {
+			return errors.New("options are only allowed for enum fields")
+		}
+	case CustomFieldEnum:
+		if len(d.Options) == 0 {
+			return errors.New("enum fields require options")
+		}
+	default:
+		return errors.New("type must be one of string, number, integer, boolean, date or enum")
+	}
+	return nil
+}
+
+// ValidateValue checks a value decoded from JSON against the field's type.
+func (d *CustomFieldDefinition) ValidateValue(value interface{}) error {
+	switch d.Type {
+	case CustomFieldString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s must be a string", d.Key)
+		}
+	case CustomFieldNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s must be a number", d.Key)
+		}
+	case CustomFieldInteger:
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			return fmt.Errorf("%s must be an integer", d.Key)
+		}
+	case CustomFieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", d.Key)
+		}
+	case CustomFieldDate:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a date formatted as YYYY-MM-DD", d.Key)
+		}
+		if _, err := ParseDate(text); err != nil {
+			return fmt.Errorf("%s must be a date formatted as YYYY-MM-DD", d.Key)
+		}
+	case CustomFieldEnum:
+		text, _ := value.(string)
+		for _, option := range d.Options {
+			if text == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %v", d.Key, d.Options)
+	}
+	return nil
+}
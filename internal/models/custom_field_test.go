@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestCustomFieldDefinitionValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition CustomFieldDefinition
+		valid      bool
+	}{
+		{"String", CustomFieldDefinition{Key: "locker_number", Label: "Locker", Type: CustomFieldString}, true},
+		{"Enum", CustomFieldDefinition{Key: "house", Label: "House", Type: CustomFieldEnum, Options: []string{"red", "blue"}}, true},
+		{"Enum without options", CustomFieldDefinition{Key: "house", Label: "House", Type: CustomFieldEnum}, false},
+		{"Options on a string", CustomFieldDefinition{Key: "house", Label: "House", Type: CustomFieldString, Options: []string{"red"}}, false},
+		{"Uppercase key", CustomFieldDefinition{Key: "House", Label: "House", Type: CustomFieldString}, false},
+		{"Missing label", CustomFieldDefinition{Key: "house", Type: CustomFieldString}, false},
+		{"Unknown type", CustomFieldDefinition{Key: "house", Label: "House", Type: "color"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.definition.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error, got none")
+			}
+		})
+	}
+}
+
+func TestCustomFieldValidateValue(t *testing.T) {
+	tests := []struct {
+		fieldType CustomFieldType
+		value     interface{}
+		valid     bool
+	}{
+		{CustomFieldString, "A12", true},
+		{CustomFieldString, 12.0, false},
+		{CustomFieldNumber, 3.5, true},
+		{CustomFieldNumber, "3.5", false},
+		{CustomFieldInteger, 3.0, true},
+		{CustomFieldInteger, 3.5, false},
+		{CustomFieldBoolean, true, true},
+		{CustomFieldBoolean, "true", false},
+		{CustomFieldDate, "2024-09-01", true},
+		{CustomFieldDate, "2024-13-01", false},
+		{CustomFieldEnum, "red", true},
+		{CustomFieldEnum, "green", false},
+	}
+
+	for _, tt := range tests {
+		definition := CustomFieldDefinition{Key: "field", Label: "Field", Type: tt.fieldType}
+		if tt.fieldType == CustomFieldEnum {
+			definition.Options = []string{"red", "blue"}
+		}
+		err := definition.ValidateValue(tt.value)
+		if tt.valid && err != nil {
+			t.Errorf("Expected %v to be a valid %s, got %v", tt.value, tt.fieldType, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Expected %v to be an invalid %s", tt.value, tt.fieldType)
+		}
+	}
+}
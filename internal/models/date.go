@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DateLayout is the format of calendar dates in the API.
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date without a time of day, encoded in JSON as
+// "YYYY-MM-DD".
+type Date struct {
+	time.Time
+}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func ParseDate(value string) (Date, error) {
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{parsed}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// YearsOn returns the number of whole years from d to t, such as a person's
+// age on t when d is their date of birth.
+func (d Date) YearsOn(t time.Time) int {
+	years := t.Year() - d.Year()
+	if t.Month() < d.Month() || (t.Month() == d.Month() && t.Day() < d.Day()) {
+		years--
+	}
+	return years
+}
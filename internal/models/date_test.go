@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateJSON(t *testing.T) {
+	date := NewDate(2004, time.March, 9)
+
+	data, err := json.Marshal(date)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `"2004-03-09"` {
+		t.Errorf("Expected \"2004-03-09\", got %s", data)
+	}
+
+	var decoded Date
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !decoded.Equal(date.Time) {
+		t.Errorf("Expected %s, got %s", date, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`"09/03/2004"`), &decoded); err == nil {
+		t.Error("Expected error for a date in another format")
+	}
+}
+
+func TestDateYearsOn(t *testing.T) {
+	birth := NewDate(2004, time.March, 9)
+
+	tests := []struct {
+		on    time.Time
+		years int
+	}{
+		{time.Date(2024, time.March, 8, 23, 0, 0, 0, time.UTC), 19},
+		{time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC), 20},
+		{time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC), 20},
+	}
+	for _, tt := range tests {
+		if years := birth.YearsOn(tt.on); years != tt.years {
+			t.Errorf("Expected %d years on %s, got %d", tt.years, tt.on.Format(DateLayout), years)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type StudentEventType string
+
+const (
+	StudentCreated  StudentEventType = "student.created"
+	StudentUpdated  StudentEventType = "student.updated"
+	StudentDeleted  StudentEventType = "student.deleted"
+	StudentRestored StudentEventType = "student.restored"
+	StudentPurged   StudentEventType = "student.purged"
+	// StudentStatusChanged is published when a student moves through a
+	// lifecycle transition.
+	StudentStatusChanged StudentEventType = "student.status_changed"
+)
+
+type StudentEvent struct {
+	ID        int64            `json:"id"`
+	Tenant    string           `json:"-"`
+	Type      StudentEventType `json:"type"`
+	StudentID int              `json:"student_id"`
+	Student   Student          `json:"student"`
+	Timestamp time.Time        `json:"timestamp"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Grade is one assessment result for a student in a course. Weight sets how
+// much the assessment counts towards the course average relative to the
+// course's other assessments.
+type Grade struct {
+	ID         int       `json:"id"`
+	StudentID  int       `json:"student_id"`
+	CourseID   int       `json:"course_id"`
+	Assessment string    `json:"assessment"`
+	Score      float64   `json:"score"`
+	MaxScore   float64   `json:"max_score"`
+	Weight     float64   `json:"weight"`
+	RecordedAt time.Time `json:"recorded_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (g *Grade) Validate() error {
+	if g.Assessment == "" {
+		return errors.New("assessment is required")
+	}
+	if g.MaxScore <= 0 {
+		return errors.New("max_score must be positive")
+	}
+	if g.Score < 0 || g.Score > g.MaxScore {
+		return errors.New("score must be between 0 and max_score")
+	}
+	if g.Weight <= 0 {
+		return errors.New("weight must be positive")
+	}
+	return nil
+}
+
+// Percent returns the score as a percentage of the maximum score.
+func (g *Grade) Percent() float64 {
+	return g.Score / g.MaxScore * 100
+}
+
+// CourseResult is a course on a transcript. Average is the weighted
+// percentage over the course's grades, and Letter and GradePoints are its
+// band on the grade scale; all three are absent until a grade is recorded.
+type CourseResult struct {
+	CourseID    int              `json:"course_id"`
+	Code        string           `json:"code"`
+	Title       string           `json:"title"`
+	Status      EnrollmentStatus `json:"status"`
+	Average     *float64         `json:"average,omitempty"`
+	Letter      string           `json:"letter,omitempty"`
+	GradePoints *float64         `json:"grade_points,omitempty"`
+	Grades      []Grade          `json:"grades"`
+}
+
+// Transcript is a student's academic record. GPA is the mean grade points of
+// the graded courses that were not dropped, out of MaxGPA.
+type Transcript struct {
+	StudentID int            `json:"student_id"`
+	Courses   []CourseResult `json:"courses"`
+	GPA       *float64       `json:"gpa"`
+	MaxGPA    float64        `json:"max_gpa"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestGradeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		grade       Grade
+		expectError bool
+	}{
+		{"Valid grade", Grade{Assessment: "Midterm", Score: 42, MaxScore: 50, Weight: 1}, false},
+		{"Missing assessment", Grade{Score: 42, MaxScore: 50, Weight: 1}, true},
+		{"Zero max score", Grade{Assessment: "Midterm", Weight: 1}, true},
+		{"Score above max", Grade{Assessment: "Midterm", Score: 51, MaxScore: 50, Weight: 1}, true},
+		{"Negative score", Grade{Assessment: "Midterm", Score: -1, MaxScore: 50, Weight: 1}, true},
+		{"Zero weight", Grade{Assessment: "Midterm", Score: 42, MaxScore: 50}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.grade.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
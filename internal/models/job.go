@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type SummaryJobStatus string
+
+const (
+	SummaryJobQueued    SummaryJobStatus = "queued"
+	SummaryJobRunning   SummaryJobStatus = "running"
+	SummaryJobCompleted SummaryJobStatus = "completed"
+	SummaryJobCancelled SummaryJobStatus = "cancelled"
+)
+
+type SummaryJobResultStatus string
+
+const (
+	SummaryResultPending   SummaryJobResultStatus = "pending"
+	SummaryResultSucceeded SummaryJobResultStatus = "succeeded"
+	SummaryResultFailed    SummaryJobResultStatus = "failed"
+	SummaryResultCancelled SummaryJobResultStatus = "cancelled"
+)
+
+type SummaryJobResult struct {
+	StudentID int                    `json:"student_id"`
+	Status    SummaryJobResultStatus `json:"status"`
+	SummaryID int                    `json:"summary_id,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+type SummaryJob struct {
+	ID         int                `json:"id"`
+	Tenant     string             `json:"tenant,omitempty"`
+	Status     SummaryJobStatus   `json:"status"`
+	Total      int                `json:"total"`
+	Succeeded  int                `json:"succeeded"`
+	Failed     int                `json:"failed"`
+	Results    []SummaryJobResult `json:"results"`
+	CreatedAt  time.Time          `json:"created_at"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+func (j *SummaryJob) IsFinished() bool {
+	return j.Status == SummaryJobCompleted || j.Status == SummaryJobCancelled
+}
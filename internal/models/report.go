@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const maxReportTitleLength = 200
+
+type ReportFormat string
+
+const (
+	ReportMarkdown ReportFormat = "markdown"
+	ReportHTML     ReportFormat = "html"
+)
+
+func (f ReportFormat) IsValid() bool {
+	return f == ReportMarkdown || f == ReportHTML
+}
+
+// Cohort is the aggregated view of a group of students that a cohort report
+// is written from. It holds no individual student's details. The averages
+// are absent when no student in the cohort has a GPA or attendance records.
+type Cohort struct {
+	Title             string
+	Stats             StudentStats
+	GradedStudents    int
+	AverageGPA        *float64
+	MaxGPA            float64
+	TrackedStudents   int
+	AverageAttendance *float64
+}
+
+// CohortReport is a generated narrative report about a cohort. Filter is the
+// student filter that selected the cohort, as JSON.
+type CohortReport struct {
+	ID               int                `json:"id"`
+	Title            string             `json:"title"`
+	Filter           json.RawMessage    `json:"filter"`
+	StudentCount     int                `json:"student_count"`
+	Stats            StudentStats       `json:"stats"`
+	Narrative        string             `json:"narrative"`
+	Model            string             `json:"model"`
+	PromptVersion    string             `json:"prompt_version"`
+	Options          *GenerationOptions `json:"options,omitempty"`
+	GeneratedAt      time.Time          `json:"generated_at"`
+	PromptTokens     int                `json:"prompt_tokens"`
+	CompletionTokens int                `json:"completion_tokens"`
+}
+
+// ValidateReportTitle checks a cohort report title, which may be empty.
+func ValidateReportTitle(title string) error {
+	if len([]rune(title)) > maxReportTitleLength {
+		return errors.New("title must be at most 200 characters")
+	}
+	return nil
+}
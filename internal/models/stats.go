@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CustomFieldGroupPrefix starts group_by fields that name a custom field,
+// such as "custom_fields.house".
+const CustomFieldGroupPrefix = "custom_fields."
+
+// StatsBucket counts the students sharing a value.
+type StatsBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// AgeBucket counts the students whose age is between Min and Max inclusive.
+type AgeBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// StudentStats aggregates a set of students. The age histogram and the
+// creation counts are ordered by age and date; email domains and groups are
+// ordered by count, largest first. Groups holds one breakdown per requested
+// field, with students lacking a value counted under an empty value.
+type StudentStats struct {
+	Count          int                      `json:"count"`
+	AgeHistogram   []AgeBucket              `json:"age_histogram"`
+	EmailDomains   []StatsBucket            `json:"email_domains"`
+	CreatedPerDay  []StatsBucket            `json:"created_per_day"`
+	CreatedPerWeek []StatsBucket            `json:"created_per_week"`
+	Groups         map[string][]StatsBucket `json:"groups,omitempty"`
+}
+
+// studentGroupFields maps the JSON names of the student fields that can be
+// grouped by to their field index. Only scalar fields qualify; the ID and
+// timestamps are left out since nearly every student has its own value.
+var studentGroupFields = func() map[string]int {
+	fields := make(map[string]int)
+	studentType := reflect.TypeOf(Student{})
+	dateType := reflect.TypeOf(Date{})
+	for i := 0; i < studentType.NumField(); i++ {
+		field := studentType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || name == "id" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType == dateType,
+			fieldType.Kind() == reflect.String,
+			fieldType.Kind() == reflect.Int,
+			fieldType.Kind() == reflect.Bool:
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// IsStudentGroupField reports whether students can be grouped by the named
+// top-level field. Custom fields are checked against their definitions by
+// the caller.
+func IsStudentGroupField(field string) bool {
+	_, ok := studentGroupFields[field]
+	return ok
+}
+
+// GroupValue returns the student's value for a group_by field as a string,
+// or "" when the student has none.
+func (s *Student) GroupValue(field string) string {
+	if key := strings.TrimPrefix(field, CustomFieldGroupPrefix); key != field {
+		value, ok := s.CustomFields[key]
+		if !ok || value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+	if field == "age" {
+		return strconv.Itoa(s.CurrentAge())
+	}
+
+	index, ok := studentGroupFields[field]
+	if !ok {
+		return ""
+	}
+	value := reflect.ValueOf(*s).Field(index)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// EmailDomain returns the lower-cased part of the email after the last "@".
+func (s *Student) EmailDomain() string {
+	return strings.ToLower(s.Email[strings.LastIndex(s.Email, "@")+1:])
+}
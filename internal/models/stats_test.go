@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStudentGroupValue(t *testing.T) {
+	enrolled := NewDate(2024, time.September, 1)
+	student := Student{
+		Name:           "Alice",
+		Age:            20,
+		Email:          "Alice@School.EDU",
+		EnrollmentDate: &enrolled,
+		Status:         StudentEnrolled,
+		CustomFields:   map[string]interface{}{"house": "red", "locker": 42.0},
+	}
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"status", "enrolled"},
+		{"age", "20"},
+		{"enrollment_date", "2024-09-01"},
+		{"date_of_birth", ""},
+		{"custom_fields.house", "red"},
+		{"custom_fields.locker", "42"},
+		{"custom_fields.bus", ""},
+	}
+
+	for _, tt := range tests {
+		if value := student.GroupValue(tt.field); value != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.field, tt.expected, value)
+		}
+	}
+
+	for _, field := range []string{"status", "age", "name", "enrollment_date"} {
+		if !IsStudentGroupField(field) {
+			t.Errorf("Expected %s to be groupable", field)
+		}
+	}
+	for _, field := range []string{"id", "contacts", "custom_fields", "deleted_at", "created_at", "unknown"} {
+		if IsStudentGroupField(field) {
+			t.Errorf("Expected %s not to be groupable", field)
+		}
+	}
+
+	if domain := student.EmailDomain(); domain != "school.edu" {
+		t.Errorf("Expected school.edu, got %s", domain)
+	}
+}
@@ -1,27 +1,141 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
+	"time"
 )
 
+type StudentStatus string
+
+const (
+	StudentApplied   StudentStatus = "applied"
+	StudentEnrolled  StudentStatus = "enrolled"
+	StudentSuspended StudentStatus = "suspended"
+	StudentGraduated StudentStatus = "graduated"
+	StudentWithdrawn StudentStatus = "withdrawn"
+)
+
+// StudentStatuses lists every status in lifecycle order.
+var StudentStatuses = []StudentStatus{StudentApplied, StudentEnrolled, StudentSuspended, StudentGraduated, StudentWithdrawn}
+
+func (s StudentStatus) IsValid() bool {
+	switch s {
+	case StudentApplied, StudentEnrolled, StudentSuspended, StudentGraduated, StudentWithdrawn:
+		return true
+	}
+	return false
+}
+
+// studentTransitions lists the statuses each status may move to. Graduated
+// and withdrawn students have left, so both statuses are final.
+var studentTransitions = map[StudentStatus][]StudentStatus{
+	StudentApplied:   {StudentEnrolled, StudentWithdrawn},
+	StudentEnrolled:  {StudentSuspended, StudentGraduated, StudentWithdrawn},
+	StudentSuspended: {StudentEnrolled, StudentWithdrawn},
+}
+
+// IsInitial reports whether a new student may start in the status.
+func (s StudentStatus) IsInitial() bool {
+	return s == StudentApplied || s == StudentEnrolled
+}
+
+func (s StudentStatus) CanTransitionTo(next StudentStatus) bool {
+	for _, allowed := range studentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// maxStudentAge bounds dates of birth so typos such as 1066 are rejected.
+const maxStudentAge = 130
+
 type Student struct {
 	ID    int    `json:"id"`
 	Name  string `json:"name"`
 	Age   int    `json:"age"`
 	Email string `json:"email"`
+	// DateOfBirth, when set, replaces Age: the age is derived from it
+	// whenever the student is read rather than stored.
+	DateOfBirth    *Date         `json:"date_of_birth,omitempty"`
+	EnrollmentDate *Date         `json:"enrollment_date,omitempty"`
+	Status         StudentStatus `json:"status,omitempty"`
+	Contacts       []Contact     `json:"contacts,omitempty"`
+	Guardians      []Guardian    `json:"guardians,omitempty"`
+	Addresses      []Address     `json:"addresses,omitempty"`
+	// CustomFields holds values for the admin-defined custom fields, keyed
+	// by field key. Values are validated against the field definitions.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	// CreatedAt is set by the store when the student is created and kept
+	// on every update.
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (s *Student) IsDeleted() bool {
+	return s.DeletedAt != nil
+}
+
+// CurrentAge returns the age derived from the date of birth, or the stored
+// age for students without one.
+func (s *Student) CurrentAge() int {
+	if s.DateOfBirth != nil {
+		return s.DateOfBirth.YearsOn(time.Now().UTC())
+	}
+	return s.Age
+}
+
+// MarshalJSON encodes the student with its current age.
+func (s Student) MarshalJSON() ([]byte, error) {
+	type student Student
+	encoded := student(s)
+	encoded.Age = s.CurrentAge()
+	return json.Marshal(encoded)
 }
 
 func (s *Student) Validate() error {
 	if s.Name == "" {
 		return errors.New("name is required")
 	}
-	if s.Age <= 0 {
+	if s.DateOfBirth != nil {
+		now := time.Now().UTC()
+		if s.DateOfBirth.After(now) {
+			return errors.New("date_of_birth must not be in the future")
+		}
+		if s.DateOfBirth.YearsOn(now) > maxStudentAge {
+			return fmt.Errorf("date_of_birth must be within the last %d years", maxStudentAge)
+		}
+	} else if s.Age <= 0 {
 		return errors.New("age must be a positive integer")
 	}
 	if !isValidEmail(s.Email) {
 		return errors.New("invalid email format")
 	}
+	if s.EnrollmentDate != nil && s.DateOfBirth != nil && s.EnrollmentDate.Before(s.DateOfBirth.Time) {
+		return errors.New("enrollment_date must not be before date_of_birth")
+	}
+	if s.Status != "" && !s.Status.IsValid() {
+		return errors.New("status must be one of applied, enrolled, suspended, graduated or withdrawn")
+	}
+	for i := range s.Contacts {
+		if err := s.Contacts[i].Validate(); err != nil {
+			return fmt.Errorf("contacts[%d]: %w", i, err)
+		}
+	}
+	for i := range s.Guardians {
+		if err := s.Guardians[i].Validate(); err != nil {
+			return fmt.Errorf("guardians[%d]: %w", i, err)
+		}
+	}
+	for i := range s.Addresses {
+		if err := s.Addresses[i].Validate(); err != nil {
+			return fmt.Errorf("addresses[%d]: %w", i, err)
+		}
+	}
 	return nil
 }
 
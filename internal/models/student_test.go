@@ -1,7 +1,9 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestStudentValidation(t *testing.T) {
@@ -115,3 +117,74 @@ func TestEmailValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestStudentProfileValidation(t *testing.T) {
+	birth := NewDate(2004, time.March, 9)
+	enrolled := NewDate(2022, time.September, 1)
+	student := Student{
+		Name:           "John Doe",
+		Email:          "john@example.com",
+		DateOfBirth:    &birth,
+		EnrollmentDate: &enrolled,
+		Status:         StudentEnrolled,
+		Contacts:       []Contact{{Type: ContactPhone, Value: "+1 555 123 4567", Primary: true}},
+		Guardians:      []Guardian{{Name: "Mary Doe", Relationship: RelationshipMother}},
+		Addresses:      []Address{{Type: AddressHome, Line1: "1 Main St", City: "Springfield", Country: "US"}},
+	}
+	if err := student.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(s *Student)
+		errorMsg string
+	}{
+		{"Future date of birth", func(s *Student) {
+			future := NewDate(time.Now().Year()+1, time.January, 1)
+			s.DateOfBirth = &future
+		}, "date_of_birth must not be in the future"},
+		{"Enrolled before birth", func(s *Student) {
+			early := NewDate(2000, time.January, 1)
+			s.EnrollmentDate = &early
+		}, "enrollment_date must not be before date_of_birth"},
+		{"Unknown status", func(s *Student) {
+			s.Status = "expelled"
+		}, "status must be one of applied, enrolled, suspended, graduated or withdrawn"},
+		{"Invalid address", func(s *Student) {
+			s.Addresses = []Address{{Type: AddressHome, Line1: "1 Main St", City: "Springfield", Country: "USA"}}
+		}, "addresses[0]: country must be a two-letter ISO 3166 code such as US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := student
+			tt.modify(&modified)
+			err := modified.Validate()
+			if err == nil || err.Error() != tt.errorMsg {
+				t.Errorf("Expected error '%s', got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestStudentJSONDerivesAge(t *testing.T) {
+	birth := NewDate(time.Now().UTC().Year()-20, time.January, 1)
+	student := Student{ID: 1, Name: "John Doe", Email: "john@example.com", DateOfBirth: &birth}
+
+	data, err := json.Marshal(student)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded Student
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.Age != 20 {
+		t.Errorf("Expected derived age 20, got %d", decoded.Age)
+	}
+	if decoded.DateOfBirth == nil || decoded.DateOfBirth.String() != birth.String() {
+		t.Errorf("Expected date of birth %s, got %v", birth, decoded.DateOfBirth)
+	}
+}
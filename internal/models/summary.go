@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type Summary struct {
+	ID               int                `json:"id"`
+	StudentID        int                `json:"student_id"`
+	Text             string             `json:"summary"`
+	Strengths        []string           `json:"strengths,omitempty"`
+	NextSteps        []string           `json:"next_steps,omitempty"`
+	Model            string             `json:"model"`
+	Style            string             `json:"style"`
+	PromptVersion    string             `json:"prompt_version"`
+	Options          *GenerationOptions `json:"options,omitempty"`
+	GeneratedAt      time.Time          `json:"generated_at"`
+	PromptTokens     int                `json:"prompt_tokens"`
+	CompletionTokens int                `json:"completion_tokens"`
+	Pinned           bool               `json:"pinned"`
+}
+
+// GenerationOptions are the sampling parameters sent to the model. Unset
+// fields fall back to the model's own defaults.
+type GenerationOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  *int     `json:"num_predict,omitempty"`
+	Seed        *int     `json:"seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+func (o GenerationOptions) IsZero() bool {
+	return o.Temperature == nil && o.NumPredict == nil && o.Seed == nil && len(o.Stop) == 0
+}
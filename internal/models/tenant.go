@@ -0,0 +1,27 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// Tenant is a school or organization hosted on the deployment. Every record
+// belongs to exactly one tenant and is only visible to it.
+type Tenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (t *Tenant) Validate() error {
+	if !tenantIDPattern.MatchString(t.ID) {
+		return errors.New("id must be 2 to 63 lowercase letters, digits or dashes, starting with a letter or digit")
+	}
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// StatusTransition records a student moving from one lifecycle status to
+// another, with who made the change and why.
+type StatusTransition struct {
+	ID        int           `json:"id"`
+	StudentID int           `json:"student_id"`
+	From      StudentStatus `json:"from"`
+	To        StudentStatus `json:"to"`
+	Reason    string        `json:"reason"`
+	Actor     string        `json:"actor"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// TransitionRequest asks for a student to be moved to Status.
+type TransitionRequest struct {
+	Status StudentStatus `json:"status"`
+	Reason string        `json:"reason"`
+}
+
+func (r *TransitionRequest) Validate() error {
+	if !r.Status.IsValid() {
+		return errors.New("status must be one of applied, enrolled, suspended, graduated or withdrawn")
+	}
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	if len(r.Reason) > 500 {
+		return errors.New("reason must be at most 500 characters")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStudentStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to StudentStatus
+		allowed  bool
+	}{
+		{StudentApplied, StudentEnrolled, true},
+		{StudentApplied, StudentWithdrawn, true},
+		{StudentEnrolled, StudentSuspended, true},
+		{StudentEnrolled, StudentGraduated, true},
+		{StudentEnrolled, StudentWithdrawn, true},
+		{StudentSuspended, StudentEnrolled, true},
+		{StudentSuspended, StudentWithdrawn, true},
+		{StudentApplied, StudentGraduated, false},
+		{StudentSuspended, StudentGraduated, false},
+		{StudentGraduated, StudentEnrolled, false},
+		{StudentWithdrawn, StudentApplied, false},
+		{StudentEnrolled, StudentEnrolled, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+}
+
+func TestTransitionRequestValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     TransitionRequest
+		expectError bool
+	}{
+		{"valid", TransitionRequest{Status: StudentSuspended, Reason: "Unpaid fees"}, false},
+		{"unknown status", TransitionRequest{Status: "expelled", Reason: "Misconduct"}, true},
+		{"missing reason", TransitionRequest{Status: StudentSuspended}, true},
+		{"reason too long", TransitionRequest{Status: StudentSuspended, Reason: strings.Repeat("a", 501)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var studentEventTypes = []StudentEventType{
+	StudentCreated,
+	StudentUpdated,
+	StudentDeleted,
+	StudentRestored,
+	StudentPurged,
+	StudentStatusChanged,
+}
+
+type Webhook struct {
+	ID        int                `json:"id"`
+	URL       string             `json:"url"`
+	Events    []StudentEventType `json:"events"`
+	Secret    string             `json:"secret,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func (wh *Webhook) Validate() error {
+	parsed, err := url.Parse(wh.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if isInternalHost(parsed.Hostname()) {
+		return errors.New("url must not point to a loopback, link-local or private address")
+	}
+	for _, eventType := range wh.Events {
+		if !isStudentEventType(eventType) {
+			return errors.New("unknown event type: " + string(eventType))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the webhook subscribes to eventType. A webhook with
+// no event types subscribes to all of them.
+func (wh *Webhook) Matches(eventType StudentEventType) bool {
+	if len(wh.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range wh.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalHost reports whether host names the server itself or is an
+// internal IP address. Other host names are checked once resolved, when a
+// delivery connects.
+func isInternalHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && IsInternalIP(ip)
+}
+
+// IsInternalIP reports whether ip is a loopback, link-local, private or
+// unspecified address, which webhooks must not be able to reach.
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified()
+}
+
+func isStudentEventType(eventType StudentEventType) bool {
+	for _, known := range studentEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID         int              `json:"id"`
+	WebhookID  int              `json:"webhook_id"`
+	EventID    int64            `json:"event_id"`
+	EventType  StudentEventType `json:"event_type"`
+	Attempt    int              `json:"attempt"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Success    bool             `json:"success"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+type DeadLetter struct {
+	WebhookID int          `json:"webhook_id"`
+	URL       string       `json:"url"`
+	Event     StudentEvent `json:"event"`
+	Attempts  int          `json:"attempts"`
+	LastError string       `json:"last_error"`
+	FailedAt  time.Time    `json:"failed_at"`
+}
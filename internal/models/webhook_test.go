@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestWebhookValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		webhook     Webhook
+		expectError bool
+	}{
+		{
+			name:        "Valid webhook",
+			webhook:     Webhook{URL: "https://lms.example.com/hooks", Events: []StudentEventType{StudentCreated}},
+			expectError: false,
+		},
+		{
+			name:        "Valid webhook for all events",
+			webhook:     Webhook{URL: "http://billing.internal/hooks"},
+			expectError: false,
+		},
+		{
+			name:        "Missing URL",
+			webhook:     Webhook{},
+			expectError: true,
+		},
+		{
+			name:        "Unsupported scheme",
+			webhook:     Webhook{URL: "ftp://example.com/hooks"},
+			expectError: true,
+		},
+		{
+			name:        "Loopback address",
+			webhook:     Webhook{URL: "http://127.0.0.1:8080/hooks"},
+			expectError: true,
+		},
+		{
+			name:        "Localhost",
+			webhook:     Webhook{URL: "http://localhost/hooks"},
+			expectError: true,
+		},
+		{
+			name:        "Link-local metadata address",
+			webhook:     Webhook{URL: "http://169.254.169.254/latest/meta-data"},
+			expectError: true,
+		},
+		{
+			name:        "Private address",
+			webhook:     Webhook{URL: "https://10.0.0.5/hooks"},
+			expectError: true,
+		},
+		{
+			name:        "IPv6 loopback",
+			webhook:     Webhook{URL: "http://[::1]/hooks"},
+			expectError: true,
+		},
+		{
+			name:        "Public address",
+			webhook:     Webhook{URL: "https://203.0.113.10/hooks"},
+			expectError: false,
+		},
+		{
+			name:        "Unknown event type",
+			webhook:     Webhook{URL: "https://example.com/hooks", Events: []StudentEventType{"student.graduated"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.webhook.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	all := Webhook{URL: "https://example.com"}
+	if !all.Matches(StudentDeleted) {
+		t.Error("Expected webhook without events to match every event")
+	}
+
+	created := Webhook{URL: "https://example.com", Events: []StudentEventType{StudentCreated}}
+	if !created.Matches(StudentCreated) || created.Matches(StudentDeleted) {
+		t.Error("Expected webhook to match only subscribed events")
+	}
+}
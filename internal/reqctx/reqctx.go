@@ -0,0 +1,49 @@
+package reqctx
+
+import "context"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+	tenantKey
+)
+
+const (
+	DefaultActor  = "anonymous"
+	DefaultTenant = "default"
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// Tenant returns the tenant whose data the request operates on, which is
+// DefaultTenant when none was resolved.
+func Tenant(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
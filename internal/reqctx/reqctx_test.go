@@ -0,0 +1,39 @@
+package reqctx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestActor(t *testing.T) {
+	if actor := Actor(context.Background()); actor != DefaultActor {
+		t.Errorf("Expected default actor %s, got %s", DefaultActor, actor)
+	}
+
+	ctx := WithActor(context.Background(), "registrar")
+	if actor := Actor(ctx); actor != "registrar" {
+		t.Errorf("Expected actor registrar, got %s", actor)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	if requestID := RequestID(context.Background()); requestID != "" {
+		t.Errorf("Expected empty request ID, got %s", requestID)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	if requestID := RequestID(ctx); requestID != "abc123" {
+		t.Errorf("Expected request ID abc123, got %s", requestID)
+	}
+}
+
+func TestTenant(t *testing.T) {
+	if tenant := Tenant(context.Background()); tenant != DefaultTenant {
+		t.Errorf("Expected default tenant %s, got %s", DefaultTenant, tenant)
+	}
+
+	ctx := WithTenant(context.Background(), "springfield-high")
+	if tenant := Tenant(ctx); tenant != "springfield-high" {
+		t.Errorf("Expected tenant springfield-high, got %s", tenant)
+	}
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrAttachmentNotFound        = errors.New("attachment not found")
+	ErrAttachmentEmpty           = errors.New("attachment is empty")
+	ErrAttachmentTooLarge        = errors.New("attachment is too large")
+	ErrUnsupportedAttachmentType = errors.New("unsupported attachment type")
+	ErrAttachmentChecksum        = errors.New("attachment checksum does not match")
+)
+
+// sniffLength is how much content http.DetectContentType considers.
+const sniffLength = 512
+
+type AttachmentSettings struct {
+	Store   BlobStore
+	MaxSize int64
+	// AllowedTypes lists the media types accepted, matched against the
+	// type sniffed from the content.
+	AllowedTypes []string
+}
+
+func DefaultAttachmentSettings() AttachmentSettings {
+	return AttachmentSettings{
+		Store:        NewMemoryBlobStore(),
+		MaxSize:      10 << 20,
+		AllowedTypes: []string{"image/jpeg", "image/png", "application/pdf"},
+	}
+}
+
+var (
+	attachmentSettings = DefaultAttachmentSettings()
+	attachmentsMutex   = sync.RWMutex{}
+)
+
+// attachmentStore holds one tenant's attachment metadata. The content lives
+// in the blob store and is deleted with the tenant.
+type attachmentStore struct {
+	attachments      map[int]models.Attachment
+	nextAttachmentID int
+}
+
+var attachmentStores = newTenantStores(&attachmentsMutex, func() *attachmentStore {
+	return &attachmentStore{attachments: make(map[int]models.Attachment), nextAttachmentID: 1}
+}, func(data *attachmentStore) {
+	for _, attachment := range data.attachments {
+		deleteBlob(attachmentSettings.Store, attachment.BlobKey)
+	}
+})
+
+func ConfigureAttachments(settings AttachmentSettings) {
+	attachmentsMutex.Lock()
+	defer attachmentsMutex.Unlock()
+	attachmentSettings = settings
+}
+
+func AttachmentMaxSize() int64 {
+	return currentAttachmentSettings().MaxSize
+}
+
+func currentAttachmentSettings() AttachmentSettings {
+	attachmentsMutex.RLock()
+	defer attachmentsMutex.RUnlock()
+	return attachmentSettings
+}
+
+// AttachmentUpload is a file to attach. Checksum, when set, is the
+// hex-encoded SHA-256 the client expects the content to have.
+type AttachmentUpload struct {
+	Filename string
+	Content  io.Reader
+	Checksum string
+}
+
+// UploadAttachment streams the upload into the blob store. The content type
+// is sniffed from the first bytes of the content, the size is capped while
+// streaming, and an upload whose checksum does not match is discarded.
+func UploadAttachment(ctx context.Context, studentID int, upload AttachmentUpload) (models.Attachment, error) {
+	if _, err := GetStudentByID(ctx, studentID); err != nil {
+		return models.Attachment{}, err
+	}
+	settings := currentAttachmentSettings()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return models.Attachment{}, err
+	}
+	if n == 0 {
+		return models.Attachment{}, ErrAttachmentEmpty
+	}
+	head = head[:n]
+
+	contentType := sniffContentType(head)
+	if !containsString(settings.AllowedTypes, contentType) {
+		return models.Attachment{}, fmt.Errorf("%w: %s", ErrUnsupportedAttachmentType, contentType)
+	}
+
+	key, err := newBlobKey(reqctx.Tenant(ctx), studentID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	hash := sha256.New()
+	content := &maxSizeReader{reader: io.MultiReader(bytes.NewReader(head), upload.Content), remaining: settings.MaxSize}
+	size, err := settings.Store.Put(ctx, key, io.TeeReader(content, hash))
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if upload.Checksum != "" && !strings.EqualFold(upload.Checksum, checksum) {
+		deleteBlob(settings.Store, key)
+		return models.Attachment{}, ErrAttachmentChecksum
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	attachmentsMutex.Lock()
+	defer attachmentsMutex.Unlock()
+
+	// The student, or its whole tenant, may have been deleted while the
+	// content was streaming.
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		deleteBlob(settings.Store, key)
+		return models.Attachment{}, err
+	}
+	data, err := attachmentStores.get(ctx)
+	if err != nil {
+		deleteBlob(settings.Store, key)
+		return models.Attachment{}, err
+	}
+	if student, exists := students.students[studentID]; !exists || student.IsDeleted() {
+		deleteBlob(settings.Store, key)
+		return models.Attachment{}, ErrStudentNotFound
+	}
+
+	attachment := models.Attachment{
+		ID:          data.nextAttachmentID,
+		StudentID:   studentID,
+		Filename:    sanitizeFilename(upload.Filename),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    checksum,
+		UploadedBy:  reqctx.Actor(ctx),
+		UploadedAt:  time.Now().UTC(),
+		BlobKey:     key,
+	}
+	data.nextAttachmentID++
+	data.attachments[attachment.ID] = attachment
+	return attachment, nil
+}
+
+func ListAttachments(ctx context.Context, studentID int) ([]models.Attachment, error) {
+	if _, err := GetStudentByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	attachmentsMutex.RLock()
+	defer attachmentsMutex.RUnlock()
+	data, err := attachmentStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Attachment{}
+	for _, attachment := range data.attachments {
+		if attachment.StudentID == studentID {
+			result = append(result, attachment)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func GetAttachment(ctx context.Context, studentID, id int) (models.Attachment, error) {
+	if _, err := GetStudentByID(ctx, studentID); err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachmentsMutex.RLock()
+	defer attachmentsMutex.RUnlock()
+	data, err := attachmentStores.get(ctx)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment, exists := data.attachments[id]
+	if !exists || attachment.StudentID != studentID {
+		return models.Attachment{}, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns an attachment with a reader over its content, which
+// the caller must close.
+func OpenAttachment(ctx context.Context, studentID, id int) (models.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := GetAttachment(ctx, studentID, id)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	content, err := currentAttachmentSettings().Store.Open(ctx, attachment.BlobKey)
+	if errors.Is(err, ErrBlobNotFound) {
+		return models.Attachment{}, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	return attachment, content, nil
+}
+
+func DeleteAttachment(ctx context.Context, studentID, id int) error {
+	if _, err := GetStudentByID(ctx, studentID); err != nil {
+		return err
+	}
+
+	attachmentsMutex.Lock()
+	data, err := attachmentStores.get(ctx)
+	if err != nil {
+		attachmentsMutex.Unlock()
+		return err
+	}
+	attachment, exists := data.attachments[id]
+	if !exists || attachment.StudentID != studentID {
+		attachmentsMutex.Unlock()
+		return ErrAttachmentNotFound
+	}
+	delete(data.attachments, id)
+	store := attachmentSettings.Store
+	attachmentsMutex.Unlock()
+
+	deleteBlob(store, attachment.BlobKey)
+	return nil
+}
+
+// deleteStudentAttachments removes a purged student's attachments and their
+// content.
+func deleteStudentAttachments(ctx context.Context, studentID int) {
+	attachmentsMutex.Lock()
+	defer attachmentsMutex.Unlock()
+	data, err := attachmentStores.get(ctx)
+	if err != nil {
+		return
+	}
+
+	for id, attachment := range data.attachments {
+		if attachment.StudentID == studentID {
+			delete(data.attachments, id)
+			deleteBlob(attachmentSettings.Store, attachment.BlobKey)
+		}
+	}
+}
+
+// deleteBlob removes content that is no longer referenced. Failures only
+// leave an orphaned blob behind, so they are logged rather than returned.
+func deleteBlob(store BlobStore, key string) {
+	if err := store.Delete(context.Background(), key); err != nil && !errors.Is(err, ErrBlobNotFound) {
+		log.Printf("Failed to delete attachment blob %s: %v", key, err)
+	}
+}
+
+func sniffContentType(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+func newBlobKey(tenantID string, studentID int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/students/%d/%s", tenantID, studentID, hex.EncodeToString(random)), nil
+}
+
+// sanitizeFilename keeps the base name of an uploaded file without control
+// characters, so it is safe to echo back in a Content-Disposition header.
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+// maxSizeReader fails with ErrAttachmentTooLarge once more than remaining
+// bytes have been read.
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, ErrAttachmentTooLarge
+	}
+	return n, err
+}
+
+func ResetAttachments() {
+	attachmentsMutex.Lock()
+	defer attachmentsMutex.Unlock()
+	attachmentStores.reset()
+	attachmentSettings = DefaultAttachmentSettings()
+}
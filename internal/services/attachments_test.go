@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+var pdfContent = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+func setupAttachmentTest() (*MemoryBlobStore, models.Student) {
+	ResetStudents()
+	ResetAttachments()
+
+	store := NewMemoryBlobStore()
+	ConfigureAttachments(AttachmentSettings{Store: store, MaxSize: 1024, AllowedTypes: []string{"application/pdf", "image/png"}})
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	return store, student
+}
+
+func TestUploadAttachment(t *testing.T) {
+	store, student := setupAttachmentTest()
+
+	sum := sha256.Sum256(pdfContent)
+	attachment, err := UploadAttachment(context.Background(), student.ID, AttachmentUpload{
+		Filename: `C:\scans\transcript.pdf`,
+		Content:  bytes.NewReader(pdfContent),
+		Checksum: hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attachment.ContentType != "application/pdf" || attachment.Size != int64(len(pdfContent)) {
+		t.Errorf("Expected a sniffed PDF of %d bytes, got %s of %d", len(pdfContent), attachment.ContentType, attachment.Size)
+	}
+	if attachment.Filename != "transcript.pdf" || attachment.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected attachment metadata: %+v", attachment)
+	}
+
+	_, content, err := OpenAttachment(context.Background(), student.ID, attachment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if !bytes.Equal(data, pdfContent) {
+		t.Error("Expected the stored content to match the upload")
+	}
+
+	if _, err := GetAttachment(context.Background(), student.ID+1, attachment.ID); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound for another student, got %v", err)
+	}
+	if err := DeleteAttachment(context.Background(), student.ID, attachment.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(store.blobs) != 0 {
+		t.Errorf("Expected the blob to be deleted, got %d blobs", len(store.blobs))
+	}
+}
+
+func TestUploadAttachmentRejections(t *testing.T) {
+	store, student := setupAttachmentTest()
+
+	tests := []struct {
+		name     string
+		upload   AttachmentUpload
+		expected error
+	}{
+		{"empty", AttachmentUpload{Filename: "empty.pdf", Content: bytes.NewReader(nil)}, ErrAttachmentEmpty},
+		{"unsupported type", AttachmentUpload{Filename: "notes.pdf", Content: bytes.NewReader([]byte("plain text notes"))}, ErrUnsupportedAttachmentType},
+		{"too large", AttachmentUpload{Filename: "big.pdf", Content: bytes.NewReader(append(pdfContent, make([]byte, 1024)...))}, ErrAttachmentTooLarge},
+		{"checksum mismatch", AttachmentUpload{Filename: "doc.pdf", Content: bytes.NewReader(pdfContent), Checksum: hex.EncodeToString(make([]byte, 32))}, ErrAttachmentChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UploadAttachment(context.Background(), student.ID, tt.upload); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if len(store.blobs) != 0 {
+		t.Errorf("Expected rejected uploads to leave no blobs, got %d", len(store.blobs))
+	}
+	if _, err := UploadAttachment(context.Background(), 99, AttachmentUpload{Content: bytes.NewReader(pdfContent)}); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+}
+
+func TestPurgeRemovesAttachments(t *testing.T) {
+	store, student := setupAttachmentTest()
+
+	attachment, err := UploadAttachment(context.Background(), student.ID, AttachmentUpload{Filename: "doc.pdf", Content: bytes.NewReader(pdfContent)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	DeleteStudent(context.Background(), student.ID)
+	if len(store.blobs) != 1 {
+		t.Fatalf("Expected a soft-deleted student to keep attachments, got %d blobs", len(store.blobs))
+	}
+	RestoreStudent(context.Background(), student.ID)
+	if _, err := GetAttachment(context.Background(), student.ID, attachment.ID); err != nil {
+		t.Errorf("Expected the attachment after restore, got %v", err)
+	}
+
+	DeleteStudent(context.Background(), student.ID)
+	PurgeDeletedStudents(context.Background(), time.Now().Add(time.Minute))
+	records, _ := attachmentStores.get(context.Background())
+	if len(store.blobs) != 0 || len(records.attachments) != 0 {
+		t.Errorf("Expected purge to remove attachments, got %d blobs and %d records", len(store.blobs), len(records.attachments))
+	}
+}
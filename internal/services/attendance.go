@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"student-api/internal/models"
+	"sync"
+	"time"
+)
+
+var ErrInvalidAttendance = errors.New("invalid attendance")
+
+var (
+	attendanceThresholdMutex sync.RWMutex
+	attendanceThreshold      = 0.8
+)
+
+// AttendanceEntry is one student's status in a bulk attendance submission.
+type AttendanceEntry struct {
+	StudentID int                     `json:"student_id"`
+	Status    models.AttendanceStatus `json:"status"`
+}
+
+// AttendanceRange selects attendance records. From and To are inclusive
+// YYYY-MM-DD dates and CourseID a course; each is optional.
+type AttendanceRange struct {
+	From     string
+	To       string
+	CourseID int
+}
+
+func (r AttendanceRange) Validate() error {
+	for _, date := range []string{r.From, r.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(models.DateLayout, date); err != nil {
+			return errors.New("dates must be formatted as YYYY-MM-DD")
+		}
+	}
+	if r.From != "" && r.To != "" && r.From > r.To {
+		return errors.New("from must not be after to")
+	}
+	return nil
+}
+
+// Contains reports whether record falls in the range. Dates in the layout
+// sort lexically, so they are compared as strings.
+func (r AttendanceRange) Contains(record models.AttendanceRecord) bool {
+	if r.CourseID != 0 && record.CourseID != r.CourseID {
+		return false
+	}
+	if r.From != "" && record.Date < r.From {
+		return false
+	}
+	if r.To != "" && record.Date > r.To {
+		return false
+	}
+	return true
+}
+
+// ConfigureAttendanceThreshold sets the default rate below which students
+// are reported.
+func ConfigureAttendanceThreshold(threshold float64) {
+	attendanceThresholdMutex.Lock()
+	defer attendanceThresholdMutex.Unlock()
+	attendanceThreshold = threshold
+}
+
+func AttendanceThreshold() float64 {
+	attendanceThresholdMutex.RLock()
+	defer attendanceThresholdMutex.RUnlock()
+	return attendanceThreshold
+}
+
+// RecordAttendance records a class session: the status of each listed
+// student in the course on date. Every student must be actively enrolled,
+// and nothing is recorded unless all entries are valid. Entries for a
+// student who already has a record for the session replace it.
+func RecordAttendance(ctx context.Context, courseID int, date string, entries []AttendanceEntry) ([]models.AttendanceRecord, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: at least one record is required", ErrInvalidAttendance)
+	}
+	seen := make(map[int]bool)
+	for _, entry := range entries {
+		record := models.AttendanceRecord{Date: date, Status: entry.Status}
+		if err := record.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAttendance, err)
+		}
+		if seen[entry.StudentID] {
+			return nil, fmt.Errorf("%w: student %d is listed more than once", ErrInvalidAttendance, entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := data.courses[courseID]; !exists {
+		return nil, ErrCourseNotFound
+	}
+	active := activeStudents(data, courseID)
+	for _, entry := range entries {
+		if student, exists := students.students[entry.StudentID]; !exists || student.IsDeleted() {
+			return nil, fmt.Errorf("%w: student %d", ErrStudentNotFound, entry.StudentID)
+		}
+		if !active[entry.StudentID] {
+			return nil, fmt.Errorf("%w: student %d", ErrNotEnrolled, entry.StudentID)
+		}
+	}
+
+	existing := sessionAttendance(data, courseID, date)
+	now := time.Now().UTC()
+	recorded := make([]models.AttendanceRecord, 0, len(entries))
+	for _, entry := range entries {
+		record := models.AttendanceRecord{
+			StudentID:  entry.StudentID,
+			CourseID:   courseID,
+			Date:       date,
+			Status:     entry.Status,
+			RecordedAt: now,
+		}
+		if id, ok := existing[entry.StudentID]; ok {
+			record.ID = id
+		} else {
+			record.ID = data.nextAttendanceID
+			data.nextAttendanceID++
+		}
+		data.attendance[record.ID] = record
+		recorded = append(recorded, record)
+	}
+	return recorded, nil
+}
+
+// GetCourseAttendance returns a course's attendance records in the range,
+// ordered by date and student.
+func GetCourseAttendance(ctx context.Context, courseID int, r AttendanceRange) ([]models.AttendanceRecord, error) {
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := data.courses[courseID]; !exists {
+		return nil, ErrCourseNotFound
+	}
+	r.CourseID = courseID
+	return attendanceInRange(data, func(record models.AttendanceRecord) bool { return r.Contains(record) }), nil
+}
+
+// GetStudentAttendance summarises an existing student's attendance in the
+// range.
+func GetStudentAttendance(ctx context.Context, studentID int, r AttendanceRange) (models.StudentAttendance, error) {
+	student, err := GetStudentByID(ctx, studentID)
+	if err != nil {
+		return models.StudentAttendance{}, err
+	}
+
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.StudentAttendance{}, err
+	}
+
+	result := models.StudentAttendance{
+		AttendanceSummary: models.AttendanceSummary{StudentID: student.ID, StudentName: student.Name},
+		Records: attendanceInRange(data, func(record models.AttendanceRecord) bool {
+			return record.StudentID == studentID && r.Contains(record)
+		}),
+	}
+	for _, record := range result.Records {
+		result.Add(record)
+	}
+	return result, nil
+}
+
+// GetAttendanceReport summarises attendance in the range for every student
+// with a record in it, ordered by student ID.
+func GetAttendanceReport(ctx context.Context, r AttendanceRange) models.AttendanceReport {
+	report := models.AttendanceReport{From: r.From, To: r.To, CourseID: r.CourseID, Students: make([]models.AttendanceSummary, 0)}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return report
+	}
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return report
+	}
+
+	summaries := make(map[int]*models.AttendanceSummary)
+	for _, record := range data.attendance {
+		student, exists := students.students[record.StudentID]
+		if !exists || student.IsDeleted() || !r.Contains(record) {
+			continue
+		}
+		summary, ok := summaries[record.StudentID]
+		if !ok {
+			summary = &models.AttendanceSummary{StudentID: student.ID, StudentName: student.Name}
+			summaries[record.StudentID] = summary
+		}
+		summary.Add(record)
+	}
+
+	for _, summary := range summaries {
+		report.Students = append(report.Students, *summary)
+	}
+	sort.Slice(report.Students, func(i, j int) bool { return report.Students[i].StudentID < report.Students[j].StudentID })
+	return report
+}
+
+// GetLowAttendanceReport lists the students whose attendance rate in the
+// range is below threshold, lowest rate first.
+func GetLowAttendanceReport(ctx context.Context, r AttendanceRange, threshold float64) models.AttendanceReport {
+	report := GetAttendanceReport(ctx, r)
+	report.Threshold = &threshold
+
+	below := make([]models.AttendanceSummary, 0)
+	for _, summary := range report.Students {
+		if summary.Rate != nil && *summary.Rate < threshold {
+			below = append(below, summary)
+		}
+	}
+	sort.SliceStable(below, func(i, j int) bool { return *below[i].Rate < *below[j].Rate })
+	report.Students = below
+	return report
+}
+
+// attendanceInRange returns the matching records ordered by date, course and
+// student. Callers hold courseMutex.
+func attendanceInRange(data *courseStore, match func(record models.AttendanceRecord) bool) []models.AttendanceRecord {
+	result := make([]models.AttendanceRecord, 0)
+	for _, record := range data.attendance {
+		if match(record) {
+			result = append(result, record)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.CourseID != b.CourseID {
+			return a.CourseID < b.CourseID
+		}
+		return a.StudentID < b.StudentID
+	})
+	return result
+}
+
+// sessionAttendance returns the IDs of a session's records keyed by student,
+// so a whole session is looked up in one pass. Callers hold courseMutex.
+func sessionAttendance(data *courseStore, courseID int, date string) map[int]int {
+	result := make(map[int]int)
+	for id, record := range data.attendance {
+		if record.CourseID == courseID && record.Date == date {
+			result[record.StudentID] = id
+		}
+	}
+	return result
+}
+
+// activeStudents returns the set of students actively enrolled in the
+// course. Callers hold courseMutex.
+func activeStudents(data *courseStore, courseID int) map[int]bool {
+	result := make(map[int]bool)
+	for _, enrollment := range data.enrollments {
+		if enrollment.CourseID == courseID && enrollment.Status == models.EnrollmentActive {
+			result[enrollment.StudentID] = true
+		}
+	}
+	return result
+}
+
+// deleteAttendance removes the records that match. Callers hold courseMutex.
+func deleteAttendance(data *courseStore, match func(record models.AttendanceRecord) bool) {
+	for id, record := range data.attendance {
+		if match(record) {
+			delete(data.attendance, id)
+		}
+	}
+}
+
+func ResetAttendance() {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	courseStores.each(func(_ string, data *courseStore) {
+		data.attendance = make(map[int]models.AttendanceRecord)
+		data.nextAttendanceID = 1
+	})
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func setupAttendanceTest(t *testing.T) (models.Course, []models.Student) {
+	ResetStudents()
+	ResetCourses()
+	ResetAttendance()
+
+	course, _ := CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro to Programming", Capacity: 10})
+	var created []models.Student
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		student, _ := CreateStudent(context.Background(), models.Student{Name: name, Age: 20, Email: name + "@example.com"})
+		if _, err := EnrollStudent(context.Background(), student.ID, course.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		created = append(created, student)
+	}
+	return course, created
+}
+
+func recordSession(t *testing.T, courseID int, date string, statuses ...models.AttendanceStatus) {
+	t.Helper()
+	var entries []AttendanceEntry
+	for i, status := range statuses {
+		entries = append(entries, AttendanceEntry{StudentID: i + 1, Status: status})
+	}
+	if _, err := RecordAttendance(context.Background(), courseID, date, entries); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestRecordAttendance(t *testing.T) {
+	course, created := setupAttendanceTest(t)
+
+	recordSession(t, course.ID, "2026-10-05", models.AttendancePresent, models.AttendanceAbsent)
+	records, err := RecordAttendance(context.Background(), course.ID, "2026-10-05", []AttendanceEntry{{StudentID: created[1].ID, Status: models.AttendanceExcused}})
+	if err != nil || records[0].ID != 2 {
+		t.Fatalf("Expected the session record to be replaced, got %+v, %v", records, err)
+	}
+
+	session, _ := GetCourseAttendance(context.Background(), course.ID, AttendanceRange{From: "2026-10-05", To: "2026-10-05"})
+	if len(session) != 2 || session[1].Status != models.AttendanceExcused {
+		t.Errorf("Expected two records with Bob excused, got %+v", session)
+	}
+
+	invalid := []struct {
+		name    string
+		date    string
+		entries []AttendanceEntry
+		wantErr error
+	}{
+		{"no records", "2026-10-06", nil, ErrInvalidAttendance},
+		{"bad date", "06/10/2026", []AttendanceEntry{{StudentID: 1, Status: models.AttendancePresent}}, ErrInvalidAttendance},
+		{"bad status", "2026-10-06", []AttendanceEntry{{StudentID: 1, Status: "sick"}}, ErrInvalidAttendance},
+		{"duplicate student", "2026-10-06", []AttendanceEntry{{StudentID: 1, Status: models.AttendancePresent}, {StudentID: 1, Status: models.AttendanceLate}}, ErrInvalidAttendance},
+		{"unknown student", "2026-10-06", []AttendanceEntry{{StudentID: 1, Status: models.AttendancePresent}, {StudentID: 99, Status: models.AttendancePresent}}, ErrStudentNotFound},
+	}
+	for _, tt := range invalid {
+		if _, err := RecordAttendance(context.Background(), course.ID, tt.date, tt.entries); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+
+	enrollments := GetStudentEnrollments(context.Background(), created[2].ID)
+	UpdateEnrollmentStatus(context.Background(), created[2].ID, enrollments[0].ID, models.EnrollmentDropped)
+	if _, err := RecordAttendance(context.Background(), course.ID, "2026-10-06", []AttendanceEntry{{StudentID: created[0].ID, Status: models.AttendancePresent}, {StudentID: created[2].ID, Status: models.AttendancePresent}}); !errors.Is(err, ErrNotEnrolled) {
+		t.Errorf("Expected ErrNotEnrolled for a dropped student, got %v", err)
+	}
+	if records, _ := GetCourseAttendance(context.Background(), course.ID, AttendanceRange{From: "2026-10-06"}); len(records) != 0 {
+		t.Errorf("Expected a rejected session to record nothing, got %+v", records)
+	}
+	if _, err := RecordAttendance(context.Background(), 99, "2026-10-06", []AttendanceEntry{{StudentID: 1, Status: models.AttendancePresent}}); err != ErrCourseNotFound {
+		t.Errorf("Expected ErrCourseNotFound, got %v", err)
+	}
+}
+
+func TestAttendanceReports(t *testing.T) {
+	course, created := setupAttendanceTest(t)
+
+	recordSession(t, course.ID, "2026-09-28", models.AttendancePresent, models.AttendanceAbsent, models.AttendancePresent)
+	recordSession(t, course.ID, "2026-10-05", models.AttendancePresent, models.AttendanceAbsent, models.AttendanceAbsent)
+	recordSession(t, course.ID, "2026-10-12", models.AttendanceLate, models.AttendancePresent, models.AttendanceExcused)
+
+	alice, err := GetStudentAttendance(context.Background(), created[0].ID, AttendanceRange{})
+	if err != nil || alice.Sessions != 3 || *alice.Rate != 1 || len(alice.Records) != 3 {
+		t.Errorf("Expected Alice to attend every session, got %+v, %v", alice, err)
+	}
+
+	october := AttendanceRange{From: "2026-10-01", To: "2026-10-31"}
+	carol, _ := GetStudentAttendance(context.Background(), created[2].ID, october)
+	if carol.Sessions != 2 || carol.Excused != 1 || *carol.Rate != 0 {
+		t.Errorf("Expected Carol to miss October's only counted session, got %+v", carol)
+	}
+
+	report := GetAttendanceReport(context.Background(), AttendanceRange{})
+	if len(report.Students) != 3 || report.Students[1].StudentName != "Bob" {
+		t.Fatalf("Expected all three students in the report, got %+v", report)
+	}
+
+	low := GetLowAttendanceReport(context.Background(), AttendanceRange{}, 0.8)
+	if len(low.Students) != 2 || low.Students[0].StudentID != created[1].ID || low.Students[1].StudentID != created[2].ID || *low.Threshold != 0.8 {
+		t.Errorf("Expected Bob (1/3) then Carol (1/2) below 0.8, got %+v", low.Students)
+	}
+	if low := GetLowAttendanceReport(context.Background(), october, 0.5); len(low.Students) != 1 || low.Students[0].StudentID != created[2].ID {
+		t.Errorf("Expected only Carol below 0.5 in October, got %+v", low.Students)
+	}
+
+	if _, err := GetStudentAttendance(context.Background(), 99, AttendanceRange{}); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+	if err := (AttendanceRange{From: "2026-10-31", To: "2026-10-01"}).Validate(); err == nil {
+		t.Error("Expected error for a reversed range")
+	}
+}
+
+func TestAttendanceRemovedOnPurge(t *testing.T) {
+	ctx := context.Background()
+	course, created := setupAttendanceTest(t)
+	recordSession(t, course.ID, "2026-10-05", models.AttendancePresent, models.AttendancePresent)
+
+	DeleteStudent(ctx, created[0].ID)
+	if report := GetAttendanceReport(context.Background(), AttendanceRange{}); len(report.Students) != 1 {
+		t.Errorf("Expected deleted students to be left out of reports, got %+v", report.Students)
+	}
+
+	PurgeDeletedStudents(ctx, time.Now().Add(time.Minute))
+	if records, _ := GetCourseAttendance(context.Background(), course.ID, AttendanceRange{}); len(records) != 1 || records[0].StudentID != created[1].ID {
+		t.Errorf("Expected purged student's attendance to be removed, got %+v", records)
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+// auditMutex guards the audit log file and the audit events of every tenant.
+var (
+	auditLogFile *os.File
+	auditMutex   = sync.RWMutex{}
+)
+
+// auditStore holds one tenant's audit events.
+type auditStore struct {
+	auditEvents []models.AuditEvent
+	nextAuditID int
+}
+
+var auditStores = newTenantStores(&auditMutex, func() *auditStore {
+	return &auditStore{nextAuditID: 1}
+}, nil)
+
+// OpenAuditLog loads previously persisted audit events from path and appends
+// every new event to it as a JSON line. Each loaded event is returned to its
+// tenant's log; events written before tenants existed belong to the default
+// tenant, and events of tenants that no longer exist are left in the file
+// but not loaded.
+func OpenAuditLog(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string][]models.AuditEvent)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			file.Close()
+			return err
+		}
+		if event.Tenant == "" {
+			event.Tenant = reqctx.DefaultTenant
+		}
+		loaded[event.Tenant] = append(loaded[event.Tenant], event)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return err
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	if auditLogFile != nil {
+		auditLogFile.Close()
+	}
+	auditLogFile = file
+	auditStores.reset()
+	for tenantID, events := range loaded {
+		data, exists := auditStores.lookup(tenantID)
+		if !exists {
+			continue
+		}
+		data.auditEvents = events
+		data.nextAuditID = 1
+		for _, event := range events {
+			if event.ID >= data.nextAuditID {
+				data.nextAuditID = event.ID + 1
+			}
+		}
+	}
+	return nil
+}
+
+func CloseAuditLog() error {
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	if auditLogFile == nil {
+		return nil
+	}
+	err := auditLogFile.Close()
+	auditLogFile = nil
+	return err
+}
+
+func recordAudit(ctx context.Context, action models.AuditAction, studentID int, before, after *models.Student) {
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+	data, err := auditStores.get(ctx)
+	if err != nil {
+		return
+	}
+
+	event := models.AuditEvent{
+		ID:        data.nextAuditID,
+		Tenant:    reqctx.Tenant(ctx),
+		StudentID: studentID,
+		Action:    action,
+		Actor:     reqctx.Actor(ctx),
+		RequestID: reqctx.RequestID(ctx),
+		Timestamp: time.Now().UTC(),
+		Changes:   models.DiffStudents(before, after),
+	}
+	data.nextAuditID++
+	data.auditEvents = append(data.auditEvents, event)
+
+	if auditLogFile != nil {
+		line, err := json.Marshal(event)
+		if err == nil {
+			_, err = auditLogFile.Write(append(line, '\n'))
+		}
+		if err != nil {
+			log.Printf("failed to persist audit event %d: %v", event.ID, err)
+		}
+	}
+}
+
+// GetStudentHistory returns the audit events of student. Student IDs start
+// again from 1 when the server restarts while the audit log is reloaded, so
+// only events recorded since the student was created are its own; those of
+// an earlier student with the same ID are left out.
+func GetStudentHistory(ctx context.Context, student models.Student) []models.AuditEvent {
+	auditMutex.RLock()
+	defer auditMutex.RUnlock()
+	data, err := auditStores.get(ctx)
+	if err != nil {
+		return []models.AuditEvent{}
+	}
+
+	result := []models.AuditEvent{}
+	for _, event := range data.auditEvents {
+		if event.StudentID == student.ID && !event.Timestamp.Before(student.CreatedAt) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func GetAuditEvents(ctx context.Context, since time.Time) []models.AuditEvent {
+	auditMutex.RLock()
+	defer auditMutex.RUnlock()
+	data, err := auditStores.get(ctx)
+	if err != nil {
+		return []models.AuditEvent{}
+	}
+
+	result := []models.AuditEvent{}
+	for _, event := range data.auditEvents {
+		if !event.Timestamp.Before(since) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func ResetAuditLog() {
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+	auditStores.reset()
+}
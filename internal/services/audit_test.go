@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"testing"
+	"time"
+)
+
+func TestAuditEventsRecorded(t *testing.T) {
+	ResetStudents()
+	ResetAuditLog()
+
+	ctx := reqctx.WithRequestID(reqctx.WithActor(context.Background(), "registrar"), "req-1")
+
+	student, _ := CreateStudent(ctx, models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	UpdateStudent(ctx, student.ID, models.Student{Name: "John Doe", Age: 21, Email: "john@example.com"})
+	DeleteStudent(ctx, student.ID)
+
+	history := GetStudentHistory(context.Background(), student)
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(history))
+	}
+
+	expectedActions := []models.AuditAction{models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete}
+	for i, action := range expectedActions {
+		if history[i].Action != action {
+			t.Errorf("Expected action %s, got %s", action, history[i].Action)
+		}
+		if history[i].Actor != "registrar" || history[i].RequestID != "req-1" {
+			t.Errorf("Expected actor and request ID to be recorded, got %+v", history[i])
+		}
+	}
+
+	if len(history[1].Changes) != 1 || history[1].Changes[0].Field != "age" {
+		t.Errorf("Expected a single age change, got %+v", history[1].Changes)
+	}
+
+	if events := GetAuditEvents(context.Background(), time.Now().Add(time.Hour)); len(events) != 0 {
+		t.Errorf("Expected no events in the future, got %d", len(events))
+	}
+}
+
+func TestAuditLogPersistence(t *testing.T) {
+	ResetStudents()
+	ResetAuditLog()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := OpenAuditLog(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jane, _ := CreateStudent(context.Background(), models.Student{Name: "Jane Doe", Age: 22, Email: "jane@example.com"})
+	CloseAuditLog()
+	ResetAuditLog()
+
+	if err := OpenAuditLog(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer CloseAuditLog()
+
+	history := GetStudentHistory(context.Background(), jane)
+	if len(history) != 1 {
+		t.Fatalf("Expected 1 persisted event, got %d", len(history))
+	}
+	if history[0].Action != models.AuditActionCreate {
+		t.Errorf("Expected create action, got %s", history[0].Action)
+	}
+
+	CreateStudent(context.Background(), models.Student{Name: "Jim Doe", Age: 23, Email: "jim@example.com"})
+	if events := GetAuditEvents(context.Background(), time.Time{}); events[len(events)-1].ID != 2 {
+		t.Errorf("Expected audit IDs to continue after reload, got %d", events[len(events)-1].ID)
+	}
+}
+
+func TestAuditHistoryAfterRestart(t *testing.T) {
+	ResetStudents()
+	ResetAuditLog()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := OpenAuditLog(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	jane, _ := CreateStudent(context.Background(), models.Student{Name: "Jane Doe", Age: 22, Email: "jane@example.com"})
+	UpdateStudent(context.Background(), jane.ID, models.Student{Name: "Jane Doe", Age: 23, Email: "jane@example.com"})
+	CloseAuditLog()
+
+	// A restart empties the student store, so the next student reuses ID 1.
+	ResetStudents()
+	ResetAuditLog()
+	if err := OpenAuditLog(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer CloseAuditLog()
+
+	time.Sleep(time.Millisecond)
+	jim, _ := CreateStudent(context.Background(), models.Student{Name: "Jim Doe", Age: 30, Email: "jim@example.com"})
+	if jim.ID != jane.ID {
+		t.Fatalf("Expected the new student to reuse ID %d, got %d", jane.ID, jim.ID)
+	}
+
+	history := GetStudentHistory(context.Background(), jim)
+	if len(history) != 1 || history[0].Action != models.AuditActionCreate {
+		t.Fatalf("Expected only the new student's create event, got %+v", history)
+	}
+	if events := GetAuditEvents(context.Background(), time.Time{}); len(events) != 3 {
+		t.Errorf("Expected the reloaded events to stay in the audit log, got %d", len(events))
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBlobStores(t *testing.T) {
+	local, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stores := map[string]BlobStore{"local": local, "memory": NewMemoryBlobStore()}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			size, err := store.Put(ctx, "students/1/blob", strings.NewReader("hello world"))
+			if err != nil || size != 11 {
+				t.Fatalf("Expected 11 bytes stored, got %d, %v", size, err)
+			}
+
+			content, err := store.Open(ctx, "students/1/blob")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			content.Seek(6, io.SeekStart)
+			data, _ := io.ReadAll(content)
+			content.Close()
+			if string(data) != "world" {
+				t.Errorf("Expected to read from the seek offset, got %q", data)
+			}
+
+			if err := store.Delete(ctx, "students/1/blob"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := store.Open(ctx, "students/1/blob"); err != ErrBlobNotFound {
+				t.Errorf("Expected ErrBlobNotFound, got %v", err)
+			}
+			if err := store.Delete(ctx, "students/1/blob"); err != ErrBlobNotFound {
+				t.Errorf("Expected ErrBlobNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLocalBlobStoreRejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, key := range []string{"", "../outside", "students/../../outside", "/etc/passwd"} {
+		if _, err := store.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
+
+func TestLocalBlobStoreDiscardsFailedPut(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	content := &maxSizeReader{reader: strings.NewReader("too much content"), remaining: 4}
+	if _, err := store.Put(context.Background(), "blob", content); err != ErrAttachmentTooLarge {
+		t.Fatalf("Expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if _, err := store.Open(context.Background(), "blob"); err != ErrBlobNotFound {
+		t.Errorf("Expected no blob after a failed put, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker fails fast after FailureThreshold consecutive failures. Once
+// ResetTimeout has passed a single trial call is let through; its outcome
+// closes or re-opens the circuit.
+type CircuitBreaker struct {
+	FailureThreshold int
+	ResetTimeout     time.Duration
+
+	mutex    sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		ResetTimeout:     resetTimeout,
+		now:              time.Now,
+	}
+}
+
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == circuitClosed {
+		return true
+	}
+	// A trial that never reports back (e.g. the caller went away) must not
+	// wedge the breaker, so another trial is allowed after each ResetTimeout.
+	if cb.now().Sub(cb.openedAt) < cb.ResetTimeout {
+		return false
+	}
+	cb.state = circuitHalfOpen
+	cb.openedAt = cb.now()
+	return true
+}
+
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.state = circuitClosed
+	cb.failures = 0
+}
+
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.FailureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.state == circuitOpen && cb.now().Sub(cb.openedAt) < cb.ResetTimeout
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	if !cb.Allow() {
+		t.Fatal("Expected closed circuit to allow calls")
+	}
+
+	cb.RecordFailure()
+	if !cb.Allow() {
+		t.Fatal("Expected circuit to stay closed below the threshold")
+	}
+
+	cb.RecordFailure()
+	if cb.Allow() || !cb.IsOpen() {
+		t.Fatal("Expected circuit to open at the threshold")
+	}
+
+	now = now.Add(time.Minute)
+	if !cb.Allow() {
+		t.Fatal("Expected a trial call after the reset timeout")
+	}
+	if cb.Allow() {
+		t.Fatal("Expected only one trial call while half-open")
+	}
+
+	cb.RecordFailure()
+	if cb.Allow() {
+		t.Fatal("Expected failed trial to re-open the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	cb.Allow()
+	cb.RecordSuccess()
+	if !cb.Allow() || cb.IsOpen() {
+		t.Fatal("Expected successful trial to close the circuit")
+	}
+}
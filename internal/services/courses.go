@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"student-api/internal/models"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCourseNotFound              = errors.New("course not found")
+	ErrCourseCodeTaken             = errors.New("course code already in use")
+	ErrCourseFull                  = errors.New("course is full")
+	ErrCourseHasEnrollments        = errors.New("course has active enrollments")
+	ErrCapacityBelowEnrollment     = errors.New("capacity is below the number of active enrollments")
+	ErrEnrollmentNotFound          = errors.New("enrollment not found")
+	ErrAlreadyEnrolled             = errors.New("student is already enrolled in course")
+	ErrInvalidEnrollmentTransition = errors.New("invalid enrollment status transition")
+)
+
+// Courses, enrollments, grades and attendance of every tenant share
+// courseMutex. Operations that also read students take the student mutex
+// first, so the two are always acquired in the same order.
+var courseMutex = sync.RWMutex{}
+
+// courseStore holds one tenant's courses, enrollments, grades and
+// attendance.
+type courseStore struct {
+	courses          map[int]models.Course
+	nextCourseID     int
+	enrollments      map[int]models.Enrollment
+	nextEnrollmentID int
+	grades           map[int]models.Grade
+	nextGradeID      int
+	attendance       map[int]models.AttendanceRecord
+	nextAttendanceID int
+}
+
+var courseStores = newTenantStores(&courseMutex, func() *courseStore {
+	return &courseStore{
+		courses:          make(map[int]models.Course),
+		nextCourseID:     1,
+		enrollments:      make(map[int]models.Enrollment),
+		nextEnrollmentID: 1,
+		grades:           make(map[int]models.Grade),
+		nextGradeID:      1,
+		attendance:       make(map[int]models.AttendanceRecord),
+		nextAttendanceID: 1,
+	}
+}, nil)
+
+func CreateCourse(ctx context.Context, course models.Course) (models.Course, error) {
+	if err := course.Validate(); err != nil {
+		return models.Course{}, err
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Course{}, err
+	}
+
+	if courseCodeTaken(data, course.Code, 0) {
+		return models.Course{}, ErrCourseCodeTaken
+	}
+	course.ID = data.nextCourseID
+	data.nextCourseID++
+	data.courses[course.ID] = course
+	return course, nil
+}
+
+func ListCourses(ctx context.Context) []models.Course {
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return []models.Course{}
+	}
+
+	result := make([]models.Course, 0, len(data.courses))
+	for _, course := range data.courses {
+		result = append(result, course)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func GetCourse(ctx context.Context, id int) (models.Course, error) {
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Course{}, err
+	}
+
+	course, exists := data.courses[id]
+	if !exists {
+		return models.Course{}, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// UpdateCourse replaces a course. Its capacity may not drop below the number
+// of students currently enrolled.
+func UpdateCourse(ctx context.Context, id int, course models.Course) (models.Course, error) {
+	if err := course.Validate(); err != nil {
+		return models.Course{}, err
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Course{}, err
+	}
+
+	if _, exists := data.courses[id]; !exists {
+		return models.Course{}, ErrCourseNotFound
+	}
+	if courseCodeTaken(data, course.Code, id) {
+		return models.Course{}, ErrCourseCodeTaken
+	}
+	if active := activeEnrollmentCount(data, id); course.Capacity < active {
+		return models.Course{}, fmt.Errorf("%w (%d enrolled)", ErrCapacityBelowEnrollment, active)
+	}
+
+	course.ID = id
+	data.courses[id] = course
+	return course, nil
+}
+
+// DeleteCourse removes a course with its enrollment history, grades and
+// attendance. A course with active enrollments cannot be deleted until they
+// are completed or dropped.
+func DeleteCourse(ctx context.Context, id int) error {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := data.courses[id]; !exists {
+		return ErrCourseNotFound
+	}
+	if activeEnrollmentCount(data, id) > 0 {
+		return ErrCourseHasEnrollments
+	}
+
+	delete(data.courses, id)
+	for enrollmentID, enrollment := range data.enrollments {
+		if enrollment.CourseID == id {
+			delete(data.enrollments, enrollmentID)
+		}
+	}
+	deleteGrades(data, func(grade models.Grade) bool { return grade.CourseID == id })
+	deleteAttendance(data, func(record models.AttendanceRecord) bool { return record.CourseID == id })
+	return nil
+}
+
+// EnrollStudent enrolls a student in a course with a free seat. A student has
+// at most one enrollment per course; a dropped enrollment is reactivated by
+// changing its status rather than enrolling again.
+func EnrollStudent(ctx context.Context, studentID, courseID int) (models.Enrollment, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	if student, exists := students.students[studentID]; !exists || student.IsDeleted() {
+		return models.Enrollment{}, ErrStudentNotFound
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	course, exists := data.courses[courseID]
+	if !exists {
+		return models.Enrollment{}, ErrCourseNotFound
+	}
+	for _, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID && enrollment.CourseID == courseID {
+			return models.Enrollment{}, ErrAlreadyEnrolled
+		}
+	}
+	if activeEnrollmentCount(data, courseID) >= course.Capacity {
+		return models.Enrollment{}, ErrCourseFull
+	}
+
+	now := time.Now().UTC()
+	enrollment := models.Enrollment{
+		ID:         data.nextEnrollmentID,
+		StudentID:  studentID,
+		CourseID:   courseID,
+		Status:     models.EnrollmentActive,
+		EnrolledAt: now,
+		UpdatedAt:  now,
+	}
+	data.nextEnrollmentID++
+	data.enrollments[enrollment.ID] = enrollment
+	return enrollment, nil
+}
+
+func GetStudentEnrollments(ctx context.Context, studentID int) []models.Enrollment {
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return []models.Enrollment{}
+	}
+
+	result := make([]models.Enrollment, 0)
+	for _, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID {
+			result = append(result, enrollment)
+		}
+	}
+	sortEnrollments(result)
+	return result
+}
+
+// UpdateEnrollmentStatus moves one of a student's enrollments to status.
+// Reactivating a dropped enrollment needs a free seat in the course.
+func UpdateEnrollmentStatus(ctx context.Context, studentID, enrollmentID int, status models.EnrollmentStatus) (models.Enrollment, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	if student, exists := students.students[studentID]; !exists || student.IsDeleted() {
+		return models.Enrollment{}, ErrStudentNotFound
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	enrollment, exists := data.enrollments[enrollmentID]
+	if !exists || enrollment.StudentID != studentID {
+		return models.Enrollment{}, ErrEnrollmentNotFound
+	}
+	if !enrollment.Status.CanTransitionTo(status) {
+		return models.Enrollment{}, fmt.Errorf("%w: %s to %s", ErrInvalidEnrollmentTransition, enrollment.Status, status)
+	}
+	if status == models.EnrollmentActive && activeEnrollmentCount(data, enrollment.CourseID) >= data.courses[enrollment.CourseID].Capacity {
+		return models.Enrollment{}, ErrCourseFull
+	}
+
+	enrollment.Status = status
+	enrollment.UpdatedAt = time.Now().UTC()
+	data.enrollments[enrollmentID] = enrollment
+	return enrollment, nil
+}
+
+// GetCourseRoster returns the course's enrollments with the given status,
+// each with its student attached.
+func GetCourseRoster(ctx context.Context, courseID int, status models.EnrollmentStatus) ([]models.Enrollment, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := data.courses[courseID]; !exists {
+		return nil, ErrCourseNotFound
+	}
+
+	result := make([]models.Enrollment, 0)
+	for _, enrollment := range data.enrollments {
+		if enrollment.CourseID != courseID || enrollment.Status != status {
+			continue
+		}
+		student, exists := students.students[enrollment.StudentID]
+		if exists && student.IsDeleted() {
+			continue
+		}
+		if exists {
+			enrollment.Student = &student
+		}
+		result = append(result, enrollment)
+	}
+	sortEnrollments(result)
+	return result, nil
+}
+
+// dropStudentEnrollments drops the active enrollments of a deleted student,
+// freeing their seats. Callers hold the student mutex.
+func dropStudentEnrollments(ctx context.Context, studentID int) {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	for id, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID && enrollment.Status == models.EnrollmentActive {
+			enrollment.Status = models.EnrollmentDropped
+			enrollment.UpdatedAt = now
+			data.enrollments[id] = enrollment
+		}
+	}
+}
+
+// deleteStudentEnrollments removes a purged student's enrollments, grades
+// and attendance.
+func deleteStudentEnrollments(ctx context.Context, studentID int) {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return
+	}
+
+	for id, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID {
+			delete(data.enrollments, id)
+		}
+	}
+	deleteGrades(data, func(grade models.Grade) bool { return grade.StudentID == studentID })
+	deleteAttendance(data, func(record models.AttendanceRecord) bool { return record.StudentID == studentID })
+}
+
+// activeEnrollmentCount returns the seats taken in a course. Callers hold
+// courseMutex.
+func activeEnrollmentCount(data *courseStore, courseID int) int {
+	count := 0
+	for _, enrollment := range data.enrollments {
+		if enrollment.CourseID == courseID && enrollment.Status == models.EnrollmentActive {
+			count++
+		}
+	}
+	return count
+}
+
+func courseCodeTaken(data *courseStore, code string, exceptID int) bool {
+	for _, course := range data.courses {
+		if course.ID != exceptID && course.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func sortEnrollments(list []models.Enrollment) {
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+}
+
+func ResetCourses() {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	courseStores.each(func(_ string, data *courseStore) {
+		data.courses = make(map[int]models.Course)
+		data.nextCourseID = 1
+		data.enrollments = make(map[int]models.Enrollment)
+		data.nextEnrollmentID = 1
+		// Grades and attendance refer to courses by ID, so they go too.
+		data.grades = make(map[int]models.Grade)
+		data.nextGradeID = 1
+		data.attendance = make(map[int]models.AttendanceRecord)
+		data.nextAttendanceID = 1
+	})
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func setupCourseTest(t *testing.T, capacity int) (models.Course, []models.Student) {
+	ResetStudents()
+	ResetCourses()
+
+	course, err := CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro to Programming", Capacity: capacity})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var created []models.Student
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		student, _ := CreateStudent(context.Background(), models.Student{Name: name, Age: 20, Email: name + "@example.com"})
+		created = append(created, student)
+	}
+	return course, created
+}
+
+func TestCourseCRUD(t *testing.T) {
+	course, _ := setupCourseTest(t, 2)
+
+	if _, err := CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Duplicate", Capacity: 5}); err != ErrCourseCodeTaken {
+		t.Errorf("Expected ErrCourseCodeTaken, got %v", err)
+	}
+	if _, err := CreateCourse(context.Background(), models.Course{Code: "CS102"}); err == nil {
+		t.Error("Expected validation error for incomplete course")
+	}
+
+	updated, err := UpdateCourse(context.Background(), course.ID, models.Course{Code: "CS101", Title: "Programming I", Capacity: 3})
+	if err != nil || updated.Title != "Programming I" || updated.ID != course.ID {
+		t.Fatalf("Expected updated course, got %+v, %v", updated, err)
+	}
+	if _, err := UpdateCourse(context.Background(), 99, updated); err != ErrCourseNotFound {
+		t.Errorf("Expected ErrCourseNotFound, got %v", err)
+	}
+
+	if err := DeleteCourse(context.Background(), course.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := GetCourse(context.Background(), course.ID); err != ErrCourseNotFound {
+		t.Errorf("Expected ErrCourseNotFound after delete, got %v", err)
+	}
+}
+
+func TestEnrollStudentCapacity(t *testing.T) {
+	course, created := setupCourseTest(t, 2)
+
+	for _, student := range created[:2] {
+		if _, err := EnrollStudent(context.Background(), student.ID, course.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if _, err := EnrollStudent(context.Background(), created[2].ID, course.ID); err != ErrCourseFull {
+		t.Errorf("Expected ErrCourseFull, got %v", err)
+	}
+	if _, err := EnrollStudent(context.Background(), created[0].ID, course.ID); err != ErrAlreadyEnrolled {
+		t.Errorf("Expected ErrAlreadyEnrolled, got %v", err)
+	}
+	if _, err := EnrollStudent(context.Background(), 99, course.ID); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+	if _, err := EnrollStudent(context.Background(), created[2].ID, 99); err != ErrCourseNotFound {
+		t.Errorf("Expected ErrCourseNotFound, got %v", err)
+	}
+
+	if _, err := UpdateCourse(context.Background(), course.ID, models.Course{Code: "CS101", Title: "Intro", Capacity: 1}); !errors.Is(err, ErrCapacityBelowEnrollment) {
+		t.Errorf("Expected ErrCapacityBelowEnrollment, got %v", err)
+	}
+	if err := DeleteCourse(context.Background(), course.ID); err != ErrCourseHasEnrollments {
+		t.Errorf("Expected ErrCourseHasEnrollments, got %v", err)
+	}
+
+	roster, err := GetCourseRoster(context.Background(), course.ID, models.EnrollmentActive)
+	if err != nil || len(roster) != 2 || roster[0].Student == nil || roster[0].Student.Name != "Alice" {
+		t.Errorf("Expected roster of Alice and Bob, got %+v, %v", roster, err)
+	}
+}
+
+func TestCourseRosterSkipsDeletedStudents(t *testing.T) {
+	course, created := setupCourseTest(t, 3)
+	for _, student := range created {
+		enrollment, err := EnrollStudent(context.Background(), student.ID, course.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := UpdateEnrollmentStatus(context.Background(), student.ID, enrollment.ID, models.EnrollmentCompleted); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	// Completed enrollments survive the delete, unlike active ones.
+	if err := DeleteStudent(context.Background(), created[1].ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	roster, err := GetCourseRoster(context.Background(), course.ID, models.EnrollmentCompleted)
+	if err != nil || len(roster) != 2 {
+		t.Fatalf("Expected roster of Alice and Carol, got %+v, %v", roster, err)
+	}
+	for _, enrollment := range roster {
+		if enrollment.StudentID == created[1].ID {
+			t.Errorf("Expected deleted student to be left off the roster, got %+v", enrollment)
+		}
+	}
+}
+
+func TestResetCoursesClearsGradesAndAttendance(t *testing.T) {
+	course, created := setupCourseTest(t, 3)
+	if _, err := EnrollStudent(context.Background(), created[0].ID, course.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := RecordGrade(context.Background(), models.Grade{StudentID: created[0].ID, CourseID: course.ID, Assessment: "Midterm", Score: 40, MaxScore: 50, Weight: 1}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := RecordAttendance(context.Background(), course.ID, "2024-03-04", []AttendanceEntry{{StudentID: created[0].ID, Status: models.AttendancePresent}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ResetCourses()
+
+	if grades := GetStudentGrades(context.Background(), created[0].ID); len(grades) != 0 {
+		t.Errorf("Expected no grades after reset, got %+v", grades)
+	}
+	report := GetAttendanceReport(context.Background(), AttendanceRange{})
+	if len(report.Students) != 0 {
+		t.Errorf("Expected no attendance after reset, got %+v", report)
+	}
+}
+
+func TestUpdateEnrollmentStatus(t *testing.T) {
+	course, created := setupCourseTest(t, 1)
+
+	enrollment, err := EnrollStudent(context.Background(), created[0].ID, course.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dropped, err := UpdateEnrollmentStatus(context.Background(), created[0].ID, enrollment.ID, models.EnrollmentDropped)
+	if err != nil || dropped.Status != models.EnrollmentDropped {
+		t.Fatalf("Expected dropped enrollment, got %+v, %v", dropped, err)
+	}
+
+	// The freed seat goes to Bob, so Alice cannot be reactivated.
+	other, err := EnrollStudent(context.Background(), created[1].ID, course.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := UpdateEnrollmentStatus(context.Background(), created[0].ID, enrollment.ID, models.EnrollmentActive); err != ErrCourseFull {
+		t.Errorf("Expected ErrCourseFull, got %v", err)
+	}
+
+	if _, err := UpdateEnrollmentStatus(context.Background(), created[1].ID, other.ID, models.EnrollmentCompleted); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := UpdateEnrollmentStatus(context.Background(), created[1].ID, other.ID, models.EnrollmentActive); !errors.Is(err, ErrInvalidEnrollmentTransition) {
+		t.Errorf("Expected ErrInvalidEnrollmentTransition, got %v", err)
+	}
+	if _, err := UpdateEnrollmentStatus(context.Background(), created[1].ID, enrollment.ID, models.EnrollmentActive); err != ErrEnrollmentNotFound {
+		t.Errorf("Expected ErrEnrollmentNotFound for another student's enrollment, got %v", err)
+	}
+}
+
+func TestEnrollmentsOnStudentDeletion(t *testing.T) {
+	ctx := context.Background()
+	course, created := setupCourseTest(t, 1)
+
+	enrollment, _ := EnrollStudent(context.Background(), created[0].ID, course.ID)
+	if err := DeleteStudent(ctx, created[0].ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	history := GetStudentEnrollments(context.Background(), created[0].ID)
+	if len(history) != 1 || history[0].Status != models.EnrollmentDropped {
+		t.Errorf("Expected deleted student's enrollment to be dropped, got %+v", history)
+	}
+	if _, err := EnrollStudent(context.Background(), created[1].ID, course.ID); err != nil {
+		t.Errorf("Expected the freed seat to be available, got %v", err)
+	}
+	if _, err := UpdateEnrollmentStatus(context.Background(), created[0].ID, enrollment.ID, models.EnrollmentActive); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound for deleted student, got %v", err)
+	}
+
+	PurgeDeletedStudents(ctx, time.Now().Add(time.Minute))
+	if history := GetStudentEnrollments(context.Background(), created[0].ID); len(history) != 0 {
+		t.Errorf("Expected purged student's enrollments to be removed, got %+v", history)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"student-api/internal/models"
+	"sync"
+)
+
+var (
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists   = errors.New("custom field already exists")
+	ErrCustomFieldInUse    = errors.New("custom field is in use")
+)
+
+// customFieldsMutex guards the custom field definitions of every tenant.
+var customFieldsMutex = sync.RWMutex{}
+
+// customFieldStore holds one tenant's custom field definitions.
+type customFieldStore struct {
+	customFields map[string]models.CustomFieldDefinition
+}
+
+var customFieldStores = newTenantStores(&customFieldsMutex, func() *customFieldStore {
+	return &customFieldStore{customFields: make(map[string]models.CustomFieldDefinition)}
+}, nil)
+
+func CreateCustomField(ctx context.Context, definition models.CustomFieldDefinition) (models.CustomFieldDefinition, error) {
+	if err := definition.Validate(); err != nil {
+		return models.CustomFieldDefinition{}, err
+	}
+
+	customFieldsMutex.Lock()
+	defer customFieldsMutex.Unlock()
+	data, err := customFieldStores.get(ctx)
+	if err != nil {
+		return models.CustomFieldDefinition{}, err
+	}
+
+	if _, exists := data.customFields[definition.Key]; exists {
+		return models.CustomFieldDefinition{}, ErrCustomFieldExists
+	}
+	data.customFields[definition.Key] = definition
+	return definition, nil
+}
+
+func ListCustomFields(ctx context.Context) []models.CustomFieldDefinition {
+	customFieldsMutex.RLock()
+	defer customFieldsMutex.RUnlock()
+	data, err := customFieldStores.get(ctx)
+	if err != nil {
+		return []models.CustomFieldDefinition{}
+	}
+
+	result := make([]models.CustomFieldDefinition, 0, len(data.customFields))
+	for _, definition := range data.customFields {
+		result = append(result, definition)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// DeleteCustomField removes a definition. A field that any student, deleted
+// or not, still has a value for cannot be removed.
+func DeleteCustomField(ctx context.Context, key string) error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return err
+	}
+	customFieldsMutex.Lock()
+	defer customFieldsMutex.Unlock()
+	data, err := customFieldStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := data.customFields[key]; !exists {
+		return ErrCustomFieldNotFound
+	}
+	for _, student := range students.students {
+		if _, ok := student.CustomFields[key]; ok {
+			return ErrCustomFieldInUse
+		}
+	}
+	delete(data.customFields, key)
+	return nil
+}
+
+// ValidateCustomFields checks student custom field values against the
+// definitions: every key must be defined, every value must match its type
+// and every required field must be present.
+func ValidateCustomFields(ctx context.Context, values map[string]interface{}) error {
+	customFieldsMutex.RLock()
+	defer customFieldsMutex.RUnlock()
+	data, err := customFieldStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition, exists := data.customFields[key]
+		if !exists {
+			return fmt.Errorf("unknown custom field %q", key)
+		}
+		if values[key] == nil {
+			continue
+		}
+		if err := definition.ValidateValue(values[key]); err != nil {
+			return err
+		}
+	}
+	for _, definition := range data.customFields {
+		if definition.Required && values[definition.Key] == nil {
+			return fmt.Errorf("custom field %q is required", definition.Key)
+		}
+	}
+	return nil
+}
+
+func ResetCustomFields() {
+	customFieldsMutex.Lock()
+	defer customFieldsMutex.Unlock()
+	customFieldStores.reset()
+}
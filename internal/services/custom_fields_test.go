@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCustomFieldDefinitions(t *testing.T) {
+	ResetStudents()
+	ResetCustomFields()
+
+	if _, err := CreateCustomField(context.Background(), models.CustomFieldDefinition{Key: "locker", Label: "Locker", Type: models.CustomFieldString}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := CreateCustomField(context.Background(), models.CustomFieldDefinition{Key: "house", Label: "House", Type: models.CustomFieldEnum, Options: []string{"red", "blue"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := CreateCustomField(context.Background(), models.CustomFieldDefinition{Key: "locker", Label: "Locker", Type: models.CustomFieldInteger}); err != ErrCustomFieldExists {
+		t.Errorf("Expected ErrCustomFieldExists, got %v", err)
+	}
+
+	definitions := ListCustomFields(context.Background())
+	if len(definitions) != 2 || definitions[0].Key != "house" || definitions[1].Key != "locker" {
+		t.Fatalf("Expected [house locker], got %+v", definitions)
+	}
+
+	CreateStudent(context.Background(), models.Student{
+		Name: "Alice", Age: 20, Email: "alice@example.com",
+		CustomFields: map[string]interface{}{"house": "red"},
+	})
+
+	if err := DeleteCustomField(context.Background(), "house"); err != ErrCustomFieldInUse {
+		t.Errorf("Expected ErrCustomFieldInUse, got %v", err)
+	}
+	if err := DeleteCustomField(context.Background(), "locker"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := DeleteCustomField(context.Background(), "locker"); err != ErrCustomFieldNotFound {
+		t.Errorf("Expected ErrCustomFieldNotFound, got %v", err)
+	}
+}
+
+func TestValidateCustomFields(t *testing.T) {
+	ResetCustomFields()
+	CreateCustomField(context.Background(), models.CustomFieldDefinition{Key: "student_number", Label: "Student number", Type: models.CustomFieldInteger, Required: true})
+	CreateCustomField(context.Background(), models.CustomFieldDefinition{Key: "house", Label: "House", Type: models.CustomFieldEnum, Options: []string{"red", "blue"}})
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		valid  bool
+	}{
+		{"Valid", map[string]interface{}{"student_number": 1042.0, "house": "blue"}, true},
+		{"Optional field omitted", map[string]interface{}{"student_number": 1042.0}, true},
+		{"Required field missing", map[string]interface{}{"house": "blue"}, false},
+		{"Required field null", map[string]interface{}{"student_number": nil}, false},
+		{"Wrong type", map[string]interface{}{"student_number": "1042"}, false},
+		{"Unknown field", map[string]interface{}{"student_number": 1042.0, "shoe_size": 9.0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomFields(context.Background(), tt.values)
+			if tt.valid && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error, got none")
+			}
+		})
+	}
+}
+
+func TestStudentProfileNormalization(t *testing.T) {
+	ResetStudents()
+
+	birth := models.NewDate(time.Now().UTC().Year()-20, time.January, 1)
+	student, _ := CreateStudent(context.Background(), models.Student{
+		Name: "Alice", Age: 99, Email: "alice@example.com", DateOfBirth: &birth,
+		CustomFields: map[string]interface{}{"house": nil},
+	})
+	if student.Status != models.StudentEnrolled {
+		t.Errorf("Expected default status enrolled, got %s", student.Status)
+	}
+	if student.Age != 0 || student.CurrentAge() != 20 {
+		t.Errorf("Expected age derived from the date of birth, got stored %d and current %d", student.Age, student.CurrentAge())
+	}
+	if student.CustomFields != nil {
+		t.Errorf("Expected null custom fields to be removed, got %v", student.CustomFields)
+	}
+
+	TransitionStudent(context.Background(), student.ID, models.TransitionRequest{Status: models.StudentSuspended, Reason: "Unpaid fees"})
+	student.Status = ""
+	updated, err := UpdateStudent(context.Background(), student.ID, student)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Status != models.StudentSuspended {
+		t.Errorf("Expected status to be kept when omitted, got %s", updated.Status)
+	}
+
+	if matches := ListStudents(context.Background(), StudentFilter{MinAge: 20, MaxAge: 20}); len(matches) != 1 {
+		t.Errorf("Expected the age filter to use the derived age, got %d matches", len(matches))
+	}
+}
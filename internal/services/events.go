@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+const subscriberBufferSize = 16
+
+// eventMutex guards the event buffers and subscribers of every tenant as well
+// as the subscribers to all tenants' events.
+var (
+	eventBufferSize = 100
+	// allSubscribers receive the events of every tenant. They are used by
+	// internal consumers such as the webhook dispatcher and the summary cache.
+	allSubscribers      = make(map[int]chan models.StudentEvent)
+	nextAllSubscriberID = 1
+	eventMutex          = sync.Mutex{}
+)
+
+// eventStore holds one tenant's recent events and subscribers.
+type eventStore struct {
+	eventBuffer      []models.StudentEvent
+	nextEventID      int64
+	subscribers      map[int]chan models.StudentEvent
+	nextSubscriberID int
+}
+
+var eventStores = newTenantStores(&eventMutex, func() *eventStore {
+	return &eventStore{nextEventID: 1, subscribers: make(map[int]chan models.StudentEvent), nextSubscriberID: 1}
+}, func(data *eventStore) {
+	// Closing the channels ends the streams of a deleted tenant.
+	for id, ch := range data.subscribers {
+		close(ch)
+		delete(data.subscribers, id)
+	}
+})
+
+// SetEventBufferSize bounds how many past events are kept per tenant for
+// Last-Event-ID resumption.
+func SetEventBufferSize(size int) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+
+	eventBufferSize = size
+	eventStores.each(func(_ string, data *eventStore) {
+		if len(data.eventBuffer) > size {
+			data.eventBuffer = append([]models.StudentEvent(nil), data.eventBuffer[len(data.eventBuffer)-size:]...)
+		}
+	})
+}
+
+func publishEvent(ctx context.Context, eventType models.StudentEventType, student models.Student) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+	data, err := eventStores.get(ctx)
+	if err != nil {
+		return
+	}
+
+	event := models.StudentEvent{
+		ID:        data.nextEventID,
+		Tenant:    reqctx.Tenant(ctx),
+		Type:      eventType,
+		StudentID: student.ID,
+		Student:   student,
+		Timestamp: time.Now().UTC(),
+	}
+	data.nextEventID++
+
+	data.eventBuffer = append(data.eventBuffer, event)
+	if len(data.eventBuffer) > eventBufferSize {
+		data.eventBuffer = append([]models.StudentEvent(nil), data.eventBuffer[len(data.eventBuffer)-eventBufferSize:]...)
+	}
+
+	deliverEvent(data.subscribers, event)
+	deliverEvent(allSubscribers, event)
+}
+
+// deliverEvent sends event to every subscriber. Callers hold eventMutex.
+func deliverEvent(subscribers map[int]chan models.StudentEvent, event models.StudentEvent) {
+	for id, ch := range subscribers {
+		select {
+		case ch <- event:
+		default:
+			// The subscriber is too slow to keep up; drop it so it reconnects
+			// and resumes from the buffer instead of blocking every mutation.
+			close(ch)
+			delete(subscribers, id)
+		}
+	}
+}
+
+// SubscribeEvents registers a subscriber for the student events of the
+// tenant in ctx. Buffered events newer than lastEventID are returned as
+// backlog when lastEventID is positive. The returned channel is closed if the
+// subscriber falls behind or its tenant is deleted; unsubscribe must be
+// called once the subscriber is done.
+func SubscribeEvents(ctx context.Context, lastEventID int64) ([]models.StudentEvent, <-chan models.StudentEvent, func()) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+	data, err := eventStores.get(ctx)
+	if err != nil {
+		closed := make(chan models.StudentEvent)
+		close(closed)
+		return []models.StudentEvent{}, closed, func() {}
+	}
+
+	backlog := []models.StudentEvent{}
+	if lastEventID > 0 {
+		backlog = eventsAfter(data, lastEventID)
+	}
+	events, unsubscribe := addSubscriber(data.subscribers, &data.nextSubscriberID)
+	return backlog, events, unsubscribe
+}
+
+// subscribeAllEvents registers a subscriber for the events of every tenant.
+// When resuming, lastEventIDs holds the last event seen per tenant and the
+// buffered events newer than it are returned as backlog; a tenant without an
+// entry has had none of its events seen, so all of its buffered events are.
+// Otherwise lastEventIDs is set to each tenant's latest event, so that a
+// later resumption starts from this subscription.
+func subscribeAllEvents(lastEventIDs map[string]int64, resume bool) ([]models.StudentEvent, <-chan models.StudentEvent, func()) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+
+	backlog := []models.StudentEvent{}
+	eventStores.each(func(tenantID string, data *eventStore) {
+		if resume {
+			backlog = append(backlog, eventsAfter(data, lastEventIDs[tenantID])...)
+		} else {
+			lastEventIDs[tenantID] = data.nextEventID - 1
+		}
+	})
+	events, unsubscribe := addSubscriber(allSubscribers, &nextAllSubscriberID)
+	return backlog, events, unsubscribe
+}
+
+// eventsAfter returns the buffered events newer than lastEventID. Callers
+// hold eventMutex.
+func eventsAfter(data *eventStore, lastEventID int64) []models.StudentEvent {
+	result := []models.StudentEvent{}
+	for _, event := range data.eventBuffer {
+		if event.ID > lastEventID {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// addSubscriber adds a channel to subscribers. Callers hold eventMutex.
+func addSubscriber(subscribers map[int]chan models.StudentEvent, nextID *int) (<-chan models.StudentEvent, func()) {
+	id := *nextID
+	*nextID++
+	ch := make(chan models.StudentEvent, subscriberBufferSize)
+	subscribers[id] = ch
+
+	unsubscribe := func() {
+		eventMutex.Lock()
+		defer eventMutex.Unlock()
+		if existing, ok := subscribers[id]; ok {
+			close(existing)
+			delete(subscribers, id)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// consumeEvents calls handle for the student events of every tenant until ctx
+// is cancelled, resubscribing from the last seen events if the subscriber
+// falls behind.
+func consumeEvents(ctx context.Context, handle func(event models.StudentEvent)) {
+	lastEventIDs := make(map[string]int64)
+	for resume := false; ; resume = true {
+		backlog, events, unsubscribe := subscribeAllEvents(lastEventIDs, resume)
+		for _, event := range backlog {
+			handle(event)
+			lastEventIDs[event.Tenant] = event.ID
+		}
+
+	receive:
+		for {
+			select {
+			case <-ctx.Done():
+				unsubscribe()
+				return
+			case event, ok := <-events:
+				if !ok {
+					break receive
+				}
+				handle(event)
+				lastEventIDs[event.Tenant] = event.ID
+			}
+		}
+		unsubscribe()
+	}
+}
+
+// SubscriberCount returns the number of event subscribers across all tenants.
+func SubscriberCount() int {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+
+	count := len(allSubscribers)
+	eventStores.each(func(_ string, data *eventStore) {
+		count += len(data.subscribers)
+	})
+	return count
+}
+
+func ResetEvents() {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+	eventStores.each(func(_ string, data *eventStore) {
+		data.eventBuffer = nil
+		data.nextEventID = 1
+	})
+}
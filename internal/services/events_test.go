@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribeEvents(t *testing.T) {
+	ResetStudents()
+	ResetEvents()
+
+	backlog, events, unsubscribe := SubscribeEvents(context.Background(), 0)
+	defer unsubscribe()
+
+	if len(backlog) != 0 {
+		t.Errorf("Expected no backlog for a new subscriber, got %d", len(backlog))
+	}
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	UpdateStudent(context.Background(), student.ID, models.Student{Name: "John Doe", Age: 21, Email: "john@example.com"})
+	DeleteStudent(context.Background(), student.ID)
+
+	expected := []models.StudentEventType{models.StudentCreated, models.StudentUpdated, models.StudentDeleted}
+	for i, eventType := range expected {
+		select {
+		case event := <-events:
+			if event.Type != eventType {
+				t.Errorf("Expected event %s, got %s", eventType, event.Type)
+			}
+			if event.ID != int64(i+1) {
+				t.Errorf("Expected event ID %d, got %d", i+1, event.ID)
+			}
+			if event.StudentID != student.ID {
+				t.Errorf("Expected student ID %d, got %d", student.ID, event.StudentID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s", eventType)
+		}
+	}
+}
+
+func TestSubscribeEventsResume(t *testing.T) {
+	ResetStudents()
+	ResetEvents()
+	SetEventBufferSize(2)
+	defer SetEventBufferSize(100)
+
+	for i := 0; i < 3; i++ {
+		CreateStudent(context.Background(), models.Student{Name: "Student", Age: 20, Email: "student@example.com"})
+	}
+
+	backlog, _, unsubscribe := SubscribeEvents(context.Background(), 1)
+	defer unsubscribe()
+
+	if len(backlog) != 2 {
+		t.Fatalf("Expected 2 buffered events, got %d", len(backlog))
+	}
+	if backlog[0].ID != 2 || backlog[1].ID != 3 {
+		t.Errorf("Expected events 2 and 3, got %d and %d", backlog[0].ID, backlog[1].ID)
+	}
+
+	backlog, _, unsubscribeLatest := SubscribeEvents(context.Background(), 3)
+	defer unsubscribeLatest()
+	if len(backlog) != 0 {
+		t.Errorf("Expected no backlog when up to date, got %d", len(backlog))
+	}
+}
+
+func TestUnsubscribeEvents(t *testing.T) {
+	ResetEvents()
+
+	_, events, unsubscribe := SubscribeEvents(context.Background(), 0)
+	before := SubscriberCount()
+	unsubscribe()
+	unsubscribe()
+
+	if SubscriberCount() != before-1 {
+		t.Errorf("Expected subscriber to be removed, got %d subscribers", SubscriberCount())
+	}
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	ResetStudents()
+	ResetEvents()
+
+	_, events, unsubscribe := SubscribeEvents(context.Background(), 0)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		CreateStudent(context.Background(), models.Student{Name: "Student", Age: 20, Email: "student@example.com"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBufferSize {
+		t.Errorf("Expected %d events before the slow subscriber was dropped, got %d", subscriberBufferSize, received)
+	}
+}
+
+func TestConsumeEventsRejoin(t *testing.T) {
+	north, _ := setupTenantTest(t)
+	ResetStudents()
+	ResetEvents()
+
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []models.StudentEvent
+	startEventConsumer(t, func(ctx context.Context) {
+		go consumeEvents(ctx, func(event models.StudentEvent) {
+			if event.Tenant == reqctx.DefaultTenant && event.ID == 1 {
+				close(blocked)
+				<-release
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, event)
+		})
+	})
+
+	// While the consumer is busy, the default tenant fills its channel and
+	// the first north event gets it dropped before it has seen any north
+	// event.
+	CreateStudent(context.Background(), models.Student{Name: "First", Age: 20, Email: "first@example.com"})
+	<-blocked
+	for i := 0; i < subscriberBufferSize; i++ {
+		CreateStudent(context.Background(), models.Student{Name: "Student", Age: 20, Email: "student@example.com"})
+	}
+	CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"})
+	close(release)
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, event := range handled {
+			if event.Tenant == "north" {
+				return true
+			}
+		}
+		return false
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != subscriberBufferSize+2 {
+		t.Errorf("Expected every event to be handled once, got %d", len(handled))
+	}
+}
+
+// startEventConsumer runs start with a context cancelled at the end of the
+// test and waits for the consumer to subscribe, and later to unsubscribe, so
+// consumers from different tests never overlap.
+func startEventConsumer(t *testing.T, start func(ctx context.Context)) {
+	t.Helper()
+	subscribers := SubscriberCount()
+	ctx, cancel := context.WithCancel(context.Background())
+	start(ctx)
+	waitFor(t, func() bool { return SubscriberCount() > subscribers })
+
+	t.Cleanup(func() {
+		cancel()
+		waitFor(t, func() bool { return SubscriberCount() == subscribers })
+	})
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"student-api/internal/models"
+	"sync"
+	"time"
+)
+
+var (
+	ErrGradeNotFound = errors.New("grade not found")
+	ErrNotEnrolled   = errors.New("student is not enrolled in course")
+)
+
+// GradeBand maps course averages of at least MinPercent to a letter and its
+// grade points.
+type GradeBand struct {
+	Letter     string
+	MinPercent float64
+	Points     float64
+}
+
+// GradeScale lists grade bands from the highest minimum down to a band
+// starting at 0, so every average falls in exactly one band.
+type GradeScale []GradeBand
+
+func DefaultGradeScale() GradeScale {
+	return GradeScale{
+		{Letter: "A", MinPercent: 90, Points: 4},
+		{Letter: "B", MinPercent: 80, Points: 3},
+		{Letter: "C", MinPercent: 70, Points: 2},
+		{Letter: "D", MinPercent: 60, Points: 1},
+		{Letter: "F", MinPercent: 0, Points: 0},
+	}
+}
+
+// ParseGradeScale parses bands written as LETTER:MIN_PERCENT:POINTS, such as
+// "A:90:4". No bands yields the default scale.
+func ParseGradeScale(bands []string) (GradeScale, error) {
+	if len(bands) == 0 {
+		return DefaultGradeScale(), nil
+	}
+
+	scale := make(GradeScale, 0, len(bands))
+	for _, band := range bands {
+		parts := strings.Split(band, ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("grade band %q must be LETTER:MIN_PERCENT:POINTS", band)
+		}
+		minPercent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || minPercent < 0 || minPercent > 100 {
+			return nil, fmt.Errorf("grade band %q must have a minimum between 0 and 100", band)
+		}
+		points, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || points < 0 {
+			return nil, fmt.Errorf("grade band %q must have non-negative points", band)
+		}
+		if n := len(scale); n > 0 && minPercent >= scale[n-1].MinPercent {
+			return nil, fmt.Errorf("grade band %q must have a lower minimum than the band before it", band)
+		}
+		scale = append(scale, GradeBand{Letter: strings.TrimSpace(parts[0]), MinPercent: minPercent, Points: points})
+	}
+	if scale[len(scale)-1].MinPercent != 0 {
+		return nil, errors.New("the last grade band must start at 0")
+	}
+	return scale, nil
+}
+
+// Band returns the band that percent falls in.
+func (s GradeScale) Band(percent float64) GradeBand {
+	for _, band := range s {
+		if percent >= band.MinPercent {
+			return band
+		}
+	}
+	return s[len(s)-1]
+}
+
+func (s GradeScale) MaxPoints() float64 {
+	max := 0.0
+	for _, band := range s {
+		max = math.Max(max, band.Points)
+	}
+	return max
+}
+
+var (
+	gradeScaleMutex sync.RWMutex
+	gradeScale      = DefaultGradeScale()
+)
+
+func ConfigureGradeScale(scale GradeScale) {
+	gradeScaleMutex.Lock()
+	defer gradeScaleMutex.Unlock()
+	gradeScale = scale
+}
+
+func currentGradeScale() GradeScale {
+	gradeScaleMutex.RLock()
+	defer gradeScaleMutex.RUnlock()
+	return gradeScale
+}
+
+// RecordGrade records an assessment for a student in a course they are
+// enrolled in. Grades can be recorded for active and completed enrollments
+// but not dropped ones.
+func RecordGrade(ctx context.Context, grade models.Grade) (models.Grade, error) {
+	if err := grade.Validate(); err != nil {
+		return models.Grade{}, err
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Grade{}, err
+	}
+
+	if student, exists := students.students[grade.StudentID]; !exists || student.IsDeleted() {
+		return models.Grade{}, ErrStudentNotFound
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Grade{}, err
+	}
+
+	if _, exists := data.courses[grade.CourseID]; !exists {
+		return models.Grade{}, ErrCourseNotFound
+	}
+	if !gradableEnrollment(data, grade.StudentID, grade.CourseID) {
+		return models.Grade{}, ErrNotEnrolled
+	}
+
+	now := time.Now().UTC()
+	grade.ID = data.nextGradeID
+	grade.RecordedAt = now
+	grade.UpdatedAt = now
+	data.nextGradeID++
+	data.grades[grade.ID] = grade
+	return grade, nil
+}
+
+// UpdateGrade replaces the assessment, score, maximum and weight of one of a
+// student's grades. The course of a grade cannot change.
+func UpdateGrade(ctx context.Context, studentID, gradeID int, update models.Grade) (models.Grade, error) {
+	if err := update.Validate(); err != nil {
+		return models.Grade{}, err
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Grade{}, err
+	}
+
+	if student, exists := students.students[studentID]; !exists || student.IsDeleted() {
+		return models.Grade{}, ErrStudentNotFound
+	}
+
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Grade{}, err
+	}
+
+	grade, exists := data.grades[gradeID]
+	if !exists || grade.StudentID != studentID {
+		return models.Grade{}, ErrGradeNotFound
+	}
+
+	grade.Assessment = update.Assessment
+	grade.Score = update.Score
+	grade.MaxScore = update.MaxScore
+	grade.Weight = update.Weight
+	grade.UpdatedAt = time.Now().UTC()
+	data.grades[gradeID] = grade
+	return grade, nil
+}
+
+func GetStudentGrades(ctx context.Context, studentID int) []models.Grade {
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return []models.Grade{}
+	}
+
+	result := make([]models.Grade, 0)
+	for _, grade := range data.grades {
+		if grade.StudentID == studentID {
+			result = append(result, grade)
+		}
+	}
+	sortGrades(result)
+	return result
+}
+
+// GetTranscript returns the academic record of an existing student.
+func GetTranscript(ctx context.Context, studentID int) (models.Transcript, error) {
+	if _, err := GetStudentByID(ctx, studentID); err != nil {
+		return models.Transcript{}, err
+	}
+	return studentTranscript(ctx, studentID), nil
+}
+
+// studentTranscript builds the transcript of studentID from every course the
+// student has an enrollment in, without checking the student exists.
+func studentTranscript(ctx context.Context, studentID int) models.Transcript {
+	scale := currentGradeScale()
+
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return buildTranscript(nil, scale, studentID, nil, nil)
+	}
+
+	var studentEnrollments []models.Enrollment
+	for _, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID {
+			studentEnrollments = append(studentEnrollments, enrollment)
+		}
+	}
+	var studentGrades []models.Grade
+	for _, grade := range data.grades {
+		if grade.StudentID == studentID {
+			studentGrades = append(studentGrades, grade)
+		}
+	}
+	return buildTranscript(data.courses, scale, studentID, studentEnrollments, studentGrades)
+}
+
+// buildTranscript builds the transcript of studentID from the student's
+// enrollments and grades. Callers hold courseMutex.
+func buildTranscript(courses map[int]models.Course, scale GradeScale, studentID int, studentEnrollments []models.Enrollment, studentGrades []models.Grade) models.Transcript {
+	transcript := models.Transcript{
+		StudentID: studentID,
+		Courses:   make([]models.CourseResult, 0),
+		MaxGPA:    scale.MaxPoints(),
+	}
+	sortEnrollments(studentEnrollments)
+
+	totalPoints, graded := 0.0, 0
+	for _, enrollment := range studentEnrollments {
+		course := courses[enrollment.CourseID]
+		result := models.CourseResult{
+			CourseID: course.ID,
+			Code:     course.Code,
+			Title:    course.Title,
+			Status:   enrollment.Status,
+			Grades:   make([]models.Grade, 0),
+		}
+
+		weighted, totalWeight := 0.0, 0.0
+		for _, grade := range studentGrades {
+			if grade.CourseID == course.ID {
+				result.Grades = append(result.Grades, grade)
+				weighted += grade.Percent() * grade.Weight
+				totalWeight += grade.Weight
+			}
+		}
+		sortGrades(result.Grades)
+
+		if totalWeight > 0 {
+			average := roundTo(weighted/totalWeight, 2)
+			band := scale.Band(average)
+			result.Average = &average
+			result.Letter = band.Letter
+			result.GradePoints = &band.Points
+			if enrollment.Status != models.EnrollmentDropped {
+				totalPoints += band.Points
+				graded++
+			}
+		}
+		transcript.Courses = append(transcript.Courses, result)
+	}
+
+	if graded > 0 {
+		gpa := roundTo(totalPoints/float64(graded), 2)
+		transcript.GPA = &gpa
+	}
+	return transcript
+}
+
+// gradableEnrollment reports whether the student has an active or completed
+// enrollment in the course. Callers hold courseMutex.
+func gradableEnrollment(data *courseStore, studentID, courseID int) bool {
+	for _, enrollment := range data.enrollments {
+		if enrollment.StudentID == studentID && enrollment.CourseID == courseID {
+			return enrollment.Status != models.EnrollmentDropped
+		}
+	}
+	return false
+}
+
+// deleteGrades removes the grades that match. Callers hold courseMutex.
+func deleteGrades(data *courseStore, match func(grade models.Grade) bool) {
+	for id, grade := range data.grades {
+		if match(grade) {
+			delete(data.grades, id)
+		}
+	}
+}
+
+func sortGrades(list []models.Grade) {
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
+
+func ResetGrades() {
+	courseMutex.Lock()
+	defer courseMutex.Unlock()
+	courseStores.each(func(_ string, data *courseStore) {
+		data.grades = make(map[int]models.Grade)
+		data.nextGradeID = 1
+	})
+}
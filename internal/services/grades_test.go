@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestParseGradeScale(t *testing.T) {
+	scale, err := ParseGradeScale(nil)
+	if err != nil || len(scale) != 5 || scale.MaxPoints() != 4 {
+		t.Fatalf("Expected default 4-point scale, got %v, %v", scale, err)
+	}
+
+	scale, err = ParseGradeScale([]string{"H1:70:1", "H2:60:0.8", "P:40:0.5", "F:0:0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if band := scale.Band(65); band.Letter != "H2" || band.Points != 0.8 {
+		t.Errorf("Expected H2 for 65%%, got %+v", band)
+	}
+	if band := scale.Band(10); band.Letter != "F" {
+		t.Errorf("Expected F for 10%%, got %+v", band)
+	}
+
+	invalid := [][]string{
+		{"A:90"},
+		{":90:4", "F:0:0"},
+		{"A:120:4", "F:0:0"},
+		{"A:90:-1", "F:0:0"},
+		{"B:80:3", "A:90:4", "F:0:0"},
+		{"A:90:4", "B:80:3"},
+	}
+	for _, bands := range invalid {
+		if _, err := ParseGradeScale(bands); err == nil {
+			t.Errorf("Expected error for %v", bands)
+		}
+	}
+}
+
+func setupGradeTest(t *testing.T) (models.Student, models.Course, models.Course) {
+	ResetStudents()
+	ResetCourses()
+	ResetGrades()
+	ConfigureGradeScale(DefaultGradeScale())
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	programming, _ := CreateCourse(context.Background(), models.Course{Code: "CS101", Title: "Intro to Programming", Capacity: 10})
+	calculus, _ := CreateCourse(context.Background(), models.Course{Code: "MATH101", Title: "Calculus I", Capacity: 10})
+	for _, course := range []models.Course{programming, calculus} {
+		if _, err := EnrollStudent(context.Background(), student.ID, course.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	return student, programming, calculus
+}
+
+func TestRecordAndUpdateGrade(t *testing.T) {
+	student, programming, _ := setupGradeTest(t)
+
+	grade, err := RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: programming.ID, Assessment: "Midterm", Score: 40, MaxScore: 50, Weight: 1})
+	if err != nil || grade.ID != 1 || grade.RecordedAt.IsZero() {
+		t.Fatalf("Expected recorded grade, got %+v, %v", grade, err)
+	}
+
+	updated, err := UpdateGrade(context.Background(), student.ID, grade.ID, models.Grade{CourseID: 99, Assessment: "Midterm", Score: 45, MaxScore: 50, Weight: 2})
+	if err != nil || updated.Score != 45 || updated.Weight != 2 || updated.CourseID != programming.ID {
+		t.Fatalf("Expected updated grade in the same course, got %+v, %v", updated, err)
+	}
+
+	if _, err := UpdateGrade(context.Background(), student.ID, 99, updated); err != ErrGradeNotFound {
+		t.Errorf("Expected ErrGradeNotFound, got %v", err)
+	}
+	if _, err := RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: 99, Assessment: "Quiz", Score: 1, MaxScore: 1, Weight: 1}); err != ErrCourseNotFound {
+		t.Errorf("Expected ErrCourseNotFound, got %v", err)
+	}
+
+	other, _ := CreateCourse(context.Background(), models.Course{Code: "ART101", Title: "Drawing", Capacity: 10})
+	if _, err := RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: other.ID, Assessment: "Quiz", Score: 1, MaxScore: 1, Weight: 1}); err != ErrNotEnrolled {
+		t.Errorf("Expected ErrNotEnrolled, got %v", err)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	student, programming, calculus := setupGradeTest(t)
+
+	record := func(courseID int, assessment string, score, maxScore, weight float64) {
+		if _, err := RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: courseID, Assessment: assessment, Score: score, MaxScore: maxScore, Weight: weight}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	transcript, err := GetTranscript(context.Background(), student.ID)
+	if err != nil || transcript.GPA != nil || len(transcript.Courses) != 2 || transcript.Courses[0].Average != nil {
+		t.Fatalf("Expected ungraded transcript, got %+v, %v", transcript, err)
+	}
+
+	// Programming: (100% * 1 + 80% * 3) / 4 = 85% (B). Calculus: 95% (A).
+	record(programming.ID, "Homework", 10, 10, 1)
+	record(programming.ID, "Final", 80, 100, 3)
+	record(calculus.ID, "Final", 19, 20, 1)
+
+	transcript, _ = GetTranscript(context.Background(), student.ID)
+	first, second := transcript.Courses[0], transcript.Courses[1]
+	if *first.Average != 85 || first.Letter != "B" || *first.GradePoints != 3 || len(first.Grades) != 2 {
+		t.Errorf("Unexpected programming result: %+v", first)
+	}
+	if *second.Average != 95 || second.Letter != "A" {
+		t.Errorf("Unexpected calculus result: %+v", second)
+	}
+	if transcript.GPA == nil || *transcript.GPA != 3.5 || transcript.MaxGPA != 4 {
+		t.Errorf("Expected GPA 3.5 out of 4, got %v", transcript.GPA)
+	}
+
+	// A dropped course stays on the transcript but no longer counts.
+	enrollments := GetStudentEnrollments(context.Background(), student.ID)
+	UpdateEnrollmentStatus(context.Background(), student.ID, enrollments[1].ID, models.EnrollmentDropped)
+	transcript, _ = GetTranscript(context.Background(), student.ID)
+	if len(transcript.Courses) != 2 || *transcript.GPA != 3 {
+		t.Errorf("Expected GPA 3 without the dropped course, got %v", *transcript.GPA)
+	}
+
+	ConfigureGradeScale(GradeScale{{Letter: "P", MinPercent: 50, Points: 1}, {Letter: "F", MinPercent: 0, Points: 0}})
+	defer ConfigureGradeScale(DefaultGradeScale())
+	transcript, _ = GetTranscript(context.Background(), student.ID)
+	if transcript.Courses[0].Letter != "P" || *transcript.GPA != 1 || transcript.MaxGPA != 1 {
+		t.Errorf("Expected transcript on the configured scale, got %+v", transcript)
+	}
+
+	if _, err := GetTranscript(context.Background(), 99); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+}
+
+func TestGradesInSummaryPrompt(t *testing.T) {
+	setupPromptSafeguards(t, DefaultPromptSafeguards())
+	student, programming, _ := setupGradeTest(t)
+
+	keyBefore := SummaryCacheKey(context.Background(), student, "llama3", "v1", models.GenerationOptions{})
+	RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: programming.ID, Assessment: "Final", Score: 92, MaxScore: 100, Weight: 1})
+	if SummaryCacheKey(context.Background(), student, "llama3", "v1", models.GenerationOptions{}) == keyBefore {
+		t.Error("Expected a new grade to change the summary cache key")
+	}
+
+	prompt, _, err := buildSummaryPrompt(context.Background(), student, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(prompt, `"CS101" "Intro to Programming", active: A (92.0%)`) || !strings.Contains(prompt, "GPA: 4.00 out of 4.00") {
+		t.Errorf("Expected transcript in prompt, got %q", prompt)
+	}
+
+	setupPromptSafeguards(t, PromptSafeguards{RedactFields: []string{"grades"}})
+	prompt, _, _ = buildSummaryPrompt(context.Background(), student, "")
+	if strings.Contains(prompt, "CS101") || strings.Contains(prompt, "GPA") {
+		t.Errorf("Expected grades to be redacted, got %q", prompt)
+	}
+}
+
+func TestGradesRemovedWithCourseAndOnPurge(t *testing.T) {
+	ctx := context.Background()
+	student, programming, calculus := setupGradeTest(t)
+
+	RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: programming.ID, Assessment: "Final", Score: 1, MaxScore: 1, Weight: 1})
+	RecordGrade(context.Background(), models.Grade{StudentID: student.ID, CourseID: calculus.ID, Assessment: "Final", Score: 1, MaxScore: 1, Weight: 1})
+
+	enrollments := GetStudentEnrollments(context.Background(), student.ID)
+	UpdateEnrollmentStatus(context.Background(), student.ID, enrollments[0].ID, models.EnrollmentDropped)
+	if err := DeleteCourse(context.Background(), programming.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if remaining := GetStudentGrades(context.Background(), student.ID); len(remaining) != 1 || remaining[0].CourseID != calculus.ID {
+		t.Errorf("Expected only the calculus grade to remain, got %+v", remaining)
+	}
+
+	DeleteStudent(ctx, student.ID)
+	PurgeDeletedStudents(ctx, time.Now().Add(time.Minute))
+	if remaining := GetStudentGrades(context.Background(), student.ID); len(remaining) != 0 {
+		t.Errorf("Expected purged student's grades to be removed, got %+v", remaining)
+	}
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSummaryJobNotFound = errors.New("summary job not found")
+	ErrSummaryJobFinished = errors.New("summary job already finished")
+	ErrSummaryJobEmpty    = errors.New("summary job has no students")
+)
+
+type summaryJobTask struct {
+	tenantID  string
+	jobID     int
+	studentID int
+}
+
+// summaryJobMutex guards the job store and the summary jobs of every tenant.
+var (
+	summaryJobsPath string
+	summaryJobTasks = make(chan summaryJobTask)
+	summaryJobMutex = sync.Mutex{}
+)
+
+// summaryJobStore holds one tenant's summary jobs and the contexts of the
+// jobs still running.
+type summaryJobStore struct {
+	summaryJobs        map[int]*models.SummaryJob
+	summaryJobCancels  map[int]context.CancelFunc
+	summaryJobContexts map[int]context.Context
+	nextSummaryJobID   int
+}
+
+var summaryJobStores = newTenantStores(&summaryJobMutex, newSummaryJobStore, nil)
+
+func init() {
+	// Set here because saving the jobs of the remaining tenants refers back
+	// to summaryJobStores.
+	summaryJobStores.release = func(data *summaryJobStore) {
+		cancelSummaryJobs(data)
+		saveSummaryJobsLocked()
+	}
+}
+
+func newSummaryJobStore() *summaryJobStore {
+	return &summaryJobStore{
+		summaryJobs:        make(map[int]*models.SummaryJob),
+		summaryJobCancels:  make(map[int]context.CancelFunc),
+		summaryJobContexts: make(map[int]context.Context),
+		nextSummaryJobID:   1,
+	}
+}
+
+func cancelSummaryJobs(data *summaryJobStore) {
+	for _, cancel := range data.summaryJobCancels {
+		cancel()
+	}
+}
+
+// OpenSummaryJobStore loads jobs saved at path and saves every job state change
+// back to it. Unfinished jobs are resumed once the workers are started. Jobs
+// saved before tenants existed belong to the default tenant; jobs of tenants
+// that no longer exist are dropped.
+func OpenSummaryJobStore(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var loaded []*models.SummaryJob
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &loaded); err != nil {
+			return err
+		}
+	}
+
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+
+	summaryJobsPath = path
+	summaryJobStores.each(func(_ string, data *summaryJobStore) {
+		cancelSummaryJobs(data)
+	})
+	summaryJobStores.reset()
+	for _, job := range loaded {
+		if job.Tenant == "" {
+			job.Tenant = reqctx.DefaultTenant
+		}
+		data, exists := summaryJobStores.lookup(job.Tenant)
+		if !exists {
+			continue
+		}
+		data.summaryJobs[job.ID] = job
+		if job.ID >= data.nextSummaryJobID {
+			data.nextSummaryJobID = job.ID + 1
+		}
+	}
+	return nil
+}
+
+// StartSummaryJobWorkers processes queued jobs with a pool of workers calling
+// summarizer, and resumes jobs left unfinished by a previous run.
+func StartSummaryJobWorkers(ctx context.Context, summarizer SummaryProvider, workers int) {
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+
+	tasks := make(chan summaryJobTask)
+	summaryJobTasks = tasks
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case task := <-tasks:
+					processSummaryJobTask(summarizer, task)
+				}
+			}
+		}()
+	}
+
+	summaryJobStores.each(func(_ string, data *summaryJobStore) {
+		for _, job := range data.summaryJobs {
+			if !job.IsFinished() {
+				if _, queued := data.summaryJobCancels[job.ID]; !queued {
+					enqueueSummaryJobLocked(data, job)
+				}
+			}
+		}
+	})
+}
+
+func CreateSummaryJob(ctx context.Context, studentIDs []int) (models.SummaryJob, error) {
+	if len(studentIDs) == 0 {
+		return models.SummaryJob{}, ErrSummaryJobEmpty
+	}
+	if err := checkStudentsExist(ctx, studentIDs); err != nil {
+		return models.SummaryJob{}, err
+	}
+
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+	data, err := summaryJobStores.get(ctx)
+	if err != nil {
+		return models.SummaryJob{}, err
+	}
+
+	seen := make(map[int]bool)
+	job := &models.SummaryJob{
+		ID:        data.nextSummaryJobID,
+		Tenant:    reqctx.Tenant(ctx),
+		Status:    models.SummaryJobQueued,
+		Results:   []models.SummaryJobResult{},
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, studentID := range studentIDs {
+		if seen[studentID] {
+			continue
+		}
+		seen[studentID] = true
+		job.Results = append(job.Results, models.SummaryJobResult{StudentID: studentID, Status: models.SummaryResultPending})
+	}
+	job.Total = len(job.Results)
+
+	data.nextSummaryJobID++
+	data.summaryJobs[job.ID] = job
+	saveSummaryJobsLocked()
+	enqueueSummaryJobLocked(data, job)
+	return *copySummaryJob(job), nil
+}
+
+// checkStudentsExist returns an error wrapping ErrStudentNotFound for the
+// first of studentIDs that is not an existing student.
+func checkStudentsExist(ctx context.Context, studentIDs []int) error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range studentIDs {
+		if student, exists := data.students[id]; !exists || student.IsDeleted() {
+			return fmt.Errorf("%w: %d", ErrStudentNotFound, id)
+		}
+	}
+	return nil
+}
+
+func GetSummaryJob(ctx context.Context, id int) (models.SummaryJob, error) {
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+	data, err := summaryJobStores.get(ctx)
+	if err != nil {
+		return models.SummaryJob{}, err
+	}
+
+	job, exists := data.summaryJobs[id]
+	if !exists {
+		return models.SummaryJob{}, ErrSummaryJobNotFound
+	}
+	return *copySummaryJob(job), nil
+}
+
+func ListSummaryJobs(ctx context.Context) []models.SummaryJob {
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+	data, err := summaryJobStores.get(ctx)
+	if err != nil {
+		return []models.SummaryJob{}
+	}
+
+	result := make([]models.SummaryJob, 0, len(data.summaryJobs))
+	for _, job := range data.summaryJobs {
+		result = append(result, *copySummaryJob(job))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// CancelSummaryJob stops a job: pending students are skipped and in-flight
+// generations are cancelled.
+func CancelSummaryJob(ctx context.Context, id int) (models.SummaryJob, error) {
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+	data, err := summaryJobStores.get(ctx)
+	if err != nil {
+		return models.SummaryJob{}, err
+	}
+
+	job, exists := data.summaryJobs[id]
+	if !exists {
+		return models.SummaryJob{}, ErrSummaryJobNotFound
+	}
+	if job.IsFinished() {
+		return models.SummaryJob{}, ErrSummaryJobFinished
+	}
+
+	for i := range job.Results {
+		if job.Results[i].Status == models.SummaryResultPending {
+			job.Results[i].Status = models.SummaryResultCancelled
+		}
+	}
+	finishSummaryJobLocked(data, job, models.SummaryJobCancelled)
+	saveSummaryJobsLocked()
+	return *copySummaryJob(job), nil
+}
+
+// enqueueSummaryJobLocked feeds the job's pending students to the workers
+// without blocking the caller.
+func enqueueSummaryJobLocked(data *summaryJobStore, job *models.SummaryJob) {
+	ctx, cancel := context.WithCancel(reqctx.WithTenant(context.Background(), job.Tenant))
+	data.summaryJobContexts[job.ID] = ctx
+	data.summaryJobCancels[job.ID] = cancel
+
+	var pending []int
+	for _, result := range job.Results {
+		if result.Status == models.SummaryResultPending {
+			pending = append(pending, result.StudentID)
+		}
+	}
+
+	tenantID, jobID := job.Tenant, job.ID
+	tasks := summaryJobTasks
+	go func() {
+		for _, studentID := range pending {
+			select {
+			case <-ctx.Done():
+				return
+			case tasks <- summaryJobTask{tenantID: tenantID, jobID: jobID, studentID: studentID}:
+			}
+		}
+	}()
+}
+
+func processSummaryJobTask(summarizer SummaryProvider, task summaryJobTask) {
+	summaryJobMutex.Lock()
+	data, exists := summaryJobStores.lookup(task.tenantID)
+	if !exists {
+		summaryJobMutex.Unlock()
+		return
+	}
+	job, exists := data.summaryJobs[task.jobID]
+	if !exists || job.IsFinished() {
+		summaryJobMutex.Unlock()
+		return
+	}
+	if job.Status == models.SummaryJobQueued {
+		now := time.Now().UTC()
+		job.Status = models.SummaryJobRunning
+		job.StartedAt = &now
+		saveSummaryJobsLocked()
+	}
+	ctx := data.summaryJobContexts[task.jobID]
+	summaryJobMutex.Unlock()
+
+	var summary models.Summary
+	student, err := GetStudentByID(ctx, task.studentID)
+	if err == nil {
+		summary, err = summarizer.GenerateSummary(ctx, student, SummaryOptions{})
+	}
+	if err == nil {
+		summary, err = SaveSummary(ctx, summary)
+	}
+
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+
+	if job.IsFinished() {
+		return
+	}
+	for i := range job.Results {
+		result := &job.Results[i]
+		if result.StudentID != task.studentID || result.Status != models.SummaryResultPending {
+			continue
+		}
+		if err != nil {
+			result.Status = models.SummaryResultFailed
+			result.Error = err.Error()
+			job.Failed++
+		} else {
+			result.Status = models.SummaryResultSucceeded
+			result.SummaryID = summary.ID
+			job.Succeeded++
+		}
+	}
+
+	if job.Succeeded+job.Failed == job.Total {
+		finishSummaryJobLocked(data, job, models.SummaryJobCompleted)
+	}
+	saveSummaryJobsLocked()
+}
+
+func finishSummaryJobLocked(data *summaryJobStore, job *models.SummaryJob, status models.SummaryJobStatus) {
+	now := time.Now().UTC()
+	job.Status = status
+	job.FinishedAt = &now
+
+	if cancel, ok := data.summaryJobCancels[job.ID]; ok {
+		cancel()
+		delete(data.summaryJobCancels, job.ID)
+		delete(data.summaryJobContexts, job.ID)
+	}
+}
+
+func saveSummaryJobsLocked() {
+	if summaryJobsPath == "" {
+		return
+	}
+
+	var jobs []*models.SummaryJob
+	summaryJobStores.each(func(_ string, data *summaryJobStore) {
+		for _, job := range data.summaryJobs {
+			jobs = append(jobs, job)
+		}
+	})
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Tenant != jobs[j].Tenant {
+			return jobs[i].Tenant < jobs[j].Tenant
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	content, err := json.Marshal(jobs)
+	if err == nil {
+		err = writeFileAtomic(summaryJobsPath, content)
+	}
+	if err != nil {
+		log.Printf("failed to persist summary jobs: %v", err)
+	}
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func copySummaryJob(job *models.SummaryJob) *models.SummaryJob {
+	copied := *job
+	copied.Results = append([]models.SummaryJobResult{}, job.Results...)
+	return &copied
+}
+
+func ResetSummaryJobs() {
+	summaryJobMutex.Lock()
+	defer summaryJobMutex.Unlock()
+
+	summaryJobStores.each(func(_ string, data *summaryJobStore) {
+		cancelSummaryJobs(data)
+	})
+	summaryJobStores.reset()
+	summaryJobsPath = ""
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"student-api/internal/models"
+	"sync"
+	"testing"
+)
+
+type stubSummarizer struct {
+	mutex   sync.Mutex
+	release chan struct{}
+	failFor map[int]bool
+	calls   int
+}
+
+func (s *stubSummarizer) Models() []string {
+	return []string{"stub"}
+}
+
+func (s *stubSummarizer) GenerateSummary(ctx context.Context, student models.Student, opts SummaryOptions) (models.Summary, error) {
+	s.mutex.Lock()
+	s.calls++
+	fail := s.failFor[student.ID]
+	s.mutex.Unlock()
+
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return models.Summary{}, ctx.Err()
+		}
+	}
+	if fail {
+		return models.Summary{}, errors.New("generation failed")
+	}
+	return models.Summary{StudentID: student.ID, Text: "Summary for " + student.Name}, nil
+}
+
+func (s *stubSummarizer) StreamSummary(ctx context.Context, student models.Student, opts SummaryOptions, onToken func(token string) error) (models.Summary, error) {
+	return s.GenerateSummary(ctx, student, opts)
+}
+
+func setupSummaryJobTest(t *testing.T, summarizer SummaryProvider) {
+	ResetStudents()
+	ResetSummaries()
+	ResetSummaryJobs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		ResetSummaryJobs()
+	})
+	StartSummaryJobWorkers(ctx, summarizer, 2)
+}
+
+func waitForJob(t *testing.T, id int, status models.SummaryJobStatus) models.SummaryJob {
+	t.Helper()
+	var job models.SummaryJob
+	waitFor(t, func() bool {
+		job, _ = GetSummaryJob(context.Background(), id)
+		return job.Status == status
+	})
+	return job
+}
+
+func TestSummaryJobCompletes(t *testing.T) {
+	summarizer := &stubSummarizer{failFor: map[int]bool{2: true}}
+	setupSummaryJobTest(t, summarizer)
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		CreateStudent(context.Background(), models.Student{Name: name, Age: 20, Email: "student@example.com"})
+	}
+
+	if _, err := CreateSummaryJob(context.Background(), []int{1, 999}); !errors.Is(err, ErrStudentNotFound) {
+		t.Errorf("Expected ErrStudentNotFound for an unknown student, got %v", err)
+	}
+
+	job, err := CreateSummaryJob(context.Background(), []int{1, 2, 3, 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if job.Total != 3 {
+		t.Errorf("Expected duplicate IDs to be ignored, got %d students", job.Total)
+	}
+
+	job = waitForJob(t, job.ID, models.SummaryJobCompleted)
+	if job.Succeeded != 2 || job.Failed != 1 {
+		t.Errorf("Expected 2 succeeded and 1 failed, got %d and %d", job.Succeeded, job.Failed)
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil {
+		t.Error("Expected start and finish times to be recorded")
+	}
+
+	for _, result := range job.Results {
+		switch result.StudentID {
+		case 1, 3:
+			if result.Status != models.SummaryResultSucceeded || result.SummaryID == 0 {
+				t.Errorf("Expected stored summary for student %d, got %+v", result.StudentID, result)
+			}
+		case 2:
+			if result.Status != models.SummaryResultFailed || result.Error == "" {
+				t.Errorf("Expected failure for student %d, got %+v", result.StudentID, result)
+			}
+		}
+	}
+
+	if len(GetStudentSummaries(context.Background(), 1)) != 1 {
+		t.Error("Expected job results to be saved in the summary history")
+	}
+}
+
+func TestSummaryJobCancel(t *testing.T) {
+	summarizer := &stubSummarizer{release: make(chan struct{})}
+	setupSummaryJobTest(t, summarizer)
+
+	for i := 0; i < 5; i++ {
+		CreateStudent(context.Background(), models.Student{Name: "Student", Age: 20, Email: "student@example.com"})
+	}
+
+	job, _ := CreateSummaryJob(context.Background(), []int{1, 2, 3, 4, 5})
+	waitForJob(t, job.ID, models.SummaryJobRunning)
+
+	cancelled, err := CancelSummaryJob(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cancelled.Status != models.SummaryJobCancelled {
+		t.Errorf("Expected cancelled status, got %s", cancelled.Status)
+	}
+	for _, result := range cancelled.Results {
+		if result.Status != models.SummaryResultCancelled {
+			t.Errorf("Expected every unfinished student to be cancelled, got %+v", result)
+		}
+	}
+
+	if _, err := CancelSummaryJob(context.Background(), job.ID); err != ErrSummaryJobFinished {
+		t.Errorf("Expected ErrSummaryJobFinished, got %v", err)
+	}
+	if _, err := CancelSummaryJob(context.Background(), 999); err != ErrSummaryJobNotFound {
+		t.Errorf("Expected ErrSummaryJobNotFound, got %v", err)
+	}
+	if _, err := CreateSummaryJob(context.Background(), nil); err != ErrSummaryJobEmpty {
+		t.Errorf("Expected ErrSummaryJobEmpty, got %v", err)
+	}
+}
+
+func TestSummaryJobResumesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	ResetStudents()
+	ResetSummaries()
+	ResetSummaryJobs()
+	CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+
+	if err := OpenSummaryJobStore(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	job, _ := CreateSummaryJob(context.Background(), []int{1})
+
+	// Simulate a restart before any worker picked the job up.
+	ResetSummaryJobs()
+	if err := OpenSummaryJobStore(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loaded, err := GetSummaryJob(context.Background(), job.ID); err != nil || loaded.Status != models.SummaryJobQueued {
+		t.Fatalf("Expected queued job to be reloaded, got %+v, %v", loaded, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer ResetSummaryJobs()
+	StartSummaryJobWorkers(ctx, &stubSummarizer{}, 1)
+
+	completed := waitForJob(t, job.ID, models.SummaryJobCompleted)
+	if completed.Succeeded != 1 {
+		t.Errorf("Expected resumed job to succeed, got %+v", completed)
+	}
+
+	next, _ := CreateSummaryJob(context.Background(), []int{1})
+	if next.ID != job.ID+1 {
+		t.Errorf("Expected job IDs to continue after reload, got %d", next.ID)
+	}
+}
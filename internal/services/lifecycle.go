@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"time"
+)
+
+// TransitionStudent moves a student to the requested status if the lifecycle
+// allows it, recording the reason and the actor making the change.
+func TransitionStudent(ctx context.Context, id int, request models.TransitionRequest) (models.StatusTransition, error) {
+	if err := request.Validate(); err != nil {
+		return models.StatusTransition{}, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.StatusTransition{}, err
+	}
+
+	existing, exists := data.students[id]
+	if !exists || existing.IsDeleted() {
+		return models.StatusTransition{}, ErrStudentNotFound
+	}
+	if !existing.Status.CanTransitionTo(request.Status) {
+		return models.StatusTransition{}, fmt.Errorf("%w: %s to %s", ErrInvalidStudentTransition, existing.Status, request.Status)
+	}
+
+	transition := models.StatusTransition{
+		ID:        data.nextTransitionID,
+		StudentID: id,
+		From:      existing.Status,
+		To:        request.Status,
+		Reason:    request.Reason,
+		Actor:     reqctx.Actor(ctx),
+		Timestamp: time.Now().UTC(),
+	}
+	data.nextTransitionID++
+	data.transitions[id] = append(data.transitions[id], transition)
+
+	updated := existing
+	updated.Status = request.Status
+	data.students[id] = updated
+	recordAudit(ctx, models.AuditActionTransition, id, &existing, &updated)
+	publishEvent(ctx, models.StudentStatusChanged, updated)
+	return transition, nil
+}
+
+// GetStudentTransitions returns an existing student's status transitions,
+// oldest first.
+func GetStudentTransitions(ctx context.Context, id int) ([]models.StatusTransition, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	student, exists := data.students[id]
+	if !exists || student.IsDeleted() {
+		return nil, ErrStudentNotFound
+	}
+	return append([]models.StatusTransition{}, data.transitions[id]...), nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"testing"
+)
+
+func TestTransitionStudent(t *testing.T) {
+	ResetStudents()
+	ResetAuditLog()
+	ResetEvents()
+
+	ctx := reqctx.WithActor(context.Background(), "registrar")
+	student, _ := CreateStudent(ctx, models.Student{Name: "Alice", Age: 20, Email: "alice@example.com", Status: models.StudentApplied})
+	_, events, unsubscribe := SubscribeEvents(ctx, 0)
+	defer unsubscribe()
+
+	transition, err := TransitionStudent(ctx, student.ID, models.TransitionRequest{Status: models.StudentEnrolled, Reason: "Offer accepted"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transition.From != models.StudentApplied || transition.To != models.StudentEnrolled || transition.Actor != "registrar" || transition.Reason != "Offer accepted" {
+		t.Errorf("Unexpected transition %+v", transition)
+	}
+	if updated, _ := GetStudentByID(ctx, student.ID); updated.Status != models.StudentEnrolled {
+		t.Errorf("Expected status enrolled, got %s", updated.Status)
+	}
+	if event := <-events; event.Type != models.StudentStatusChanged {
+		t.Errorf("Expected a status change event, got %s", event.Type)
+	}
+	history := GetStudentHistory(ctx, student)
+	if last := history[len(history)-1]; last.Action != models.AuditActionTransition {
+		t.Errorf("Expected a transition audit event, got %s", last.Action)
+	}
+
+	_, err = TransitionStudent(ctx, student.ID, models.TransitionRequest{Status: models.StudentApplied, Reason: "Undo"})
+	if !errors.Is(err, ErrInvalidStudentTransition) {
+		t.Errorf("Expected ErrInvalidStudentTransition, got %v", err)
+	}
+	if _, err := TransitionStudent(ctx, 99, models.TransitionRequest{Status: models.StudentEnrolled, Reason: "Missing"}); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+
+	TransitionStudent(ctx, student.ID, models.TransitionRequest{Status: models.StudentGraduated, Reason: "Completed the programme"})
+	transitions, err := GetStudentTransitions(ctx, student.ID)
+	if err != nil || len(transitions) != 2 || transitions[1].To != models.StudentGraduated {
+		t.Errorf("Expected two transitions ending in graduated, got %+v, %v", transitions, err)
+	}
+}
+
+func TestUpdateStudentRejectsStatusChange(t *testing.T) {
+	ResetStudents()
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com"})
+	student.Status = models.StudentGraduated
+	if _, err := UpdateStudent(context.Background(), student.ID, student); err != ErrStatusChangeNotAllowed {
+		t.Errorf("Expected ErrStatusChangeNotAllowed, got %v", err)
+	}
+
+	student.Status = models.StudentEnrolled
+	if _, err := UpdateStudent(context.Background(), student.ID, student); err != nil {
+		t.Errorf("Expected an unchanged status to be accepted, got %v", err)
+	}
+}
+
+func TestListStudentsByStatus(t *testing.T) {
+	ResetStudents()
+
+	CreateStudent(context.Background(), models.Student{Name: "Alice", Age: 20, Email: "alice@example.com", Status: models.StudentApplied})
+	bob, _ := CreateStudent(context.Background(), models.Student{Name: "Bob", Age: 21, Email: "bob@example.com"})
+	CreateStudent(context.Background(), models.Student{Name: "Carol", Age: 22, Email: "carol@example.com"})
+	TransitionStudent(context.Background(), bob.ID, models.TransitionRequest{Status: models.StudentSuspended, Reason: "Unpaid fees"})
+
+	if matches := ListStudents(context.Background(), StudentFilter{Status: []models.StudentStatus{models.StudentEnrolled}}); len(matches) != 1 || matches[0].Name != "Carol" {
+		t.Errorf("Expected only Carol to be enrolled, got %+v", matches)
+	}
+	if matches := ListStudents(context.Background(), StudentFilter{Status: []models.StudentStatus{models.StudentApplied, models.StudentSuspended}}); len(matches) != 2 {
+		t.Errorf("Expected two applied or suspended students, got %d", len(matches))
+	}
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrProviderUnavailable = errors.New("model provider unavailable")
+	ErrProviderTimeout     = errors.New("model provider timed out")
+	ErrProviderBadResponse = errors.New("model provider returned an invalid response")
+)
+
+// HTTPSettings configure an LLMClient: where the provider is and how calls to
+// it are bounded, retried and broken.
+type HTTPSettings struct {
+	BaseURL             string
+	APIKey              string
+	Timeout             time.Duration
+	MaxRetries          int
+	RetryBaseDelay      time.Duration
+	BreakerThreshold    int
+	BreakerResetTimeout time.Duration
+}
+
+func DefaultHTTPSettings() HTTPSettings {
+	return HTTPSettings{
+		BaseURL:             "http://localhost:11434",
+		Timeout:             60 * time.Second,
+		MaxRetries:          2,
+		RetryBaseDelay:      200 * time.Millisecond,
+		BreakerThreshold:    5,
+		BreakerResetTimeout: 30 * time.Second,
+	}
+}
+
+// LLMClient talks to the HTTP API of a model provider, either Ollama's own
+// API or an OpenAI-compatible one. Transient failures are retried with
+// jittered exponential backoff and a circuit breaker fails calls fast while
+// the provider is down. Errors wrap ErrProviderUnavailable,
+// ErrProviderTimeout or ErrProviderBadResponse, except when the caller's
+// context is cancelled.
+type LLMClient struct {
+	settings   HTTPSettings
+	httpClient *http.Client
+	breaker    *CircuitBreaker
+}
+
+func NewLLMClient(settings HTTPSettings) *LLMClient {
+	return &LLMClient{
+		settings: settings,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
+				ResponseHeaderTimeout: settings.Timeout,
+			},
+		},
+		breaker: NewCircuitBreaker(settings.BreakerThreshold, settings.BreakerResetTimeout),
+	}
+}
+
+func (c *LLMClient) BaseURL() string {
+	return c.settings.BaseURL
+}
+
+// Generate performs a non-streaming /api/generate call bounded by the
+// configured timeout.
+func (c *LLMClient) Generate(ctx context.Context, reqBody OllamaRequest) (OllamaResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.settings.Timeout)
+	defer cancel()
+
+	reqBody.Stream = false
+	resp, err := c.post(ctx, "/api/generate", reqBody)
+	if err != nil {
+		return OllamaResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var ollamaResp OllamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return OllamaResponse{}, c.classify(ctx, err)
+	}
+	if ollamaResp.Error != "" {
+		return OllamaResponse{}, fmt.Errorf("%w: %s", ErrProviderBadResponse, ollamaResp.Error)
+	}
+	return ollamaResp, nil
+}
+
+// Stream performs a streaming /api/generate call, invoking onChunk for every
+// NDJSON chunk until Ollama reports done. Only establishing the stream is
+// retried. The stream has no overall deadline, but fails with
+// ErrProviderTimeout when no chunk arrives within the configured timeout.
+func (c *LLMClient) Stream(ctx context.Context, reqBody OllamaRequest, onChunk func(chunk OllamaResponse) error) error {
+	ctx, idle := newStreamIdleTimer(ctx, c.settings.Timeout)
+	defer idle.stop()
+
+	reqBody.Stream = true
+	resp, err := c.post(ctx, "/api/generate", reqBody)
+	if err != nil {
+		return idle.check(err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		idle.reset()
+		var chunk OllamaResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("%w: stream ended before completion", ErrProviderBadResponse)
+			}
+			return idle.check(c.classify(ctx, err))
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("%w: %s", ErrProviderBadResponse, chunk.Error)
+		}
+		if err := onChunk(chunk); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+}
+
+// post sends body to path, retrying transient failures, and returns a
+// response with a 2xx status that the caller must close.
+func (c *LLMClient) post(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	return c.do(ctx, "POST", path, body)
+}
+
+func (c *LLMClient) do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		if jsonData, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithJitter(ctx, c.settings.RetryBaseDelay, attempt); err != nil {
+				return nil, c.classify(ctx, err)
+			}
+		}
+
+		if !c.breaker.Allow() {
+			return nil, fmt.Errorf("%w: circuit breaker open", ErrProviderUnavailable)
+		}
+
+		resp, err := c.send(ctx, method, path, jsonData)
+		if err == nil {
+			c.breaker.RecordSuccess()
+			return resp, nil
+		}
+
+		if ctx.Err() == context.Canceled {
+			return nil, ctx.Err()
+		}
+
+		lastErr = err
+		if !isTransient(err) {
+			if errors.Is(err, ErrProviderBadResponse) {
+				// Ollama answered, so it is up even though the request was rejected.
+				c.breaker.RecordSuccess()
+			}
+			return nil, err
+		}
+		c.breaker.RecordFailure()
+	}
+
+	return nil, lastErr
+}
+
+func (c *LLMClient) send(ctx context.Context, method, path string, jsonData []byte) (*http.Response, error) {
+	var body io.Reader
+	if jsonData != nil {
+		body = bytes.NewReader(jsonData)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.settings.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.settings.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.settings.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.classify(ctx, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return nil, &ollamaStatusError{StatusCode: resp.StatusCode, kind: ErrProviderUnavailable}
+		}
+		return nil, &ollamaStatusError{StatusCode: resp.StatusCode, kind: ErrProviderBadResponse}
+	}
+	return resp, nil
+}
+
+func (c *LLMClient) classify(ctx context.Context, err error) error {
+	if ctx.Err() == context.Canceled {
+		return ctx.Err()
+	}
+	if ctx.Err() == context.DeadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrProviderTimeout, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %v", ErrProviderTimeout, err)
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %v", ErrProviderBadResponse, err)
+	}
+	return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+}
+
+// ollamaStatusError reports a non-2xx status. It unwraps to
+// ErrProviderUnavailable or ErrProviderBadResponse while keeping the status code
+// available to callers that treat particular codes specially.
+type ollamaStatusError struct {
+	StatusCode int
+	kind       error
+}
+
+func (e *ollamaStatusError) Error() string {
+	return fmt.Sprintf("%v: unexpected status %d", e.kind, e.StatusCode)
+}
+
+func (e *ollamaStatusError) Unwrap() error {
+	return e.kind
+}
+
+// streamIdleTimer cancels a streamed request when the provider sends nothing
+// for the timeout, so a stalled stream cannot hold its caller forever.
+type streamIdleTimer struct {
+	timeout time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	expired int32
+}
+
+func newStreamIdleTimer(ctx context.Context, timeout time.Duration) (context.Context, *streamIdleTimer) {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &streamIdleTimer{timeout: timeout, cancel: cancel}
+	t.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&t.expired, 1)
+		cancel()
+	})
+	return ctx, t
+}
+
+// reset gives the provider another timeout to send the next chunk.
+func (t *streamIdleTimer) reset() {
+	t.timer.Reset(t.timeout)
+}
+
+func (t *streamIdleTimer) stop() {
+	t.timer.Stop()
+	t.cancel()
+}
+
+// check returns err as ErrProviderTimeout when the timer cancelled the
+// request, rather than as the cancellation it caused.
+func (t *streamIdleTimer) check(err error) error {
+	if atomic.LoadInt32(&t.expired) == 1 {
+		return fmt.Errorf("%w: no data for %s", ErrProviderTimeout, t.timeout)
+	}
+	return err
+}
+
+func isTransient(err error) bool {
+	return errors.Is(err, ErrProviderUnavailable) || errors.Is(err, ErrProviderTimeout)
+}
+
+func sleepWithJitter(ctx context.Context, base time.Duration, attempt int) error {
+	backoff := base << uint(attempt-1)
+	delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
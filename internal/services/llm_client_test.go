@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testOllamaSettings(baseURL string) HTTPSettings {
+	return HTTPSettings{
+		BaseURL:             baseURL,
+		Timeout:             time.Second,
+		MaxRetries:          2,
+		RetryBaseDelay:      time.Millisecond,
+		BreakerThreshold:    5,
+		BreakerResetTimeout: time.Minute,
+	}
+}
+
+func TestOllamaClientGenerate(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       func(calls int32) http.HandlerFunc
+		expectedErr   error
+		expectedCalls int32
+	}{
+		{
+			name: "Success",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, `{"response":"John is motivated.","done":true}`)
+				}
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "Retries transient errors",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					if calls < 3 {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					fmt.Fprint(w, `{"response":"John is motivated.","done":true}`)
+				}
+			},
+			expectedCalls: 3,
+		},
+		{
+			name: "Gives up after retries",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			},
+			expectedErr:   ErrProviderUnavailable,
+			expectedCalls: 3,
+		},
+		{
+			name: "Does not retry client errors",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			expectedErr:   ErrProviderBadResponse,
+			expectedCalls: 1,
+		},
+		{
+			name: "Invalid JSON",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, `not json`)
+				}
+			},
+			expectedErr:   ErrProviderBadResponse,
+			expectedCalls: 1,
+		},
+		{
+			name: "Error field",
+			handler: func(calls int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, `{"error":"model 'llama3' not found"}`)
+				}
+			},
+			expectedErr:   ErrProviderBadResponse,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handler(atomic.AddInt32(&calls, 1))(w, r)
+			}))
+			defer server.Close()
+
+			client := NewLLMClient(testOllamaSettings(server.URL))
+			resp, err := client.Generate(context.Background(), OllamaRequest{Model: "llama3", Prompt: "hi"})
+
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if resp.Response != "John is motivated." {
+					t.Errorf("Unexpected response: %s", resp.Response)
+				}
+			} else if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, got)
+			}
+		})
+	}
+}
+
+func TestOllamaClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	settings := testOllamaSettings(server.URL)
+	settings.Timeout = 20 * time.Millisecond
+	client := NewLLMClient(settings)
+
+	_, err := client.Generate(context.Background(), OllamaRequest{Model: "llama3"})
+	if !errors.Is(err, ErrProviderTimeout) {
+		t.Errorf("Expected ErrProviderTimeout, got %v", err)
+	}
+}
+
+func TestOllamaClientStreamIdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		// Each chunk arrives within the timeout, then the stream stalls.
+		for i := 0; i < 3; i++ {
+			fmt.Fprint(w, `{"response":"token","status":"downloading"}`+"\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	settings := testOllamaSettings(server.URL)
+	settings.Timeout = 50 * time.Millisecond
+	client := NewLLMClient(settings)
+
+	chunks := 0
+	err := client.Stream(context.Background(), OllamaRequest{Model: "llama3"}, func(chunk OllamaResponse) error {
+		chunks++
+		return nil
+	})
+	if !errors.Is(err, ErrProviderTimeout) {
+		t.Errorf("Expected ErrProviderTimeout, got %v", err)
+	}
+	if chunks != 3 {
+		t.Errorf("Expected the chunks before the stall to be delivered, got %d", chunks)
+	}
+
+	err = client.PullModel(context.Background(), "llama3", func(OllamaPullProgress) error { return nil })
+	if !errors.Is(err, ErrProviderTimeout) {
+		t.Errorf("Expected ErrProviderTimeout from a stalled pull, got %v", err)
+	}
+}
+
+func TestOllamaClientCircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	settings := testOllamaSettings(server.URL)
+	settings.MaxRetries = 0
+	settings.BreakerThreshold = 2
+	client := NewLLMClient(settings)
+
+	for i := 0; i < 2; i++ {
+		client.Generate(context.Background(), OllamaRequest{Model: "llama3"})
+	}
+
+	_, err := client.Generate(context.Background(), OllamaRequest{Model: "llama3"})
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected open circuit to skip the request, got %d calls", got)
+	}
+}
+
+func TestOllamaClientUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	baseURL := server.URL
+	server.Close()
+
+	client := NewLLMClient(testOllamaSettings(baseURL))
+	_, err := client.Generate(context.Background(), OllamaRequest{Model: "llama3"})
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
+
+func TestOllamaClientCallerCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	client := NewLLMClient(testOllamaSettings(server.URL))
+	_, err := client.Generate(ctx, OllamaRequest{Model: "llama3"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if client.breaker.IsOpen() {
+		t.Error("Expected caller cancellation not to trip the circuit breaker")
+	}
+}
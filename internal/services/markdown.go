@@ -0,0 +1,117 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownNumbered    = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	markdownRule        = regexp.MustCompile(`^\s*(-{3,}|\*{3,}|_{3,})\s*$`)
+	markdownStrong      = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	markdownEmphasis    = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*|\b_(\S(?:[^_]*?\S)?)_\b`)
+	markdownBlockEnders = []*regexp.Regexp{markdownHeading, markdownBullet, markdownNumbered, markdownRule}
+)
+
+// renderMarkdownHTML converts the subset of Markdown that reports use to
+// HTML: headings, paragraphs, bulleted and numbered lists, horizontal rules,
+// strong and emphasised text and inline code. Everything else is kept as
+// text. The input is escaped first, so model output cannot inject markup.
+func renderMarkdownHTML(markdown string) string {
+	var b strings.Builder
+	var paragraph []string
+	list := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderMarkdownInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+		if !startsMarkdownBlock(line) && len(paragraph) > 0 {
+			paragraph = append(paragraph, strings.TrimSpace(line))
+			continue
+		}
+		flushParagraph()
+
+		switch {
+		case markdownRule.MatchString(line):
+			closeList()
+			b.WriteString("<hr>\n")
+		case markdownHeading.MatchString(line):
+			closeList()
+			match := markdownHeading.FindStringSubmatch(line)
+			level := string(rune('0' + len(match[1])))
+			b.WriteString("<h" + level + ">" + renderMarkdownInline(strings.TrimRight(match[2], " #")) + "</h" + level + ">\n")
+		case markdownBullet.MatchString(line):
+			openList("ul")
+			b.WriteString("<li>" + renderMarkdownInline(markdownBullet.FindStringSubmatch(line)[1]) + "</li>\n")
+		case markdownNumbered.MatchString(line):
+			openList("ol")
+			b.WriteString("<li>" + renderMarkdownInline(markdownNumbered.FindStringSubmatch(line)[1]) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+	}
+	flushParagraph()
+	closeList()
+	return b.String()
+}
+
+func startsMarkdownBlock(line string) bool {
+	for _, pattern := range markdownBlockEnders {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderMarkdownInline escapes text and converts its inline code, strong and
+// emphasised spans. Code spans are left untouched by the other conversions.
+func renderMarkdownInline(text string) string {
+	parts := strings.Split(text, "`")
+	var b strings.Builder
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			b.WriteString("<code>" + escaped + "</code>")
+		case i%2 == 1:
+			// An unmatched backtick is kept as text.
+			b.WriteString("`" + renderMarkdownEmphasis(escaped))
+		default:
+			b.WriteString(renderMarkdownEmphasis(escaped))
+		}
+	}
+	return b.String()
+}
+
+func renderMarkdownEmphasis(text string) string {
+	text = markdownStrong.ReplaceAllString(text, "<strong>$1$2</strong>")
+	return markdownEmphasis.ReplaceAllString(text, "<em>$1$2</em>")
+}
@@ -0,0 +1,50 @@
+package services
+
+import "testing"
+
+func TestRenderMarkdownHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "Headings and paragraphs",
+			markdown: "# Title\n\nFirst line\ncontinues here.\n\n### Section ##",
+			expected: "<h1>Title</h1>\n<p>First line continues here.</p>\n<h3>Section</h3>\n",
+		},
+		{
+			name:     "Lists",
+			markdown: "- one\n* two\n1. first\n2) second",
+			expected: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			name:     "Inline formatting",
+			markdown: "A **bold**, *soft* and _quiet_ `x*y*z` word",
+			expected: "<p>A <strong>bold</strong>, <em>soft</em> and <em>quiet</em> <code>x*y*z</code> word</p>\n",
+		},
+		{
+			name:     "Rule",
+			markdown: "Above\n\n---\n\nBelow",
+			expected: "<p>Above</p>\n<hr>\n<p>Below</p>\n",
+		},
+		{
+			name:     "Markup is escaped",
+			markdown: "<script>alert('x')</script> & `<b>`",
+			expected: "<p>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; &amp; <code>&lt;b&gt;</code></p>\n",
+		},
+		{
+			name:     "Snake case is kept",
+			markdown: "Use student_number and 2 * 3 * 4",
+			expected: "<p>Use student_number and 2 * 3 * 4</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if html := renderMarkdownHTML(tt.markdown); html != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, html)
+			}
+		})
+	}
+}
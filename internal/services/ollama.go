@@ -1,73 +1,241 @@
 package services
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 	"student-api/internal/models"
+	"time"
 )
 
-type OllamaServiceInterface interface {
-	GenerateSummary(student models.Student) (string, error)
-}
+const SummaryModel = "llama3"
 
 type OllamaService struct {
-	BaseURL string
+	Client *LLMClient
+	// AllowedModels lists the models callers may request, default first.
+	AllowedModels []string
+	System        string
+	KeepAlive     string
+	// OutputRetries is how many times GenerateSummary asks again when the
+	// model's structured output does not match summarySchema.
+	OutputRetries int
 }
 
 type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model     string                    `json:"model"`
+	Prompt    string                    `json:"prompt"`
+	System    string                    `json:"system,omitempty"`
+	Stream    bool                      `json:"stream"`
+	Format    json.RawMessage           `json:"format,omitempty"`
+	Options   *models.GenerationOptions `json:"options,omitempty"`
+	KeepAlive string                    `json:"keep_alive,omitempty"`
 }
 
 type OllamaResponse struct {
-	Response string `json:"response"`
+	Model           string `json:"model"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	Error           string `json:"error,omitempty"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
-func NewOllamaService() *OllamaService {
+func NewOllamaService(client *LLMClient) *OllamaService {
 	return &OllamaService{
-		BaseURL: "http://localhost:11434",
+		Client:        client,
+		AllowedModels: []string{SummaryModel},
+		OutputRetries: 2,
+	}
+}
+
+func (s *OllamaService) Models() []string {
+	return s.AllowedModels
+}
+
+// GenerateSummary requests a structured summary constrained by
+// summarySchema, asking again up to OutputRetries times when the output does
+// not validate.
+func (s *OllamaService) GenerateSummary(ctx context.Context, student models.Student, opts SummaryOptions) (models.Summary, error) {
+	reqBody, promptTemplate, err := s.summaryRequest(ctx, student, opts)
+	if err != nil {
+		return models.Summary{}, err
+	}
+	reqBody.Prompt += structuredOutputInstruction
+	reqBody.Format = summarySchema
+
+	var lastErr error
+	for attempt := 0; attempt <= s.OutputRetries; attempt++ {
+		ollamaResp, err := s.Client.Generate(ctx, reqBody)
+		if err != nil {
+			return models.Summary{}, err
+		}
+
+		output, err := parseStructuredSummary(ollamaResp.Response)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		summary := newOllamaSummary(student, promptTemplate, reqBody, ollamaResp, output.Summary)
+		summary.Strengths = output.Strengths
+		summary.NextSteps = output.NextSteps
+		return summary, nil
 	}
+
+	return models.Summary{}, fmt.Errorf("%w: invalid structured output after %d attempts: %v", ErrProviderBadResponse, s.OutputRetries+1, lastErr)
 }
 
-func (s *OllamaService) GenerateSummary(student models.Student) (string, error) {
-	prompt := fmt.Sprintf(`Generate a professional summary for this student profile:
-Name: %s
-Age: %d
-Email: %s
-ID: %d
+// StreamSummary asks Ollama for a streamed summary, calling onToken for each
+// chunk as it arrives, and returns the cleaned full summary once Ollama is
+// done. Streamed summaries are free text, since partial JSON is of no use to
+// a reader and invalid output cannot be retried once tokens are sent.
+// Cancelling ctx aborts the upstream request.
+func (s *OllamaService) StreamSummary(ctx context.Context, student models.Student, opts SummaryOptions, onToken func(token string) error) (models.Summary, error) {
+	reqBody, promptTemplate, err := s.summaryRequest(ctx, student, opts)
+	if err != nil {
+		return models.Summary{}, err
+	}
 
-Please provide a brief, professional summary of this student in 2-3 sentences. Return only the summary without any prefixes or headers.`,
-		student.Name, student.Age, student.Email, student.ID)
+	text, final, err := s.stream(ctx, reqBody, onToken)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	return newOllamaSummary(student, promptTemplate, reqBody, final, cleanSummaryResponse(text)), nil
+}
 
+// StreamCohortReport asks Ollama for a streamed cohort report. Unlike
+// summaries, the report keeps its line breaks, which carry its Markdown
+// structure.
+func (s *OllamaService) StreamCohortReport(ctx context.Context, cohort models.Cohort, opts SummaryOptions, onToken func(token string) error) (models.CohortReport, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+	prompt, promptTemplate, err := buildCohortPrompt(cohort)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
 	reqBody := OllamaRequest{
-		Model:  "llama3",
-		Prompt: prompt,
-		Stream: false,
+		Model:     model,
+		Prompt:    prompt,
+		System:    s.System,
+		Options:   generationOptions(opts.Generation),
+		KeepAlive: s.KeepAlive,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	text, final, err := s.stream(ctx, reqBody, onToken)
 	if err != nil {
-		return "", err
+		return models.CohortReport{}, err
 	}
 
-	resp, err := http.Post(s.BaseURL+"/api/generate", "application/json", bytes.NewBuffer(jsonData))
+	if final.Model != "" {
+		model = final.Model
+	}
+	report := newCohortReport(cohort, model, promptTemplate.Version, opts.Generation, text)
+	report.PromptTokens = final.PromptEvalCount
+	report.CompletionTokens = final.EvalCount
+	return report, nil
+}
+
+// TranslateStudentQuery asks Ollama for a student filter constrained by
+// studentQuerySchema, asking again up to OutputRetries times when the filter
+// does not validate.
+func (s *OllamaService) TranslateStudentQuery(ctx context.Context, question string, opts SummaryOptions) (StudentQuery, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return StudentQuery{}, err
+	}
+	prompt, promptTemplate, err := buildStudentQueryPrompt(question)
 	if err != nil {
-		return "", err
+		return StudentQuery{}, err
 	}
-	defer resp.Body.Close()
+	reqBody := OllamaRequest{
+		Model:     model,
+		Prompt:    prompt,
+		System:    s.System,
+		Format:    studentQuerySchema,
+		Options:   generationOptions(opts.Generation),
+		KeepAlive: s.KeepAlive,
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.OutputRetries; attempt++ {
+		ollamaResp, err := s.Client.Generate(ctx, reqBody)
+		if err != nil {
+			return StudentQuery{}, err
+		}
 
-	var ollamaResp OllamaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return "", err
+		filter, err := parseStudentQueryFilter(ollamaResp.Response)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if ollamaResp.Model != "" {
+			model = ollamaResp.Model
+		}
+		return newStudentQuery(question, filter, model, promptTemplate.Version), nil
 	}
 
-	cleanedResponse := cleanSummaryResponse(ollamaResp.Response)
+	return StudentQuery{}, fmt.Errorf("%w: invalid student filter after %d attempts: %v", ErrProviderBadResponse, s.OutputRetries+1, lastErr)
+}
 
-	return cleanedResponse, nil
+// stream sends reqBody as a streamed request, calling onToken for each chunk
+// of text, and returns the full text with Ollama's final chunk.
+func (s *OllamaService) stream(ctx context.Context, reqBody OllamaRequest, onToken func(token string) error) (string, OllamaResponse, error) {
+	var full strings.Builder
+	var final OllamaResponse
+	err := s.Client.Stream(ctx, reqBody, func(chunk OllamaResponse) error {
+		if chunk.Done {
+			final = chunk
+		}
+		if chunk.Response == "" {
+			return nil
+		}
+		full.WriteString(chunk.Response)
+		return onToken(chunk.Response)
+	})
+	return full.String(), final, err
+}
+
+func (s *OllamaService) summaryRequest(ctx context.Context, student models.Student, opts SummaryOptions) (OllamaRequest, *PromptTemplate, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return OllamaRequest{}, nil, err
+	}
+	prompt, promptTemplate, err := buildSummaryPrompt(ctx, student, opts.Style)
+	if err != nil {
+		return OllamaRequest{}, nil, err
+	}
+
+	return OllamaRequest{
+		Model:     model,
+		Prompt:    prompt,
+		System:    s.System,
+		Options:   generationOptions(opts.Generation),
+		KeepAlive: s.KeepAlive,
+	}, promptTemplate, nil
+}
+
+func newOllamaSummary(student models.Student, promptTemplate *PromptTemplate, req OllamaRequest, resp OllamaResponse, text string) models.Summary {
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	return models.Summary{
+		StudentID:        student.ID,
+		Text:             text,
+		Model:            model,
+		Style:            promptTemplate.Style,
+		PromptVersion:    promptTemplate.Version,
+		Options:          req.Options,
+		GeneratedAt:      time.Now().UTC(),
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+	}
 }
 
 func cleanSummaryResponse(response string) string {
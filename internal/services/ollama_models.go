@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrOllamaModelNotFound = errors.New("ollama model not found")
+
+type OllamaModel struct {
+	Name       string             `json:"name"`
+	Model      string             `json:"model"`
+	ModifiedAt time.Time          `json:"modified_at"`
+	Size       int64              `json:"size"`
+	Digest     string             `json:"digest"`
+	Details    OllamaModelDetails `json:"details"`
+}
+
+type OllamaModelDetails struct {
+	Format            string `json:"format"`
+	Family            string `json:"family"`
+	ParameterSize     string `json:"parameter_size"`
+	QuantizationLevel string `json:"quantization_level"`
+}
+
+// OllamaModelInfo is the response of /api/show.
+type OllamaModelInfo struct {
+	Modelfile  string             `json:"modelfile"`
+	Parameters string             `json:"parameters"`
+	Template   string             `json:"template"`
+	Details    OllamaModelDetails `json:"details"`
+	ModifiedAt time.Time          `json:"modified_at"`
+}
+
+type OllamaPullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ollamaModelRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream,omitempty"`
+}
+
+// ListModels returns the models available locally at Ollama (/api/tags).
+func (c *LLMClient) ListModels(ctx context.Context) ([]OllamaModel, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.settings.Timeout)
+	defer cancel()
+
+	resp, err := c.do(ctx, "GET", "/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tags struct {
+		Models []OllamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, c.classify(ctx, err)
+	}
+	if tags.Models == nil {
+		tags.Models = []OllamaModel{}
+	}
+	return tags.Models, nil
+}
+
+// ShowModel returns the details of a local model (/api/show). A model that is
+// not available returns an error wrapping ErrOllamaModelNotFound.
+func (c *LLMClient) ShowModel(ctx context.Context, name string) (OllamaModelInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.settings.Timeout)
+	defer cancel()
+
+	resp, err := c.post(ctx, "/api/show", ollamaModelRequest{Model: name})
+	if err != nil {
+		var statusErr *ollamaStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return OllamaModelInfo{}, fmt.Errorf("%w: %s", ErrOllamaModelNotFound, name)
+		}
+		return OllamaModelInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	var info OllamaModelInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return OllamaModelInfo{}, c.classify(ctx, err)
+	}
+	return info, nil
+}
+
+// PullModel downloads a model (/api/pull), invoking onProgress for every
+// progress update until Ollama reports success. Like Stream, only
+// establishing the request is retried and the download has no overall
+// deadline, since pulls can take far longer than the request timeout, but
+// fails with ErrProviderTimeout when no update arrives within it.
+func (c *LLMClient) PullModel(ctx context.Context, name string, onProgress func(progress OllamaPullProgress) error) error {
+	ctx, idle := newStreamIdleTimer(ctx, c.settings.Timeout)
+	defer idle.stop()
+
+	resp, err := c.post(ctx, "/api/pull", ollamaModelRequest{Model: name, Stream: true})
+	if err != nil {
+		return idle.check(err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		idle.reset()
+		var progress OllamaPullProgress
+		if err := decoder.Decode(&progress); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("%w: pull ended before completion", ErrProviderBadResponse)
+			}
+			return idle.check(c.classify(ctx, err))
+		}
+		if progress.Error != "" {
+			return fmt.Errorf("%w: %s", ErrProviderBadResponse, progress.Error)
+		}
+		if err := onProgress(progress); err != nil {
+			return err
+		}
+		if progress.Status == "success" {
+			return nil
+		}
+	}
+}
+
+// MissingOllamaModels returns the names in required that are not available
+// at Ollama. A name without a tag matches the model's "latest" tag.
+func MissingOllamaModels(ctx context.Context, client *LLMClient, required []string) ([]string, error) {
+	available, err := client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range required {
+		if !ollamaModelAvailable(available, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+func ollamaModelAvailable(available []OllamaModel, name string) bool {
+	if !strings.Contains(name, ":") {
+		name += ":latest"
+	}
+	for _, model := range available {
+		if model.Name == name || model.Model == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaClientListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/tags" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"models":[{"name":"llama3:latest","model":"llama3:latest","size":4661224676,"digest":"365c0bd3c000","details":{"family":"llama","parameter_size":"8.0B","quantization_level":"Q4_0"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewLLMClient(testOllamaSettings(server.URL))
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(models) != 1 || models[0].Name != "llama3:latest" || models[0].Details.ParameterSize != "8.0B" {
+		t.Errorf("Unexpected models: %+v", models)
+	}
+}
+
+func TestOllamaClientShowModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaModelRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "llama3" {
+			http.Error(w, `{"error":"model 'missing' not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"modelfile":"FROM llama3","parameters":"stop <|eot_id|>","details":{"family":"llama"}}`)
+	}))
+	defer server.Close()
+
+	client := NewLLMClient(testOllamaSettings(server.URL))
+	info, err := client.ShowModel(context.Background(), "llama3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Modelfile != "FROM llama3" || info.Details.Family != "llama" {
+		t.Errorf("Unexpected model info: %+v", info)
+	}
+
+	_, err = client.ShowModel(context.Background(), "missing")
+	if !errors.Is(err, ErrOllamaModelNotFound) {
+		t.Errorf("Expected ErrOllamaModelNotFound, got %v", err)
+	}
+}
+
+func TestOllamaClientPullModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantErr   error
+		wantCount int
+	}{
+		{
+			name:      "success",
+			body:      "{\"status\":\"pulling manifest\"}\n{\"status\":\"downloading\",\"digest\":\"sha256:abc\",\"total\":100,\"completed\":50}\n{\"status\":\"success\"}\n",
+			wantCount: 3,
+		},
+		{
+			name:      "error chunk",
+			body:      "{\"status\":\"pulling manifest\"}\n{\"error\":\"pull model manifest: file does not exist\"}\n",
+			wantErr:   ErrProviderBadResponse,
+			wantCount: 1,
+		},
+		{
+			name:      "stream ends early",
+			body:      "{\"status\":\"pulling manifest\"}\n",
+			wantErr:   ErrProviderBadResponse,
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/pull" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewLLMClient(testOllamaSettings(server.URL))
+			var updates []OllamaPullProgress
+			err := client.PullModel(context.Background(), "llama3", func(progress OllamaPullProgress) error {
+				updates = append(updates, progress)
+				return nil
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if len(updates) != tt.wantCount {
+				t.Errorf("Expected %d progress updates, got %d", tt.wantCount, len(updates))
+			}
+		})
+	}
+}
+
+func TestMissingOllamaModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"models":[{"name":"llama3:latest","model":"llama3:latest"},{"name":"mistral:7b","model":"mistral:7b"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewLLMClient(testOllamaSettings(server.URL))
+	missing, err := MissingOllamaModels(context.Background(), client, []string{"llama3", "mistral:7b", "mistral", "qwen2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(missing) != 2 || missing[0] != "mistral" || missing[1] != "qwen2" {
+		t.Errorf("Expected [mistral qwen2] missing, got %v", missing)
+	}
+}
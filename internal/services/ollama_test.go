@@ -1,7 +1,14 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"student-api/internal/models"
 	"testing"
@@ -80,3 +87,352 @@ func (m *MockOllamaService) GenerateSummary(student models.Student) (string, err
 
 	return cleanSummaryResponse(rawResponse), nil
 }
+
+func TestOllamaServiceStreamSummary(t *testing.T) {
+	var received OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"response":"Here is a brief summary:\n","done":false}`)
+		fmt.Fprintln(w, `{"response":"John is ","done":false}`)
+		fmt.Fprintln(w, `{"response":"motivated.","done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3","response":"","done":true,"prompt_eval_count":26,"eval_count":8}`)
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	student := models.Student{ID: 1, Name: "John", Age: 20, Email: "john@example.com"}
+
+	var tokens []string
+	summary, err := service.StreamSummary(context.Background(), student, SummaryOptions{}, func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !received.Stream {
+		t.Error("Expected stream to be requested from Ollama")
+	}
+	if len(tokens) != 3 {
+		t.Errorf("Expected 3 tokens, got %d", len(tokens))
+	}
+	if summary.Text != "John is motivated." {
+		t.Errorf("Expected cleaned summary, got '%s'", summary.Text)
+	}
+	if summary.Model != "llama3" || summary.PromptTokens != 26 || summary.CompletionTokens != 8 {
+		t.Errorf("Expected model and token counts from the final chunk, got %+v", summary)
+	}
+}
+
+func TestOllamaServiceStreamSummaryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "Upstream error status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "Error chunk",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"error":"model not found"}`)
+			},
+		},
+		{
+			name: "Stream ends early",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"response":"John","done":false}`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+			_, err := service.StreamSummary(context.Background(), models.Student{ID: 1}, SummaryOptions{}, func(string) error { return nil })
+			if err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestOllamaServiceStreamSummaryCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"response":"John","done":false}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	_, err := service.StreamSummary(ctx, models.Student{ID: 1}, SummaryOptions{}, func(string) error {
+		cancel()
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error after cancellation")
+	}
+}
+
+func TestOllamaServiceGenerateSummaryStyle(t *testing.T) {
+	var received OllamaRequest
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, structuredOllamaResponse("John is motivated."))
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	student := models.Student{ID: 1, Name: "John", Age: 20, Email: "john@example.com"}
+
+	summary, err := service.GenerateSummary(context.Background(), student, SummaryOptions{Style: "recommendation"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected, _ := LookupPrompt("recommendation")
+	if !strings.Contains(received.Prompt, "recommendation letter") {
+		t.Errorf("Expected recommendation prompt, got %q", received.Prompt)
+	}
+	if summary.Style != "recommendation" || summary.PromptVersion != expected.Version {
+		t.Errorf("Expected style and prompt version to be recorded, got %s %s", summary.Style, summary.PromptVersion)
+	}
+
+	if _, err := service.GenerateSummary(context.Background(), student, SummaryOptions{Style: "haiku"}); !errors.Is(err, ErrUnknownPromptStyle) {
+		t.Errorf("Expected ErrUnknownPromptStyle, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected unknown style to be rejected before calling Ollama, got %d calls", calls)
+	}
+}
+
+func TestOllamaServiceModelAndOptions(t *testing.T) {
+	var received map[string]interface{}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, structuredOllamaResponse("John is motivated."))
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	service.AllowedModels = allowedModels("llama3", []string{"mistral", "llama3"})
+	service.System = "You are an academic advisor."
+	service.KeepAlive = "10m"
+
+	temperature, seed := 0.2, 7
+	opts := SummaryOptions{
+		Model: "mistral",
+		Generation: models.GenerationOptions{
+			Temperature: &temperature,
+			Seed:        &seed,
+			Stop:        []string{"\n\n"},
+		},
+	}
+	summary, err := service.GenerateSummary(context.Background(), models.Student{ID: 1, Name: "John"}, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received["model"] != "mistral" || received["system"] != "You are an academic advisor." || received["keep_alive"] != "10m" {
+		t.Errorf("Unexpected request: %v", received)
+	}
+	options, _ := received["options"].(map[string]interface{})
+	if options["temperature"] != 0.2 || options["seed"] != float64(7) || options["num_predict"] != nil {
+		t.Errorf("Expected only the set options to be sent, got %v", options)
+	}
+	if summary.Model != "mistral" || summary.Options == nil || *summary.Options.Seed != 7 {
+		t.Errorf("Expected model and options to be recorded, got %+v", summary)
+	}
+
+	if _, err := service.GenerateSummary(context.Background(), models.Student{ID: 1}, SummaryOptions{Model: "gpt-4"}); !errors.Is(err, ErrModelNotAllowed) {
+		t.Errorf("Expected ErrModelNotAllowed, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected disallowed model to be rejected before calling Ollama, got %d calls", calls)
+	}
+	if len(service.Models()) != 2 {
+		t.Errorf("Expected duplicate models to be dropped, got %v", service.Models())
+	}
+}
+
+// structuredOllamaResponse returns a non-streaming Ollama response whose text
+// is a valid structured summary.
+func structuredOllamaResponse(summary string) string {
+	output, _ := json.Marshal(structuredSummary{
+		Summary:   summary,
+		Strengths: []string{"Motivated"},
+		NextSteps: []string{"Join a study group"},
+	})
+	response, _ := json.Marshal(OllamaResponse{Response: string(output), Done: true})
+	return string(response)
+}
+
+func TestOllamaServiceStructuredSummary(t *testing.T) {
+	responses := []string{
+		`Here is a professional summary: John is motivated.`,
+		`{"summary":"John is motivated.","strengths":[]}`,
+		`{"summary":" John is motivated. ","strengths":["Motivated"],"next_steps":["Join a study group"]}`,
+	}
+	var requests []OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OllamaRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		json.NewEncoder(w).Encode(OllamaResponse{Model: "llama3", Response: responses[len(requests)-1], Done: true})
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	summary, err := service.GenerateSummary(context.Background(), models.Student{ID: 1, Name: "John"}, SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Errorf("Expected invalid output to be retried, got %d requests", len(requests))
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(requests[0].Format, &schema); err != nil || schema["type"] != "object" {
+		t.Errorf("Expected the summary schema as format, got %s", requests[0].Format)
+	}
+	if !strings.Contains(requests[0].Prompt, `"next_steps"`) {
+		t.Error("Expected the prompt to describe the structured fields")
+	}
+	if summary.Text != "John is motivated." || len(summary.Strengths) != 1 || summary.NextSteps[0] != "Join a study group" {
+		t.Errorf("Unexpected structured summary: %+v", summary)
+	}
+}
+
+func TestOllamaServiceStructuredSummaryRetriesExhausted(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		io.Copy(io.Discard, r.Body)
+		fmt.Fprint(w, `{"response":"{\"summary\":\"John\",\"extra\":true}","done":true}`)
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	service.OutputRetries = 1
+	_, err := service.GenerateSummary(context.Background(), models.Student{ID: 1}, SummaryOptions{})
+	if !errors.Is(err, ErrProviderBadResponse) {
+		t.Errorf("Expected ErrProviderBadResponse, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestParseStructuredSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		expectError bool
+	}{
+		{name: "Valid", response: `{"summary":"A","strengths":["B"],"next_steps":["C"]}`},
+		{name: "Not JSON", response: `A is a student.`, expectError: true},
+		{name: "Missing summary", response: `{"strengths":["B"],"next_steps":["C"]}`, expectError: true},
+		{name: "Empty strengths", response: `{"summary":"A","strengths":[],"next_steps":["C"]}`, expectError: true},
+		{name: "Blank next step", response: `{"summary":"A","strengths":["B"],"next_steps":[" "]}`, expectError: true},
+		{name: "Unknown field", response: `{"summary":"A","strengths":["B"],"next_steps":["C"],"grade":"A"}`, expectError: true},
+		{name: "Trailing data", response: `{"summary":"A","strengths":["B"],"next_steps":["C"]} {}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseStructuredSummary(tt.response)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOllamaServiceStreamCohortReport(t *testing.T) {
+	var received OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"response":"## Overview\n\n","done":false}`)
+		fmt.Fprintln(w, `{"response":"A steady cohort.","done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3","response":"","done":true,"prompt_eval_count":120,"eval_count":9}`)
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	cohort := models.Cohort{Title: "Seniors", Stats: models.StudentStats{Count: 12}}
+
+	report, err := service.StreamCohortReport(context.Background(), cohort, SummaryOptions{}, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !received.Stream || !strings.Contains(received.Prompt, `"Seniors"`) || !strings.Contains(received.Prompt, "Students: 12") {
+		t.Errorf("Expected a streamed cohort prompt, got %+v", received)
+	}
+	if report.Narrative != "## Overview\n\nA steady cohort." {
+		t.Errorf("Expected the Markdown line breaks to be kept, got %q", report.Narrative)
+	}
+	cohortPrompt, _ := lookupTaskPrompt(cohortTaskPrompt)
+	if report.Model != "llama3" || report.PromptVersion != cohortPrompt.Version || report.PromptTokens != 120 || report.CompletionTokens != 9 || report.StudentCount != 12 {
+		t.Errorf("Unexpected report metadata %+v", report)
+	}
+}
+
+func TestOllamaServiceTranslateStudentQuery(t *testing.T) {
+	responses := []string{
+		`{"min_age":21,"status":["expelled"]}`,
+		`{"min_age":21,"email_domain":"gmail.com"}`,
+	}
+	var requests []OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OllamaRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		json.NewEncoder(w).Encode(OllamaResponse{Model: "llama3", Response: responses[len(requests)-1], Done: true})
+	}))
+	defer server.Close()
+
+	service := NewOllamaService(NewLLMClient(testOllamaSettings(server.URL)))
+	query, err := service.TranslateStudentQuery(context.Background(), " students over 20 with gmail addresses ", SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected the invalid filter to be retried, got %d requests", len(requests))
+	}
+	if requests[0].Stream || !strings.Contains(string(requests[0].Format), `"email_domain"`) {
+		t.Errorf("Expected a non-streamed request constrained by the filter schema, got %+v", requests[0])
+	}
+	if !strings.Contains(requests[0].Prompt, `"students over 20 with gmail addresses"`) {
+		t.Errorf("Expected the quoted question in the prompt, got %q", requests[0].Prompt)
+	}
+	queryPrompt, _ := lookupTaskPrompt(queryTaskPrompt)
+	expected := StudentQuery{
+		Question:      "students over 20 with gmail addresses",
+		Filter:        StudentFilter{MinAge: 21, EmailDomain: "gmail.com"},
+		Model:         "llama3",
+		PromptVersion: queryPrompt.Version,
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, query)
+	}
+}
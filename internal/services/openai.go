@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"student-api/internal/models"
+	"time"
+)
+
+const openAIChatPath = "/v1/chat/completions"
+
+// OpenAIService generates summaries through any server implementing the
+// OpenAI chat completions API, such as llama.cpp, vLLM or LM Studio. It shares
+// the retrying, circuit-breaking HTTP client used for Ollama.
+type OpenAIService struct {
+	Client *LLMClient
+	// AllowedModels lists the models callers may request, default first.
+	AllowedModels []string
+	System        string
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []openAIMessage      `json:"messages"`
+	Stream        bool                 `json:"stream"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	MaxTokens     *int                 `json:"max_tokens,omitempty"`
+	Seed          *int                 `json:"seed,omitempty"`
+	Stop          []string             `json:"stop,omitempty"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+		Delta   openAIMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewOpenAIService(client *LLMClient, model string) *OpenAIService {
+	return &OpenAIService{
+		Client:        client,
+		AllowedModels: []string{model},
+	}
+}
+
+func (s *OpenAIService) Models() []string {
+	return s.AllowedModels
+}
+
+func (s *OpenAIService) GenerateSummary(ctx context.Context, student models.Student, opts SummaryOptions) (models.Summary, error) {
+	chatReq, promptTemplate, err := s.chatRequest(ctx, student, opts, false)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	chatResp, err := s.complete(ctx, chatReq)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	return newOpenAISummary(student, promptTemplate, opts, chatReq, chatResp, cleanSummaryResponse(chatResp.Choices[0].Message.Content)), nil
+}
+
+// StreamSummary streams a chat completion for the student's summary.
+func (s *OpenAIService) StreamSummary(ctx context.Context, student models.Student, opts SummaryOptions, onToken func(token string) error) (models.Summary, error) {
+	chatReq, promptTemplate, err := s.chatRequest(ctx, student, opts, true)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	text, final, err := s.streamChat(ctx, chatReq, onToken)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	return newOpenAISummary(student, promptTemplate, opts, chatReq, final, cleanSummaryResponse(text)), nil
+}
+
+// StreamCohortReport streams a chat completion for a cohort report, keeping
+// the line breaks of its Markdown.
+func (s *OpenAIService) StreamCohortReport(ctx context.Context, cohort models.Cohort, opts SummaryOptions, onToken func(token string) error) (models.CohortReport, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+	prompt, promptTemplate, err := buildCohortPrompt(cohort)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+	chatReq := s.newChatRequest(model, prompt, opts, true)
+
+	text, final, err := s.streamChat(ctx, chatReq, onToken)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+
+	if final.Model != "" {
+		model = final.Model
+	}
+	report := newCohortReport(cohort, model, promptTemplate.Version, opts.Generation, text)
+	if final.Usage != nil {
+		report.PromptTokens = final.Usage.PromptTokens
+		report.CompletionTokens = final.Usage.CompletionTokens
+	}
+	return report, nil
+}
+
+// TranslateStudentQuery asks for a student filter as a JSON object. The
+// chat completions API has no portable way to constrain the output, so a
+// filter that does not validate is rejected rather than retried.
+func (s *OpenAIService) TranslateStudentQuery(ctx context.Context, question string, opts SummaryOptions) (StudentQuery, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return StudentQuery{}, err
+	}
+	prompt, promptTemplate, err := buildStudentQueryPrompt(question)
+	if err != nil {
+		return StudentQuery{}, err
+	}
+	chatReq := s.newChatRequest(model, prompt, opts, false)
+
+	chatResp, err := s.complete(ctx, chatReq)
+	if err != nil {
+		return StudentQuery{}, err
+	}
+
+	filter, err := parseStudentQueryFilter(chatResp.Choices[0].Message.Content)
+	if err != nil {
+		return StudentQuery{}, fmt.Errorf("%w: invalid student filter: %v", ErrProviderBadResponse, err)
+	}
+	if chatResp.Model != "" {
+		model = chatResp.Model
+	}
+	return newStudentQuery(question, filter, model, promptTemplate.Version), nil
+}
+
+// complete sends a non-streamed chat completion and returns the response,
+// which has at least one choice.
+func (s *OpenAIService) complete(ctx context.Context, chatReq openAIChatRequest) (openAIChatResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.Client.settings.Timeout)
+	defer cancel()
+
+	resp, err := s.Client.post(ctx, openAIChatPath, chatReq)
+	if err != nil {
+		return openAIChatResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return openAIChatResponse{}, s.Client.classify(ctx, err)
+	}
+	if chatResp.Error != nil {
+		return openAIChatResponse{}, fmt.Errorf("%w: %s", ErrProviderBadResponse, chatResp.Error.Message)
+	}
+	if len(chatResp.Choices) == 0 {
+		return openAIChatResponse{}, fmt.Errorf("%w: no choices returned", ErrProviderBadResponse)
+	}
+	return chatResp, nil
+}
+
+// streamChat reads the server-sent events of a streamed chat completion,
+// calling onToken for every content delta until the server sends [DONE]. It
+// returns the full text with the model and usage the server reported. Like
+// LLMClient.Stream, it fails with ErrProviderTimeout when no line arrives
+// within the client's timeout.
+func (s *OpenAIService) streamChat(ctx context.Context, chatReq openAIChatRequest, onToken func(token string) error) (string, openAIChatResponse, error) {
+	ctx, idle := newStreamIdleTimer(ctx, s.Client.settings.Timeout)
+	defer idle.stop()
+
+	resp, err := s.Client.post(ctx, openAIChatPath, chatReq)
+	if err != nil {
+		return "", openAIChatResponse{}, idle.check(err)
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	var final openAIChatResponse
+	reader := bufio.NewReader(resp.Body)
+	for {
+		idle.reset()
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return "", openAIChatResponse{}, fmt.Errorf("%w: stream ended before completion", ErrProviderBadResponse)
+			}
+			return "", openAIChatResponse{}, idle.check(s.Client.classify(ctx, err))
+		}
+
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return full.String(), final, nil
+		}
+
+		var chunk openAIChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", openAIChatResponse{}, fmt.Errorf("%w: %v", ErrProviderBadResponse, err)
+		}
+		if chunk.Error != nil {
+			return "", openAIChatResponse{}, fmt.Errorf("%w: %s", ErrProviderBadResponse, chunk.Error.Message)
+		}
+		if chunk.Model != "" {
+			final.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			final.Usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(choice.Delta.Content)
+			if err := onToken(choice.Delta.Content); err != nil {
+				return "", openAIChatResponse{}, err
+			}
+		}
+	}
+}
+
+func (s *OpenAIService) chatRequest(ctx context.Context, student models.Student, opts SummaryOptions, stream bool) (openAIChatRequest, *PromptTemplate, error) {
+	model, err := ResolveModel(s.AllowedModels, opts.Model)
+	if err != nil {
+		return openAIChatRequest{}, nil, err
+	}
+	prompt, promptTemplate, err := buildSummaryPrompt(ctx, student, opts.Style)
+	if err != nil {
+		return openAIChatRequest{}, nil, err
+	}
+
+	return s.newChatRequest(model, prompt, opts, stream), promptTemplate, nil
+}
+
+func (s *OpenAIService) newChatRequest(model, prompt string, opts SummaryOptions, stream bool) openAIChatRequest {
+	var messages []openAIMessage
+	if s.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: s.System})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: prompt})
+
+	request := openAIChatRequest{
+		Model:       model,
+		Messages:    messages,
+		Stream:      stream,
+		Temperature: opts.Generation.Temperature,
+		MaxTokens:   opts.Generation.NumPredict,
+		Seed:        opts.Generation.Seed,
+		Stop:        opts.Generation.Stop,
+	}
+	if stream {
+		request.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	return request
+}
+
+func newOpenAISummary(student models.Student, promptTemplate *PromptTemplate, opts SummaryOptions, req openAIChatRequest, resp openAIChatResponse, text string) models.Summary {
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	summary := models.Summary{
+		StudentID:     student.ID,
+		Text:          text,
+		Model:         model,
+		Style:         promptTemplate.Style,
+		PromptVersion: promptTemplate.Version,
+		Options:       generationOptions(opts.Generation),
+		GeneratedAt:   time.Now().UTC(),
+	}
+	if resp.Usage != nil {
+		summary.PromptTokens = resp.Usage.PromptTokens
+		summary.CompletionTokens = resp.Usage.CompletionTokens
+	}
+	return summary
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"student-api/internal/models"
+	"testing"
+)
+
+func TestOpenAIServiceGenerateSummary(t *testing.T) {
+	var received openAIChatRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Expected chat completions path, got %s", r.URL.Path)
+		}
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, `{"model":"qwen2","choices":[{"message":{"role":"assistant","content":"Summary:\nJohn is motivated."}}],"usage":{"prompt_tokens":30,"completion_tokens":5}}`)
+	}))
+	defer server.Close()
+
+	settings := testOllamaSettings(server.URL)
+	settings.APIKey = "secret"
+	service := NewOpenAIService(NewLLMClient(settings), "qwen2")
+	student := models.Student{ID: 1, Name: "John", Age: 20, Email: "john@example.com"}
+
+	summary, err := service.GenerateSummary(context.Background(), student, SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if authorization != "Bearer secret" {
+		t.Errorf("Expected bearer token, got %q", authorization)
+	}
+	if received.Model != "qwen2" || received.Stream || len(received.Messages) != 1 || received.Messages[0].Role != "user" {
+		t.Errorf("Unexpected chat request: %+v", received)
+	}
+	if summary.Text != "John is motivated." {
+		t.Errorf("Expected cleaned summary, got '%s'", summary.Text)
+	}
+	if summary.Model != "qwen2" || summary.PromptTokens != 30 || summary.CompletionTokens != 5 {
+		t.Errorf("Expected model and usage from the response, got %+v", summary)
+	}
+}
+
+func TestOpenAIServiceGenerateSummaryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected error
+	}{
+		{
+			name: "Server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expected: ErrProviderUnavailable,
+		},
+		{
+			name: "Rejected request",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			expected: ErrProviderBadResponse,
+		},
+		{
+			name: "No choices",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"choices":[]}`)
+			},
+			expected: ErrProviderBadResponse,
+		},
+		{
+			name: "Error object",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"error":{"message":"model not loaded"}}`)
+			},
+			expected: ErrProviderBadResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+			_, err := service.GenerateSummary(context.Background(), models.Student{ID: 1}, SummaryOptions{})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestOpenAIServiceStreamSummary(t *testing.T) {
+	var received openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"model\":\"qwen2\",\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"John is \"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"motivated.\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":30,\"completion_tokens\":4}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+	student := models.Student{ID: 1, Name: "John", Age: 20, Email: "john@example.com"}
+
+	var tokens []string
+	summary, err := service.StreamSummary(context.Background(), student, SummaryOptions{}, func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !received.Stream || received.StreamOptions == nil || !received.StreamOptions.IncludeUsage {
+		t.Errorf("Expected a streamed request with usage, got %+v", received)
+	}
+	if len(tokens) != 2 {
+		t.Errorf("Expected 2 tokens, got %d", len(tokens))
+	}
+	if summary.Text != "John is motivated." {
+		t.Errorf("Expected joined summary, got '%s'", summary.Text)
+	}
+	if summary.Model != "qwen2" || summary.PromptTokens != 30 || summary.CompletionTokens != 4 {
+		t.Errorf("Expected model and usage from the stream, got %+v", summary)
+	}
+}
+
+func TestOpenAIServiceStreamEndsEarly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"John\"}}]}\n\n")
+	}))
+	defer server.Close()
+
+	service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+	_, err := service.StreamSummary(context.Background(), models.Student{ID: 1}, SummaryOptions{}, func(string) error { return nil })
+	if !errors.Is(err, ErrProviderBadResponse) {
+		t.Errorf("Expected ErrProviderBadResponse, got %v", err)
+	}
+}
+
+func TestOpenAIServiceSystemPromptAndOptions(t *testing.T) {
+	var received openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"John is motivated."}}]}`)
+	}))
+	defer server.Close()
+
+	service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+	service.System = "You are an academic advisor."
+
+	numPredict := 64
+	summary, err := service.GenerateSummary(context.Background(), models.Student{ID: 1, Name: "John"}, SummaryOptions{
+		Generation: models.GenerationOptions{NumPredict: &numPredict, Stop: []string{"END"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(received.Messages) != 2 || received.Messages[0].Role != "system" {
+		t.Errorf("Expected system message before the prompt, got %+v", received.Messages)
+	}
+	if received.MaxTokens == nil || *received.MaxTokens != 64 || len(received.Stop) != 1 || received.Temperature != nil {
+		t.Errorf("Expected num_predict mapped to max_tokens, got %+v", received)
+	}
+	if summary.Model != "qwen2" || summary.Options == nil || *summary.Options.NumPredict != 64 {
+		t.Errorf("Expected model and options to be recorded, got %+v", summary)
+	}
+}
+
+func TestOpenAIServiceStreamCohortReport(t *testing.T) {
+	var received openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"model\":\"qwen2\",\"choices\":[{\"delta\":{\"content\":\"## Overview\\n\\n\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"A steady cohort.\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":110,\"completion_tokens\":7}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+	cohort := models.Cohort{Title: "Seniors", Stats: models.StudentStats{Count: 12}}
+
+	report, err := service.StreamCohortReport(context.Background(), cohort, SummaryOptions{}, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !received.Stream || len(received.Messages) != 1 {
+		t.Errorf("Expected a streamed request with one message, got %+v", received)
+	}
+	if report.Narrative != "## Overview\n\nA steady cohort." {
+		t.Errorf("Expected the Markdown line breaks to be kept, got %q", report.Narrative)
+	}
+	if report.Model != "qwen2" || report.PromptTokens != 110 || report.CompletionTokens != 7 {
+		t.Errorf("Expected model and usage from the stream, got %+v", report)
+	}
+}
+
+func TestOpenAIServiceTranslateStudentQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    StudentFilter
+		expectError bool
+	}{
+		{
+			name:     "Valid filter",
+			content:  "```json\n{\"max_age\":17,\"status\":[\"applied\"]}\n```",
+			expected: StudentFilter{MaxAge: 17, Status: []models.StudentStatus{models.StudentApplied}},
+		},
+		{name: "Invalid filter", content: `{"grade":"A"}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received openAIChatRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&received)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"model":   "qwen2",
+					"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": tt.content}}},
+				})
+			}))
+			defer server.Close()
+
+			service := NewOpenAIService(NewLLMClient(testOllamaSettings(server.URL)), "qwen2")
+			query, err := service.TranslateStudentQuery(context.Background(), "applicants under 18", SummaryOptions{})
+
+			if received.Stream || len(received.Messages) != 1 || !strings.Contains(received.Messages[0].Content, `"applicants under 18"`) {
+				t.Errorf("Expected a non-streamed request with the quoted question, got %+v", received)
+			}
+			if tt.expectError {
+				if !errors.Is(err, ErrProviderBadResponse) {
+					t.Errorf("Expected ErrProviderBadResponse, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(query.Filter, tt.expected) || query.Model != "qwen2" {
+				t.Errorf("Unexpected query %+v", query)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"student-api/internal/models"
+	"sync"
+	"text/template"
+)
+
+const DefaultPromptStyle = "professional"
+
+var ErrUnknownPromptStyle = errors.New("unknown summary style")
+
+//go:embed prompts/*.tmpl prompts/tasks/*.tmpl
+var builtinPrompts embed.FS
+
+// Task prompts are the prompts for work other than student summaries. They
+// live in the tasks directory next to the summary styles.
+const (
+	cohortTaskPrompt = "cohort"
+	queryTaskPrompt  = "query"
+)
+
+// promptSample and promptSampleTranscript are rendered through every
+// template at load time so broken templates are reported at startup rather
+// than on the first request.
+var (
+	promptSample           = models.Student{ID: 1, Name: "Sample Student", Age: 20, Email: "sample@example.com"}
+	promptSampleAverage    = 87.5
+	promptSampleGPA        = 3.0
+	promptSampleTranscript = models.Transcript{
+		StudentID: 1,
+		Courses: []models.CourseResult{
+			{CourseID: 1, Code: "CS101", Title: "Intro to Programming", Status: models.EnrollmentActive, Average: &promptSampleAverage, Letter: "B"},
+			{CourseID: 2, Code: "MATH101", Title: "Calculus I", Status: models.EnrollmentActive},
+		},
+		GPA:    &promptSampleGPA,
+		MaxGPA: 4,
+	}
+
+	// taskPromptSamples holds the sample rendered through each task prompt,
+	// and so also lists the task prompts a library must have.
+	taskPromptSamples = map[string]interface{}{
+		cohortTaskPrompt: cohortPromptData{
+			Title:        `"Sample cohort"`,
+			Count:        2,
+			Statuses:     []models.StatsBucket{{Value: "enrolled", Count: 2}},
+			Ages:         []models.AgeBucket{{Min: 20, Max: 24, Count: 2}},
+			EmailDomains: []models.StatsBucket{{Value: `"example.com"`, Count: 2}},
+			GPA:          "3.00 out of 4.00 across 2 students with grades",
+			Attendance:   "90.0% across 2 students with attendance records",
+		},
+		queryTaskPrompt: studentQueryPromptData{
+			Question: `"students over 20"`,
+			Statuses: "applied, enrolled",
+		},
+	}
+)
+
+// PromptTemplate is a named summary or task prompt. Its version is derived
+// from the template source, so editing a template changes the version
+// recorded with new summaries and reports and invalidates cached summaries.
+type PromptTemplate struct {
+	Style    string
+	Version  string
+	template *template.Template
+}
+
+// PromptLibrary holds the prompt templates available for summaries, keyed by
+// style name, and the task prompts, keyed by task.
+type PromptLibrary struct {
+	templates map[string]*PromptTemplate
+	tasks     map[string]*PromptTemplate
+}
+
+var (
+	promptLibraryMutex sync.RWMutex
+	promptLibrary      = mustLoadBuiltinPrompts()
+)
+
+// LoadPromptLibrary loads the built-in templates and then every *.tmpl file
+// in dir, which may add styles or override built-in ones. The file name
+// without its extension is the style name. Files in dir's tasks directory
+// override the built-in task prompts of the same name. An empty dir loads
+// only the built-in templates.
+func LoadPromptLibrary(dir string) (*PromptLibrary, error) {
+	library := &PromptLibrary{templates: make(map[string]*PromptTemplate), tasks: make(map[string]*PromptTemplate)}
+	if err := library.loadFS(builtinPrompts, "prompts"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := library.loadFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := library.templates[DefaultPromptStyle]; !ok {
+		return nil, fmt.Errorf("missing default prompt style %q", DefaultPromptStyle)
+	}
+	for task := range taskPromptSamples {
+		if _, ok := library.tasks[task]; !ok {
+			return nil, fmt.Errorf("missing task prompt %q", task)
+		}
+	}
+	return library, nil
+}
+
+func mustLoadBuiltinPrompts() *PromptLibrary {
+	library, err := LoadPromptLibrary("")
+	if err != nil {
+		panic(err)
+	}
+	return library
+}
+
+func (l *PromptLibrary) loadFS(fsys fs.FS, dir string) error {
+	err := loadPromptFiles(fsys, dir, func(style, source string) error {
+		prompt, err := parsePromptTemplate(style, source, newPromptData(promptSample, promptSampleTranscript))
+		if err != nil {
+			return err
+		}
+		l.templates[style] = prompt
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return loadPromptFiles(fsys, filepath.Join(dir, "tasks"), func(task, source string) error {
+		sample, ok := taskPromptSamples[task]
+		if !ok {
+			return fmt.Errorf("unknown task prompt %q", task)
+		}
+		prompt, err := parsePromptTemplate(task, source, sample)
+		if err != nil {
+			return err
+		}
+		l.tasks[task] = prompt
+		return nil
+	})
+}
+
+// loadPromptFiles calls load with the name and source of every *.tmpl file
+// in dir.
+func loadPromptFiles(fsys fs.FS, dir string, load func(name, source string) error) error {
+	paths, err := fs.Glob(fsys, filepath.ToSlash(filepath.Join(dir, "*.tmpl")))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		source, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		if err := load(strings.TrimSuffix(filepath.Base(path), ".tmpl"), string(source)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePromptTemplate parses a prompt, along with the guard it may include,
+// and renders it over sample, so that broken templates are reported when
+// they are loaded.
+func parsePromptTemplate(name, source string, sample interface{}) (*PromptTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(promptGuardSource)
+	if err == nil {
+		tmpl, err = tmpl.Parse(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("prompt %q: %w", name, err)
+	}
+
+	// The guard is part of what the model sees, so it is part of the version.
+	hash := sha256.Sum256([]byte(promptGuardSource + source))
+	prompt := &PromptTemplate{
+		Style:    name,
+		Version:  name + "@" + hex.EncodeToString(hash[:4]),
+		template: tmpl,
+	}
+
+	rendered, err := prompt.execute(sample)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %q: %w", name, err)
+	}
+	if rendered == "" {
+		return nil, fmt.Errorf("prompt %q renders an empty prompt", name)
+	}
+	return prompt, nil
+}
+
+// Render executes the template over a sanitized, redacted view of student
+// and their transcript.
+func (p *PromptTemplate) Render(student models.Student, transcript models.Transcript) (string, error) {
+	return p.execute(newPromptData(student, transcript))
+}
+
+func (p *PromptTemplate) execute(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Lookup returns the template for style, or the default template when style
+// is empty.
+func (l *PromptLibrary) Lookup(style string) (*PromptTemplate, error) {
+	if style == "" {
+		style = DefaultPromptStyle
+	}
+	prompt, ok := l.templates[style]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPromptStyle, style)
+	}
+	return prompt, nil
+}
+
+func (l *PromptLibrary) Styles() []string {
+	styles := make([]string, 0, len(l.templates))
+	for style := range l.templates {
+		styles = append(styles, style)
+	}
+	sort.Strings(styles)
+	return styles
+}
+
+// SetPromptLibrary replaces the templates used by the summary providers.
+func SetPromptLibrary(library *PromptLibrary) {
+	promptLibraryMutex.Lock()
+	defer promptLibraryMutex.Unlock()
+	promptLibrary = library
+}
+
+// LookupPrompt returns the active template for style.
+func LookupPrompt(style string) (*PromptTemplate, error) {
+	promptLibraryMutex.RLock()
+	defer promptLibraryMutex.RUnlock()
+	return promptLibrary.Lookup(style)
+}
+
+// lookupTaskPrompt returns the active prompt for task.
+func lookupTaskPrompt(task string) (*PromptTemplate, error) {
+	promptLibraryMutex.RLock()
+	defer promptLibraryMutex.RUnlock()
+	prompt, ok := promptLibrary.tasks[task]
+	if !ok {
+		return nil, fmt.Errorf("missing task prompt %q", task)
+	}
+	return prompt, nil
+}
+
+func PromptStyles() []string {
+	promptLibraryMutex.RLock()
+	defer promptLibraryMutex.RUnlock()
+	return promptLibrary.Styles()
+}
+
+// buildSummaryPrompt renders the prompt for student, with their current
+// transcript, in the requested style.
+func buildSummaryPrompt(ctx context.Context, student models.Student, style string) (string, *PromptTemplate, error) {
+	prompt, err := LookupPrompt(style)
+	if err != nil {
+		return "", nil, err
+	}
+	rendered, err := prompt.Render(student, studentTranscript(ctx, student.ID))
+	if err != nil {
+		return "", nil, err
+	}
+	return rendered, prompt, nil
+}
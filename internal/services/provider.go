@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"student-api/internal/models"
+)
+
+const (
+	ProviderOllama   = "ollama"
+	ProviderOpenAI   = "openai"
+	ProviderTemplate = "template"
+)
+
+// SummaryProvider is a backend that generates student summaries. HTTP-backed
+// providers report failures with the ErrProvider* errors so callers can map
+// them to status codes without knowing which provider is configured.
+type SummaryProvider interface {
+	// Models lists the models callers may request; the first is the default.
+	Models() []string
+	GenerateSummary(ctx context.Context, student models.Student, opts SummaryOptions) (models.Summary, error)
+	StreamSummary(ctx context.Context, student models.Student, opts SummaryOptions, onToken func(token string) error) (models.Summary, error)
+}
+
+// CohortReporter is a summary provider that can also write cohort reports.
+type CohortReporter interface {
+	// StreamCohortReport writes a Markdown narrative about cohort, calling
+	// onToken as it is generated. opts.Style does not apply to reports.
+	StreamCohortReport(ctx context.Context, cohort models.Cohort, opts SummaryOptions, onToken func(token string) error) (models.CohortReport, error)
+}
+
+// StudentQueryTranslator is a summary provider that can also translate
+// questions about students into filters.
+type StudentQueryTranslator interface {
+	// TranslateStudentQuery turns a question about students into a
+	// validated StudentFilter.
+	TranslateStudentQuery(ctx context.Context, question string, opts SummaryOptions) (StudentQuery, error)
+}
+
+var ErrModelNotAllowed = errors.New("model not allowed")
+
+// SummaryOptions are per-request settings for generating a summary.
+type SummaryOptions struct {
+	// Style names the prompt template to use; empty selects
+	// DefaultPromptStyle.
+	Style string
+	// Model must be one of the provider's Models; empty selects the default.
+	Model      string
+	Generation models.GenerationOptions
+}
+
+type ProviderSettings struct {
+	Provider string
+
+	// HTTP holds the timeout, retry and circuit breaker settings shared by
+	// the HTTP-backed providers, along with Ollama's base URL.
+	HTTP HTTPSettings
+
+	OllamaModel  string
+	OpenAIURL    string
+	OpenAIAPIKey string
+	OpenAIModel  string
+
+	// ExtraModels may be requested in addition to the provider's default
+	// model.
+	ExtraModels  []string
+	SystemPrompt string
+	KeepAlive    string
+	// OutputRetries bounds the retries for invalid structured output.
+	OutputRetries int
+}
+
+// NewSummaryProvider builds the summary provider selected by
+// settings.Provider, guarded by the summary output filter.
+func NewSummaryProvider(settings ProviderSettings) (SummaryProvider, error) {
+	provider, err := newUnguardedProvider(settings)
+	if err != nil {
+		return nil, err
+	}
+	return GuardSummaryProvider(provider), nil
+}
+
+func newUnguardedProvider(settings ProviderSettings) (SummaryProvider, error) {
+	switch settings.Provider {
+	case ProviderOllama:
+		service := NewOllamaService(NewLLMClient(settings.HTTP))
+		service.AllowedModels = allowedModels(settings.OllamaModel, settings.ExtraModels)
+		service.System = settings.SystemPrompt
+		service.KeepAlive = settings.KeepAlive
+		service.OutputRetries = settings.OutputRetries
+		return service, nil
+	case ProviderOpenAI:
+		httpSettings := settings.HTTP
+		httpSettings.BaseURL = settings.OpenAIURL
+		httpSettings.APIKey = settings.OpenAIAPIKey
+		service := NewOpenAIService(NewLLMClient(httpSettings), settings.OpenAIModel)
+		service.AllowedModels = allowedModels(settings.OpenAIModel, settings.ExtraModels)
+		service.System = settings.SystemPrompt
+		return service, nil
+	case ProviderTemplate:
+		return NewTemplateSummaryService(), nil
+	default:
+		return nil, fmt.Errorf("unknown summary provider %q", settings.Provider)
+	}
+}
+
+// allowedModels returns defaultModel followed by the extra models, without
+// duplicates.
+func allowedModels(defaultModel string, extra []string) []string {
+	allowed := []string{defaultModel}
+	for _, model := range extra {
+		if model != "" && !containsString(allowed, model) {
+			allowed = append(allowed, model)
+		}
+	}
+	return allowed
+}
+
+// ResolveModel returns the model to use for a request, which must be one of
+// allowed. An empty request selects the default.
+func ResolveModel(allowed []string, requested string) (string, error) {
+	if requested == "" {
+		return allowed[0], nil
+	}
+	if !containsString(allowed, requested) {
+		return "", fmt.Errorf("%w: %q", ErrModelNotAllowed, requested)
+	}
+	return requested, nil
+}
+
+// generationOptions returns opts for inclusion in a summary or request, or
+// nil when no option is set.
+func generationOptions(opts models.GenerationOptions) *models.GenerationOptions {
+	if opts.IsZero() {
+		return nil
+	}
+	return &opts
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
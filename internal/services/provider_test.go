@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"student-api/internal/models"
+	"testing"
+)
+
+func TestNewSummaryProvider(t *testing.T) {
+	tests := []struct {
+		provider      string
+		expectedModel string
+		expectError   bool
+	}{
+		{provider: ProviderOllama, expectedModel: SummaryModel},
+		{provider: ProviderOpenAI, expectedModel: "qwen2"},
+		{provider: ProviderTemplate, expectedModel: TemplateSummaryModel},
+		{provider: "gpt", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			provider, err := NewSummaryProvider(ProviderSettings{
+				Provider:    tt.provider,
+				HTTP:        testOllamaSettings("http://localhost:11434"),
+				OllamaModel: SummaryModel,
+				OpenAIURL:   "http://localhost:8000",
+				OpenAIModel: "qwen2",
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error for unknown provider")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if provider.Models()[0] != tt.expectedModel {
+				t.Errorf("Expected default model %s, got %v", tt.expectedModel, provider.Models())
+			}
+		})
+	}
+}
+
+func TestTemplateSummaryService(t *testing.T) {
+	service := NewTemplateSummaryService()
+	student := models.Student{ID: 7, Name: "Alice Johnson", Age: 23, Email: "alice@example.com"}
+
+	summary, err := service.GenerateSummary(context.Background(), student, SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "Alice Johnson is a 23-year-old student enrolled with student ID 7."
+	if summary.Text != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, summary.Text)
+	}
+	if summary.Model != TemplateSummaryModel || summary.StudentID != 7 {
+		t.Errorf("Unexpected summary metadata: %+v", summary)
+	}
+
+	var tokens []string
+	streamed, err := service.StreamSummary(context.Background(), student, SummaryOptions{}, func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(tokens, "") != expected || streamed.Text != expected {
+		t.Errorf("Expected streamed tokens to form the summary, got %q", tokens)
+	}
+}
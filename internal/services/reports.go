@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"student-api/internal/models"
+	"sync"
+	"time"
+)
+
+const DefaultCohortReportTitle = "Cohort report"
+
+var (
+	ErrCohortEmpty          = errors.New("no students match the cohort filter")
+	ErrCohortReportNotFound = errors.New("cohort report not found")
+)
+
+// reportMutex guards the cohort reports of every tenant.
+var reportMutex = sync.RWMutex{}
+
+// cohortReportStore holds one tenant's saved cohort reports.
+type cohortReportStore struct {
+	cohortReports      map[int]models.CohortReport
+	nextCohortReportID int
+}
+
+var cohortReportStores = newTenantStores(&reportMutex, func() *cohortReportStore {
+	return &cohortReportStore{cohortReports: make(map[int]models.CohortReport), nextCohortReportID: 1}
+}, nil)
+
+// cohortPromptData is the view of a cohort that the cohort task prompt
+// renders, with user-supplied values quoted and redacted fields left out.
+// Only aggregate figures are sent; no individual student's details reach the
+// model.
+type cohortPromptData struct {
+	Title          string
+	Count          int
+	Statuses       []models.StatsBucket
+	Ages           []models.AgeBucket
+	EmailDomains   []models.StatsBucket
+	CreatedPerWeek []models.StatsBucket
+	GPA            string
+	Attendance     string
+}
+
+// BuildCohort aggregates the students matching filter: their statistics,
+// broken down by status, and their average GPA and attendance rate. The
+// figures are taken in one pass under a single read lock of the student and
+// course stores, so they describe the same students.
+func BuildCohort(ctx context.Context, title string, filter StudentFilter) (models.Cohort, error) {
+	if title == "" {
+		title = DefaultCohortReportTitle
+	}
+	scale := currentGradeScale()
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	students, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Cohort{}, err
+	}
+	courseMutex.RLock()
+	defer courseMutex.RUnlock()
+	data, err := courseStores.get(ctx)
+	if err != nil {
+		return models.Cohort{}, err
+	}
+
+	enrollments := make(map[int][]models.Enrollment)
+	for _, enrollment := range data.enrollments {
+		enrollments[enrollment.StudentID] = append(enrollments[enrollment.StudentID], enrollment)
+	}
+	grades := make(map[int][]models.Grade)
+	for _, grade := range data.grades {
+		grades[grade.StudentID] = append(grades[grade.StudentID], grade)
+	}
+	attendance := make(map[int]*models.AttendanceSummary)
+	for _, record := range data.attendance {
+		summary, ok := attendance[record.StudentID]
+		if !ok {
+			summary = &models.AttendanceSummary{StudentID: record.StudentID}
+			attendance[record.StudentID] = summary
+		}
+		summary.Add(record)
+	}
+
+	cohort := models.Cohort{Title: title, MaxGPA: scale.MaxPoints()}
+	var gpaTotal, attendanceTotal float64
+	cohort.Stats = studentStats(students, filter, StatsOptions{GroupBy: []string{"status"}}, func(student models.Student) {
+		if transcript := buildTranscript(data.courses, scale, student.ID, enrollments[student.ID], grades[student.ID]); transcript.GPA != nil {
+			cohort.GradedStudents++
+			gpaTotal += *transcript.GPA
+		}
+		if summary, ok := attendance[student.ID]; ok && summary.Rate != nil {
+			cohort.TrackedStudents++
+			attendanceTotal += *summary.Rate
+		}
+	})
+	if cohort.Stats.Count == 0 {
+		return models.Cohort{}, ErrCohortEmpty
+	}
+
+	if cohort.GradedStudents > 0 {
+		average := roundTo(gpaTotal/float64(cohort.GradedStudents), 2)
+		cohort.AverageGPA = &average
+	}
+	if cohort.TrackedStudents > 0 {
+		average := roundTo(attendanceTotal/float64(cohort.TrackedStudents), 3)
+		cohort.AverageAttendance = &average
+	}
+	return cohort, nil
+}
+
+// buildCohortPrompt renders the prompt for cohort, leaving out the figures
+// derived from redacted prompt fields.
+func buildCohortPrompt(cohort models.Cohort) (string, *PromptTemplate, error) {
+	prompt, err := lookupTaskPrompt(cohortTaskPrompt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data := cohortPromptData{
+		Title:          quotePromptValue(cohort.Title),
+		Count:          cohort.Stats.Count,
+		Statuses:       cohort.Stats.Groups["status"],
+		Ages:           cohort.Stats.AgeHistogram,
+		CreatedPerWeek: cohort.Stats.CreatedPerWeek,
+	}
+	for _, domain := range cohort.Stats.EmailDomains {
+		data.EmailDomains = append(data.EmailDomains, models.StatsBucket{Value: quotePromptValue(domain.Value), Count: domain.Count})
+	}
+	if cohort.AverageGPA != nil {
+		data.GPA = fmt.Sprintf("%.2f out of %.2f across %d students with grades", *cohort.AverageGPA, cohort.MaxGPA, cohort.GradedStudents)
+	}
+	if cohort.AverageAttendance != nil {
+		data.Attendance = fmt.Sprintf("%.1f%% across %d students with attendance records", *cohort.AverageAttendance*100, cohort.TrackedStudents)
+	}
+
+	for _, field := range currentPromptSafeguards().RedactFields {
+		switch field {
+		case "age":
+			data.Ages = nil
+		case "email":
+			data.EmailDomains = nil
+		case "grades":
+			data.GPA = ""
+		}
+	}
+
+	rendered, err := prompt.execute(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return rendered, prompt, nil
+}
+
+// newCohortReport returns the report for cohort with the generated
+// narrative. Providers fill in the token counts they know.
+func newCohortReport(cohort models.Cohort, model, promptVersion string, opts models.GenerationOptions, narrative string) models.CohortReport {
+	return models.CohortReport{
+		Title:         cohort.Title,
+		StudentCount:  cohort.Stats.Count,
+		Stats:         cohort.Stats,
+		Narrative:     strings.TrimSpace(narrative),
+		Model:         model,
+		PromptVersion: promptVersion,
+		Options:       generationOptions(opts),
+		GeneratedAt:   time.Now().UTC(),
+	}
+}
+
+// SaveCohortReport stores a generated report with the filter that selected
+// its cohort and returns it with its assigned ID.
+func SaveCohortReport(ctx context.Context, filter StudentFilter, report models.CohortReport) (models.CohortReport, error) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	data, err := cohortReportStores.get(ctx)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+
+	report.Filter, _ = json.Marshal(filter)
+	report.ID = data.nextCohortReportID
+	data.nextCohortReportID++
+	data.cohortReports[report.ID] = report
+	return report, nil
+}
+
+func ListCohortReports(ctx context.Context) []models.CohortReport {
+	reportMutex.RLock()
+	defer reportMutex.RUnlock()
+	data, err := cohortReportStores.get(ctx)
+	if err != nil {
+		return []models.CohortReport{}
+	}
+
+	result := make([]models.CohortReport, 0, len(data.cohortReports))
+	for _, report := range data.cohortReports {
+		result = append(result, report)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func GetCohortReport(ctx context.Context, id int) (models.CohortReport, error) {
+	reportMutex.RLock()
+	defer reportMutex.RUnlock()
+	data, err := cohortReportStores.get(ctx)
+	if err != nil {
+		return models.CohortReport{}, err
+	}
+
+	report, exists := data.cohortReports[id]
+	if !exists {
+		return models.CohortReport{}, ErrCohortReportNotFound
+	}
+	return report, nil
+}
+
+func DeleteCohortReport(ctx context.Context, id int) error {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	data, err := cohortReportStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := data.cohortReports[id]; !exists {
+		return ErrCohortReportNotFound
+	}
+	delete(data.cohortReports, id)
+	return nil
+}
+
+// CohortReportMarkdown renders report as a Markdown document: the title, the
+// generated narrative and an appendix with the cohort's statistics.
+func CohortReportMarkdown(report models.CohortReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", report.Title)
+	fmt.Fprintf(&b, "_Generated %s by %s for %d students._\n\n", report.GeneratedAt.Format(time.RFC3339), report.Model, report.StudentCount)
+	b.WriteString(report.Narrative)
+	b.WriteString("\n\n---\n\n## Cohort statistics\n\n")
+	fmt.Fprintf(&b, "Filter: `%s`\n", report.Filter)
+
+	writeBuckets := func(heading string, buckets []models.StatsBucket) {
+		if len(buckets) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", heading)
+		for _, bucket := range buckets {
+			value := bucket.Value
+			if value == "" {
+				value = "none"
+			}
+			fmt.Fprintf(&b, "- %s: %d\n", value, bucket.Count)
+		}
+	}
+	writeBuckets("Students by status", report.Stats.Groups["status"])
+	if len(report.Stats.AgeHistogram) > 0 {
+		b.WriteString("\n### Students by age\n\n")
+		for _, bucket := range report.Stats.AgeHistogram {
+			fmt.Fprintf(&b, "- %d to %d: %d\n", bucket.Min, bucket.Max, bucket.Count)
+		}
+	}
+	writeBuckets("Students by email domain", report.Stats.EmailDomains)
+	writeBuckets("New students per week", report.Stats.CreatedPerWeek)
+	return b.String()
+}
+
+// CohortReportHTML renders the Markdown document of report as a standalone
+// HTML page.
+func CohortReportHTML(report models.CohortReport) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n</head>\n<body>\n", html.EscapeString(report.Title))
+	b.WriteString(renderMarkdownHTML(CohortReportMarkdown(report)))
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func ResetCohortReports() {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	cohortReportStores.reset()
+}
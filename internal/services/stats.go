@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"student-api/internal/models"
+)
+
+// DefaultAgeBucketWidth is the width in years of the age histogram buckets
+// when StatsOptions leaves it unset.
+const DefaultAgeBucketWidth = 5
+
+var ErrInvalidGroupField = errors.New("invalid group_by field")
+
+// StatsOptions shapes GetStudentStats. GroupBy names the fields to break the
+// students down by: scalar student fields such as status, or a defined
+// custom field as custom_fields.<key>.
+type StatsOptions struct {
+	AgeBucketWidth int
+	GroupBy        []string
+}
+
+// GetStudentStats aggregates the students matching filter in a single pass
+// over the store. Creations are counted per UTC day and per ISO week.
+func GetStudentStats(ctx context.Context, filter StudentFilter, options StatsOptions) (models.StudentStats, error) {
+	for _, field := range options.GroupBy {
+		if err := validateGroupField(ctx, field); err != nil {
+			return models.StudentStats{}, err
+		}
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.StudentStats{}, err
+	}
+	return studentStats(data, filter, options, nil), nil
+}
+
+// studentStats aggregates the students matching filter, calling visit, when
+// set, for each of them in the same pass. Callers hold mutex and have
+// validated options.
+func studentStats(data *studentStore, filter StudentFilter, options StatsOptions, visit func(student models.Student)) models.StudentStats {
+	if options.AgeBucketWidth <= 0 {
+		options.AgeBucketWidth = DefaultAgeBucketWidth
+	}
+
+	var stats models.StudentStats
+	ages := make(map[int]int)
+	domains := make(map[string]int)
+	days := make(map[string]int)
+	weeks := make(map[string]int)
+	groups := make(map[string]map[string]int, len(options.GroupBy))
+	for _, field := range options.GroupBy {
+		groups[field] = make(map[string]int)
+	}
+
+	for _, student := range data.students {
+		if !filter.Matches(student) {
+			continue
+		}
+		stats.Count++
+		ages[student.CurrentAge()/options.AgeBucketWidth]++
+		domains[student.EmailDomain()]++
+		if !student.CreatedAt.IsZero() {
+			days[student.CreatedAt.Format(models.DateLayout)]++
+			year, week := student.CreatedAt.ISOWeek()
+			weeks[fmt.Sprintf("%04d-W%02d", year, week)]++
+		}
+		for field, counts := range groups {
+			counts[student.GroupValue(field)]++
+		}
+		if visit != nil {
+			visit(student)
+		}
+	}
+
+	stats.AgeHistogram = ageHistogram(ages, options.AgeBucketWidth)
+	stats.EmailDomains = bucketsByCount(domains)
+	stats.CreatedPerDay = bucketsByValue(days)
+	stats.CreatedPerWeek = bucketsByValue(weeks)
+	if len(groups) > 0 {
+		stats.Groups = make(map[string][]models.StatsBucket, len(groups))
+		for field, counts := range groups {
+			stats.Groups[field] = bucketsByCount(counts)
+		}
+	}
+	return stats
+}
+
+func validateGroupField(ctx context.Context, field string) error {
+	if key := strings.TrimPrefix(field, models.CustomFieldGroupPrefix); key != field {
+		for _, definition := range ListCustomFields(ctx) {
+			if definition.Key == key {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: unknown custom field %s", ErrInvalidGroupField, key)
+	}
+	if !models.IsStudentGroupField(field) {
+		return fmt.Errorf("%w: %s", ErrInvalidGroupField, field)
+	}
+	return nil
+}
+
+// ageHistogram turns counts keyed by bucket index into contiguous buckets
+// from the youngest to the oldest, including empty buckets in between.
+func ageHistogram(counts map[int]int, width int) []models.AgeBucket {
+	histogram := make([]models.AgeBucket, 0)
+	if len(counts) == 0 {
+		return histogram
+	}
+
+	first, last := -1, -1
+	for bucket := range counts {
+		if first == -1 || bucket < first {
+			first = bucket
+		}
+		if bucket > last {
+			last = bucket
+		}
+	}
+	for bucket := first; bucket <= last; bucket++ {
+		histogram = append(histogram, models.AgeBucket{
+			Min:   bucket * width,
+			Max:   (bucket+1)*width - 1,
+			Count: counts[bucket],
+		})
+	}
+	return histogram
+}
+
+func bucketsByValue(counts map[string]int) []models.StatsBucket {
+	buckets := statsBuckets(counts)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Value < buckets[j].Value })
+	return buckets
+}
+
+func bucketsByCount(counts map[string]int) []models.StatsBucket {
+	buckets := statsBuckets(counts)
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	return buckets
+}
+
+func statsBuckets(counts map[string]int) []models.StatsBucket {
+	buckets := make([]models.StatsBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, models.StatsBucket{Value: value, Count: count})
+	}
+	return buckets
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestGetStudentStats(t *testing.T) {
+	ResetStudents()
+	ResetCustomFields()
+
+	ctx := context.Background()
+	CreateCustomField(ctx, models.CustomFieldDefinition{Key: "house", Label: "House", Type: models.CustomFieldString})
+	CreateStudent(ctx, models.Student{Name: "Alice", Age: 18, Email: "alice@school.edu", CustomFields: map[string]interface{}{"house": "red"}})
+	CreateStudent(ctx, models.Student{Name: "Bob", Age: 19, Email: "bob@School.edu", Status: models.StudentApplied})
+	CreateStudent(ctx, models.Student{Name: "Carol", Age: 31, Email: "carol@example.com", CustomFields: map[string]interface{}{"house": "red"}})
+	deleted, _ := CreateStudent(ctx, models.Student{Name: "Dave", Age: 40, Email: "dave@example.com"})
+	DeleteStudent(ctx, deleted.ID)
+
+	stats, err := GetStudentStats(ctx, StudentFilter{}, StatsOptions{GroupBy: []string{"status", "custom_fields.house"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Count != 3 {
+		t.Errorf("Expected 3 students, got %d", stats.Count)
+	}
+
+	expectedAges := []models.AgeBucket{{Min: 15, Max: 19, Count: 2}, {Min: 20, Max: 24, Count: 0}, {Min: 25, Max: 29, Count: 0}, {Min: 30, Max: 34, Count: 1}}
+	if len(stats.AgeHistogram) != len(expectedAges) {
+		t.Fatalf("Expected %d age buckets, got %+v", len(expectedAges), stats.AgeHistogram)
+	}
+	for i, bucket := range expectedAges {
+		if stats.AgeHistogram[i] != bucket {
+			t.Errorf("Expected age bucket %+v, got %+v", bucket, stats.AgeHistogram[i])
+		}
+	}
+
+	if len(stats.EmailDomains) != 2 || stats.EmailDomains[0] != (models.StatsBucket{Value: "school.edu", Count: 2}) {
+		t.Errorf("Expected school.edu to lead with 2 students, got %+v", stats.EmailDomains)
+	}
+
+	today := time.Now().UTC().Format(models.DateLayout)
+	if len(stats.CreatedPerDay) != 1 || stats.CreatedPerDay[0].Value != today || stats.CreatedPerDay[0].Count != 3 {
+		t.Errorf("Expected 3 creations today, got %+v", stats.CreatedPerDay)
+	}
+	if len(stats.CreatedPerWeek) != 1 || stats.CreatedPerWeek[0].Count != 3 {
+		t.Errorf("Expected 3 creations this week, got %+v", stats.CreatedPerWeek)
+	}
+
+	if status := stats.Groups["status"]; len(status) != 2 || status[0] != (models.StatsBucket{Value: "enrolled", Count: 2}) {
+		t.Errorf("Expected 2 enrolled and 1 applied, got %+v", status)
+	}
+	if house := stats.Groups["custom_fields.house"]; len(house) != 2 || house[0] != (models.StatsBucket{Value: "red", Count: 2}) || house[1].Value != "" {
+		t.Errorf("Expected 2 red and 1 without a house, got %+v", house)
+	}
+}
+
+func TestGetStudentStatsOptions(t *testing.T) {
+	ResetStudents()
+	ResetCustomFields()
+
+	ctx := context.Background()
+	CreateStudent(ctx, models.Student{Name: "Alice", Age: 18, Email: "alice@school.edu"})
+	CreateStudent(ctx, models.Student{Name: "Carol", Age: 31, Email: "carol@example.com"})
+
+	stats, err := GetStudentStats(ctx, StudentFilter{EmailDomain: "example.com"}, StatsOptions{AgeBucketWidth: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Count != 1 || len(stats.AgeHistogram) != 1 || stats.AgeHistogram[0] != (models.AgeBucket{Min: 30, Max: 39, Count: 1}) {
+		t.Errorf("Expected one filtered student in the 30-39 bucket, got %+v", stats)
+	}
+	if stats.Groups != nil {
+		t.Errorf("Expected no groups, got %+v", stats.Groups)
+	}
+
+	for _, field := range []string{"contacts", "custom_fields.house"} {
+		if _, err := GetStudentStats(ctx, StudentFilter{}, StatsOptions{GroupBy: []string{field}}); !errors.Is(err, ErrInvalidGroupField) {
+			t.Errorf("%s: expected ErrInvalidGroupField, got %v", field, err)
+		}
+	}
+
+	ResetStudents()
+	stats, _ = GetStudentStats(ctx, StudentFilter{}, StatsOptions{})
+	if stats.Count != 0 || len(stats.AgeHistogram) != 0 || stats.EmailDomains == nil {
+		t.Errorf("Expected empty stats with empty lists, got %+v", stats)
+	}
+}
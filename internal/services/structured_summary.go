@@ -0,0 +1,79 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// summarySchema is the JSON schema sent as Ollama's format parameter so the
+// model returns a structured summary instead of free text.
+var summarySchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"summary": {"type": "string", "minLength": 1},
+		"strengths": {"type": "array", "items": {"type": "string", "minLength": 1}, "minItems": 1},
+		"next_steps": {"type": "array", "items": {"type": "string", "minLength": 1}, "minItems": 1}
+	},
+	"required": ["summary", "strengths", "next_steps"],
+	"additionalProperties": false
+}`)
+
+// structuredOutputInstruction is appended to the prompt so the model knows
+// what each field of summarySchema should contain.
+const structuredOutputInstruction = `
+
+Respond with a JSON object with these fields:
+- "summary": the summary described above
+- "strengths": a list of the student's key strengths, as short phrases
+- "next_steps": a list of suggested next steps for the student`
+
+type structuredSummary struct {
+	Summary   string   `json:"summary"`
+	Strengths []string `json:"strengths"`
+	NextSteps []string `json:"next_steps"`
+}
+
+// parseStructuredSummary decodes a model response and checks it against
+// summarySchema.
+func parseStructuredSummary(response string) (structuredSummary, error) {
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.DisallowUnknownFields()
+
+	var output structuredSummary
+	if err := decoder.Decode(&output); err != nil {
+		return structuredSummary{}, fmt.Errorf("decode structured summary: %w", err)
+	}
+	if decoder.More() {
+		return structuredSummary{}, errors.New("unexpected data after structured summary")
+	}
+	if err := output.validate(); err != nil {
+		return structuredSummary{}, err
+	}
+	return output, nil
+}
+
+func (s *structuredSummary) validate() error {
+	s.Summary = strings.TrimSpace(s.Summary)
+	if s.Summary == "" {
+		return errors.New("summary is required")
+	}
+	if err := validateStringList("strengths", s.Strengths); err != nil {
+		return err
+	}
+	return validateStringList("next_steps", s.NextSteps)
+}
+
+func validateStringList(field string, values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%s must contain at least one item", field)
+	}
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return fmt.Errorf("%s must not contain empty items", field)
+		}
+	}
+	return nil
+}
@@ -1,77 +1,326 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"student-api/internal/models"
+	"student-api/internal/reqctx"
 	"sync"
+	"time"
 )
 
 var (
-	students = make(map[int]models.Student)
-	nextID   = 1
-	mutex    = sync.RWMutex{}
+	ErrStudentNotFound          = errors.New("student not found")
+	ErrStudentNotDeleted        = errors.New("student is not deleted")
+	ErrInvalidStudentTransition = errors.New("invalid student status transition")
+	ErrStatusChangeNotAllowed   = errors.New("status can only be changed through a transition")
 )
 
-func CreateStudent(student models.Student) models.Student {
+// mutex guards the students of every tenant.
+var mutex = sync.RWMutex{}
+
+// studentStore holds one tenant's students and their status transitions.
+type studentStore struct {
+	students         map[int]models.Student
+	nextStudentID    int
+	transitions      map[int][]models.StatusTransition
+	nextTransitionID int
+}
+
+var studentStores = newTenantStores(&mutex, func() *studentStore {
+	return &studentStore{
+		students:         make(map[int]models.Student),
+		nextStudentID:    1,
+		transitions:      make(map[int][]models.StatusTransition),
+		nextTransitionID: 1,
+	}
+}, nil)
+
+type StudentFilter struct {
+	IncludeDeleted bool   `json:"include_deleted,omitempty"`
+	MinAge         int    `json:"min_age,omitempty"`
+	MaxAge         int    `json:"max_age,omitempty"`
+	EmailDomain    string `json:"email_domain,omitempty"`
+	// Status, when set, keeps only students in one of the statuses.
+	Status []models.StudentStatus `json:"status,omitempty"`
+}
+
+// Validate checks a filter, whether received as JSON or parsed from the query
+// parameters of GET /students.
+func (f StudentFilter) Validate() error {
+	if f.MinAge < 0 || f.MaxAge < 0 {
+		return errors.New("min_age and max_age must not be negative")
+	}
+	if f.MinAge > 0 && f.MaxAge > 0 && f.MinAge > f.MaxAge {
+		return errors.New("min_age must not be greater than max_age")
+	}
+	if strings.Contains(f.EmailDomain, "@") {
+		return errors.New("email_domain must not contain @")
+	}
+	for _, status := range f.Status {
+		if !status.IsValid() {
+			return fmt.Errorf("unknown status %q", status)
+		}
+	}
+	return nil
+}
+
+func (f StudentFilter) Matches(student models.Student) bool {
+	if student.IsDeleted() && !f.IncludeDeleted {
+		return false
+	}
+	if f.MinAge > 0 && student.CurrentAge() < f.MinAge {
+		return false
+	}
+	if f.MaxAge > 0 && student.CurrentAge() > f.MaxAge {
+		return false
+	}
+	if f.EmailDomain != "" && !strings.HasSuffix(strings.ToLower(student.Email), "@"+strings.ToLower(f.EmailDomain)) {
+		return false
+	}
+	if len(f.Status) > 0 && !containsStatus(f.Status, student.Status) {
+		return false
+	}
+	return true
+}
+
+func containsStatus(statuses []models.StudentStatus, status models.StudentStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+func CreateStudent(ctx context.Context, student models.Student) (models.Student, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Student{}, err
+	}
 
-	student.ID = nextID
-	nextID++
-	students[student.ID] = student
-	return student
+	student.ID = data.nextStudentID
+	student.CreatedAt = time.Now().UTC()
+	student.DeletedAt = nil
+	normalizeStudent(&student, models.StudentEnrolled)
+	data.nextStudentID++
+	data.students[student.ID] = student
+	recordAudit(ctx, models.AuditActionCreate, student.ID, nil, &student)
+	publishEvent(ctx, models.StudentCreated, student)
+	return student, nil
+}
+
+func GetAllStudents(ctx context.Context) []models.Student {
+	return ListStudents(ctx, StudentFilter{})
 }
 
-func GetAllStudents() []models.Student {
+// ListStudents returns the students matching filter, or none when the
+// tenant does not exist.
+func ListStudents(ctx context.Context, filter StudentFilter) []models.Student {
 	mutex.RLock()
 	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return []models.Student{}
+	}
 
-	result := make([]models.Student, 0, len(students))
-	for _, student := range students {
-		result = append(result, student)
+	result := make([]models.Student, 0, len(data.students))
+	for _, student := range data.students {
+		if filter.Matches(student) {
+			result = append(result, student)
+		}
 	}
 	return result
 }
 
-func GetStudentByID(id int) (models.Student, error) {
+func GetStudentByID(ctx context.Context, id int) (models.Student, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Student{}, err
+	}
+
+	student, exists := data.students[id]
+	if !exists || student.IsDeleted() {
+		return models.Student{}, ErrStudentNotFound
+	}
+	return student, nil
+}
+
+// GetStudentIncludingDeleted returns a student by ID even when it has been
+// soft-deleted, for records such as the audit history that outlive the
+// student.
+func GetStudentIncludingDeleted(ctx context.Context, id int) (models.Student, error) {
 	mutex.RLock()
 	defer mutex.RUnlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Student{}, err
+	}
 
-	student, exists := students[id]
+	student, exists := data.students[id]
 	if !exists {
-		return models.Student{}, errors.New("student not found")
+		return models.Student{}, ErrStudentNotFound
 	}
 	return student, nil
 }
 
-func UpdateStudent(id int, student models.Student) (models.Student, error) {
+// UpdateStudent replaces a student's details. The status is kept; a request
+// naming a different status is rejected, since status changes go through
+// TransitionStudent.
+func UpdateStudent(ctx context.Context, id int, student models.Student) (models.Student, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Student{}, err
+	}
 
-	if _, exists := students[id]; !exists {
-		return models.Student{}, errors.New("student not found")
+	existing, exists := data.students[id]
+	if !exists || existing.IsDeleted() {
+		return models.Student{}, ErrStudentNotFound
+	}
+	if student.Status != "" && student.Status != existing.Status {
+		return models.Student{}, ErrStatusChangeNotAllowed
 	}
 
 	student.ID = id
-	students[id] = student
+	student.CreatedAt = existing.CreatedAt
+	student.DeletedAt = nil
+	normalizeStudent(&student, existing.Status)
+	data.students[id] = student
+	recordAudit(ctx, models.AuditActionUpdate, id, &existing, &student)
+	publishEvent(ctx, models.StudentUpdated, student)
 	return student, nil
 }
 
-func DeleteStudent(id int) error {
+// DeleteStudent soft-deletes a student. The record stays in the store, hidden
+// from normal reads, until it is restored or purged. The student's active
+// enrollments are dropped and stay dropped if the student is restored.
+func DeleteStudent(ctx context.Context, id int) error {
 	mutex.Lock()
 	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return err
+	}
 
-	if _, exists := students[id]; !exists {
-		return errors.New("student not found")
+	existing, exists := data.students[id]
+	if !exists || existing.IsDeleted() {
+		return ErrStudentNotFound
 	}
 
-	delete(students, id)
+	deleted := existing
+	deletedAt := time.Now().UTC()
+	deleted.DeletedAt = &deletedAt
+	data.students[id] = deleted
+	dropStudentEnrollments(ctx, id)
+	recordAudit(ctx, models.AuditActionDelete, id, &existing, &deleted)
+	publishEvent(ctx, models.StudentDeleted, deleted)
 	return nil
 }
 
+func RestoreStudent(ctx context.Context, id int) (models.Student, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return models.Student{}, err
+	}
+
+	existing, exists := data.students[id]
+	if !exists {
+		return models.Student{}, ErrStudentNotFound
+	}
+	if !existing.IsDeleted() {
+		return models.Student{}, ErrStudentNotDeleted
+	}
+
+	restored := existing
+	restored.DeletedAt = nil
+	data.students[id] = restored
+	recordAudit(ctx, models.AuditActionRestore, id, &existing, &restored)
+	publishEvent(ctx, models.StudentRestored, restored)
+	return restored, nil
+}
+
+// PurgeDeletedStudents permanently removes the tenant's students soft-deleted
+// before cutoff and returns how many were removed.
+func PurgeDeletedStudents(ctx context.Context, cutoff time.Time) int {
+	mutex.Lock()
+	defer mutex.Unlock()
+	data, err := studentStores.get(ctx)
+	if err != nil {
+		return 0
+	}
+
+	purged := 0
+	for id, student := range data.students {
+		if student.IsDeleted() && student.DeletedAt.Before(cutoff) {
+			delete(data.students, id)
+			delete(data.transitions, id)
+			recordAudit(ctx, models.AuditActionPurge, id, &student, nil)
+			publishEvent(ctx, models.StudentPurged, student)
+			deleteStudentSummaries(ctx, id)
+			deleteStudentEnrollments(ctx, id)
+			deleteStudentAttachments(ctx, id)
+			purged++
+		}
+	}
+	return purged
+}
+
+// StartStudentPurger purges every tenant's students deleted longer than
+// retention ago every interval until ctx is cancelled.
+func StartStudentPurger(ctx context.Context, interval, retention time.Duration) {
+	ctx = reqctx.WithActor(ctx, "system")
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cutoff := time.Now().Add(-retention)
+				for _, tenant := range tenantIDs() {
+					if purged := PurgeDeletedStudents(reqctx.WithTenant(ctx, tenant), cutoff); purged > 0 {
+						log.Printf("Purged %d deleted students of tenant %s", purged, tenant)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// normalizeStudent prepares a student for storage: the age is not stored
+// when it is derived from the date of birth, a missing status becomes
+// defaultStatus and custom fields set to null are removed.
+func normalizeStudent(student *models.Student, defaultStatus models.StudentStatus) {
+	if student.DateOfBirth != nil {
+		student.Age = 0
+	}
+	if student.Status == "" {
+		student.Status = defaultStatus
+	}
+	for key, value := range student.CustomFields {
+		if value == nil {
+			delete(student.CustomFields, key)
+		}
+	}
+	if len(student.CustomFields) == 0 {
+		student.CustomFields = nil
+	}
+}
+
 func ResetStudents() {
 	mutex.Lock()
 	defer mutex.Unlock()
-	students = make(map[int]models.Student)
-	nextID = 1
+	studentStores.reset()
 }
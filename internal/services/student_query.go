@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"student-api/internal/models"
+	"unicode/utf8"
+)
+
+// MaxStudentQuestionLength is the longest question, in characters, that can
+// be translated into a student filter.
+const MaxStudentQuestionLength = 300
+
+// StudentQuery is a natural-language question about students and the filter
+// a model translated it into.
+type StudentQuery struct {
+	Question      string        `json:"question"`
+	Filter        StudentFilter `json:"filter"`
+	Model         string        `json:"model"`
+	PromptVersion string        `json:"prompt_version"`
+}
+
+// studentQueryPromptData is what the query task prompt renders: the quoted
+// question and the list of statuses. The model only ever produces a filter,
+// which is validated before it is run.
+type studentQueryPromptData struct {
+	Question string
+	Statuses string
+}
+
+// studentQuerySchema is sent as Ollama's format parameter so the model
+// returns a StudentFilter.
+var studentQuerySchema = func() json.RawMessage {
+	statuses, _ := json.Marshal(models.StudentStatuses)
+	return json.RawMessage(fmt.Sprintf(`{
+	"type": "object",
+	"properties": {
+		"min_age": {"type": "integer", "minimum": 1},
+		"max_age": {"type": "integer", "minimum": 1},
+		"email_domain": {"type": "string"},
+		"status": {"type": "array", "items": {"type": "string", "enum": %s}}
+	},
+	"additionalProperties": false
+}`, statuses))
+}()
+
+// ValidateStudentQuestion checks a question before it is sent to a model.
+func ValidateStudentQuestion(question string) error {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return errors.New("question is required")
+	}
+	if utf8.RuneCountInString(question) > MaxStudentQuestionLength {
+		return fmt.Errorf("question must be at most %d characters", MaxStudentQuestionLength)
+	}
+	return nil
+}
+
+func buildStudentQueryPrompt(question string) (string, *PromptTemplate, error) {
+	prompt, err := lookupTaskPrompt(queryTaskPrompt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	statuses := make([]string, len(models.StudentStatuses))
+	for i, status := range models.StudentStatuses {
+		statuses[i] = string(status)
+	}
+	rendered, err := prompt.execute(studentQueryPromptData{
+		Question: quotePromptText(question, MaxStudentQuestionLength),
+		Statuses: strings.Join(statuses, ", "),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return rendered, prompt, nil
+}
+
+// studentQueryFilter is the part of StudentFilter a model may produce.
+// Deleted students are only listed when a caller asks for them explicitly,
+// so include_deleted is rejected as an unknown field.
+type studentQueryFilter struct {
+	MinAge      int                    `json:"min_age"`
+	MaxAge      int                    `json:"max_age"`
+	EmailDomain string                 `json:"email_domain"`
+	Status      []models.StudentStatus `json:"status"`
+}
+
+// parseStudentQueryFilter decodes a model response into a filter and
+// validates it. A Markdown code fence around the JSON, which models without
+// structured output often add, is ignored.
+func parseStudentQueryFilter(response string) (StudentFilter, error) {
+	response = strings.TrimSpace(response)
+	if strings.HasPrefix(response, "```") {
+		response = strings.TrimSuffix(response, "```")
+		if newline := strings.IndexByte(response, '\n'); newline >= 0 {
+			response = response[newline+1:]
+		}
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.DisallowUnknownFields()
+
+	var decoded studentQueryFilter
+	if err := decoder.Decode(&decoded); err != nil {
+		return StudentFilter{}, fmt.Errorf("decode student filter: %w", err)
+	}
+	if decoder.More() {
+		return StudentFilter{}, errors.New("unexpected data after student filter")
+	}
+
+	filter := StudentFilter{
+		MinAge:      decoded.MinAge,
+		MaxAge:      decoded.MaxAge,
+		EmailDomain: strings.ToLower(strings.TrimPrefix(strings.TrimSpace(decoded.EmailDomain), "@")),
+		Status:      decoded.Status,
+	}
+	if err := filter.Validate(); err != nil {
+		return StudentFilter{}, err
+	}
+	return filter, nil
+}
+
+func newStudentQuery(question string, filter StudentFilter, model, promptVersion string) StudentQuery {
+	return StudentQuery{
+		Question:      strings.TrimSpace(question),
+		Filter:        filter,
+		Model:         model,
+		PromptVersion: promptVersion,
+	}
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"student-api/internal/models"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestCreateStudent(t *testing.T) {
@@ -15,7 +17,7 @@ func TestCreateStudent(t *testing.T) {
 		Email: "john@example.com",
 	}
 
-	result := CreateStudent(student)
+	result, _ := CreateStudent(context.Background(), student)
 
 	if result.ID != 1 {
 		t.Errorf("Expected ID 1, got %d", result.ID)
@@ -28,15 +30,15 @@ func TestCreateStudent(t *testing.T) {
 func TestGetAllStudents(t *testing.T) {
 	ResetStudents()
 
-	students := GetAllStudents()
+	students := GetAllStudents(context.Background())
 	if len(students) != 0 {
 		t.Errorf("Expected empty list, got %d students", len(students))
 	}
 
-	CreateStudent(models.Student{Name: "Student1", Age: 20, Email: "s1@example.com"})
-	CreateStudent(models.Student{Name: "Student2", Age: 21, Email: "s2@example.com"})
+	CreateStudent(context.Background(), models.Student{Name: "Student1", Age: 20, Email: "s1@example.com"})
+	CreateStudent(context.Background(), models.Student{Name: "Student2", Age: 21, Email: "s2@example.com"})
 
-	students = GetAllStudents()
+	students = GetAllStudents(context.Background())
 	if len(students) != 2 {
 		t.Errorf("Expected 2 students, got %d", len(students))
 	}
@@ -45,13 +47,13 @@ func TestGetAllStudents(t *testing.T) {
 func TestGetStudentByID(t *testing.T) {
 	ResetStudents()
 
-	student := CreateStudent(models.Student{
+	student, _ := CreateStudent(context.Background(), models.Student{
 		Name:  "Jane Doe",
 		Age:   22,
 		Email: "jane@example.com",
 	})
 
-	result, err := GetStudentByID(student.ID)
+	result, err := GetStudentByID(context.Background(), student.ID)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -59,7 +61,7 @@ func TestGetStudentByID(t *testing.T) {
 		t.Errorf("Expected name %s, got %s", student.Name, result.Name)
 	}
 
-	_, err = GetStudentByID(999)
+	_, err = GetStudentByID(context.Background(), 999)
 	if err == nil {
 		t.Error("Expected error for non-existing student")
 	}
@@ -68,7 +70,7 @@ func TestGetStudentByID(t *testing.T) {
 func TestUpdateStudent(t *testing.T) {
 	ResetStudents()
 
-	student := CreateStudent(models.Student{
+	student, _ := CreateStudent(context.Background(), models.Student{
 		Name:  "Original",
 		Age:   20,
 		Email: "original@example.com",
@@ -80,7 +82,7 @@ func TestUpdateStudent(t *testing.T) {
 		Email: "updated@example.com",
 	}
 
-	result, err := UpdateStudent(student.ID, updatedData)
+	result, err := UpdateStudent(context.Background(), student.ID, updatedData)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -91,7 +93,7 @@ func TestUpdateStudent(t *testing.T) {
 		t.Errorf("Expected ID %d, got %d", student.ID, result.ID)
 	}
 
-	_, err = UpdateStudent(999, updatedData)
+	_, err = UpdateStudent(context.Background(), 999, updatedData)
 	if err == nil {
 		t.Error("Expected error for non-existing student")
 	}
@@ -100,23 +102,23 @@ func TestUpdateStudent(t *testing.T) {
 func TestDeleteStudent(t *testing.T) {
 	ResetStudents()
 
-	student := CreateStudent(models.Student{
+	student, _ := CreateStudent(context.Background(), models.Student{
 		Name:  "To Delete",
 		Age:   20,
 		Email: "delete@example.com",
 	})
 
-	err := DeleteStudent(student.ID)
+	err := DeleteStudent(context.Background(), student.ID)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	_, err = GetStudentByID(student.ID)
+	_, err = GetStudentByID(context.Background(), student.ID)
 	if err == nil {
 		t.Error("Expected error after deletion")
 	}
 
-	err = DeleteStudent(999)
+	err = DeleteStudent(context.Background(), 999)
 	if err == nil {
 		t.Error("Expected error for non-existing student")
 	}
@@ -132,7 +134,7 @@ func TestConcurrency(t *testing.T) {
 	for i := 0; i < numGoroutines; i++ {
 		go func(index int) {
 			defer wg.Done()
-			CreateStudent(models.Student{
+			CreateStudent(context.Background(), models.Student{
 				Name:  "Student" + string(rune(index)),
 				Age:   20 + index,
 				Email: "student" + string(rune(index)) + "@example.com",
@@ -141,7 +143,7 @@ func TestConcurrency(t *testing.T) {
 	}
 	wg.Wait()
 
-	students := GetAllStudents()
+	students := GetAllStudents(context.Background())
 	if len(students) != numGoroutines {
 		t.Errorf("Expected %d students, got %d", numGoroutines, len(students))
 	}
@@ -150,8 +152,84 @@ func TestConcurrency(t *testing.T) {
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
 			defer wg.Done()
-			GetAllStudents()
+			GetAllStudents(context.Background())
 		}()
 	}
 	wg.Wait()
 }
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	ResetStudents()
+
+	student, _ := CreateStudent(context.Background(), models.Student{
+		Name:  "Soft Deleted",
+		Age:   20,
+		Email: "soft@example.com",
+	})
+
+	if err := DeleteStudent(context.Background(), student.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if students := GetAllStudents(context.Background()); len(students) != 0 {
+		t.Errorf("Expected deleted student to be hidden, got %d students", len(students))
+	}
+
+	students := ListStudents(context.Background(), StudentFilter{IncludeDeleted: true})
+	if len(students) != 1 || !students[0].IsDeleted() {
+		t.Fatalf("Expected deleted student with deleted_at marker, got %+v", students)
+	}
+
+	if _, err := UpdateStudent(context.Background(), student.ID, student); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound updating deleted student, got %v", err)
+	}
+
+	if err := DeleteStudent(context.Background(), student.ID); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound deleting twice, got %v", err)
+	}
+
+	restored, err := RestoreStudent(context.Background(), student.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if restored.IsDeleted() {
+		t.Error("Expected restored student to have no deleted_at marker")
+	}
+
+	if _, err := GetStudentByID(context.Background(), student.ID); err != nil {
+		t.Errorf("Expected restored student to be readable, got %v", err)
+	}
+
+	if _, err := RestoreStudent(context.Background(), student.ID); err != ErrStudentNotDeleted {
+		t.Errorf("Expected ErrStudentNotDeleted, got %v", err)
+	}
+
+	if _, err := RestoreStudent(context.Background(), 999); err != ErrStudentNotFound {
+		t.Errorf("Expected ErrStudentNotFound, got %v", err)
+	}
+}
+
+func TestPurgeDeletedStudents(t *testing.T) {
+	ResetStudents()
+
+	kept, _ := CreateStudent(context.Background(), models.Student{Name: "Kept", Age: 20, Email: "kept@example.com"})
+	purged, _ := CreateStudent(context.Background(), models.Student{Name: "Purged", Age: 21, Email: "purged@example.com"})
+	DeleteStudent(context.Background(), purged.ID)
+
+	if count := PurgeDeletedStudents(context.Background(), time.Now().Add(-time.Hour)); count != 0 {
+		t.Errorf("Expected nothing purged within retention, got %d", count)
+	}
+
+	if count := PurgeDeletedStudents(context.Background(), time.Now().Add(time.Second)); count != 1 {
+		t.Errorf("Expected 1 student purged, got %d", count)
+	}
+
+	students := ListStudents(context.Background(), StudentFilter{IncludeDeleted: true})
+	if len(students) != 1 || students[0].ID != kept.ID {
+		t.Errorf("Expected only the kept student to remain, got %+v", students)
+	}
+
+	if _, err := RestoreStudent(context.Background(), purged.ID); err != ErrStudentNotFound {
+		t.Errorf("Expected purged student to be unrecoverable, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"student-api/internal/models"
+	"sync"
+)
+
+var ErrSummaryNotFound = errors.New("summary not found")
+
+// summaryMutex guards the summaries of every tenant.
+var summaryMutex = sync.RWMutex{}
+
+// summaryStore holds one tenant's summary histories, keyed by student ID.
+type summaryStore struct {
+	summaries     map[int][]models.Summary
+	nextSummaryID int
+}
+
+var summaryStores = newTenantStores(&summaryMutex, func() *summaryStore {
+	return &summaryStore{summaries: make(map[int][]models.Summary), nextSummaryID: 1}
+}, nil)
+
+// SaveSummary stores a generated summary in the student's summary history and
+// returns it with its assigned ID.
+func SaveSummary(ctx context.Context, summary models.Summary) (models.Summary, error) {
+	summaryMutex.Lock()
+	defer summaryMutex.Unlock()
+	data, err := summaryStores.get(ctx)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	summary.ID = data.nextSummaryID
+	summary.Pinned = false
+	data.nextSummaryID++
+	data.summaries[summary.StudentID] = append(data.summaries[summary.StudentID], summary)
+	return summary, nil
+}
+
+func GetStudentSummaries(ctx context.Context, studentID int) []models.Summary {
+	summaryMutex.RLock()
+	defer summaryMutex.RUnlock()
+	data, err := summaryStores.get(ctx)
+	if err != nil {
+		return []models.Summary{}
+	}
+
+	return append([]models.Summary{}, data.summaries[studentID]...)
+}
+
+// PinSummary marks a summary as the student's preferred one, unpinning any
+// previously pinned summary.
+func PinSummary(ctx context.Context, studentID, summaryID int) (models.Summary, error) {
+	summaryMutex.Lock()
+	defer summaryMutex.Unlock()
+	data, err := summaryStores.get(ctx)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	history := data.summaries[studentID]
+	index := -1
+	for i := range history {
+		if history[i].ID == summaryID {
+			index = i
+		}
+	}
+	if index == -1 {
+		return models.Summary{}, ErrSummaryNotFound
+	}
+
+	for i := range history {
+		history[i].Pinned = i == index
+	}
+	return history[index], nil
+}
+
+func deleteStudentSummaries(ctx context.Context, studentID int) {
+	summaryMutex.Lock()
+	defer summaryMutex.Unlock()
+	if data, err := summaryStores.get(ctx); err == nil {
+		delete(data.summaries, studentID)
+	}
+}
+
+func ResetSummaries() {
+	summaryMutex.Lock()
+	defer summaryMutex.Unlock()
+	summaryStores.reset()
+}
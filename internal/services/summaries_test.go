@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestSaveAndPinSummaries(t *testing.T) {
+	ResetSummaries()
+
+	first, _ := SaveSummary(context.Background(), models.Summary{StudentID: 1, Text: "First", Model: "llama3", PromptVersion: "v1"})
+	second, _ := SaveSummary(context.Background(), models.Summary{StudentID: 1, Text: "Second", Model: "llama3", PromptVersion: "v1", Pinned: true})
+	SaveSummary(context.Background(), models.Summary{StudentID: 2, Text: "Other student"})
+
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("Expected sequential IDs, got %d and %d", first.ID, second.ID)
+	}
+	if second.Pinned {
+		t.Error("Expected new summaries to start unpinned")
+	}
+
+	history := GetStudentSummaries(context.Background(), 1)
+	if len(history) != 2 || history[0].Text != "First" {
+		t.Fatalf("Expected two summaries oldest first, got %+v", history)
+	}
+
+	if _, err := PinSummary(context.Background(), 1, first.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pinned, err := PinSummary(context.Background(), 1, second.ID)
+	if err != nil || !pinned.Pinned {
+		t.Fatalf("Expected pinned summary, got %+v, %v", pinned, err)
+	}
+
+	history = GetStudentSummaries(context.Background(), 1)
+	if history[0].Pinned || !history[1].Pinned {
+		t.Errorf("Expected only the latest pin to remain, got %+v", history)
+	}
+
+	if _, err := PinSummary(context.Background(), 2, first.ID); err != ErrSummaryNotFound {
+		t.Errorf("Expected ErrSummaryNotFound for another student's summary, got %v", err)
+	}
+}
+
+func TestSummariesRemovedOnPurge(t *testing.T) {
+	ResetStudents()
+	ResetSummaries()
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	SaveSummary(context.Background(), models.Summary{StudentID: student.ID, Text: "Summary"})
+
+	DeleteStudent(context.Background(), student.ID)
+	if len(GetStudentSummaries(context.Background(), student.ID)) != 1 {
+		t.Error("Expected summaries to survive a soft delete")
+	}
+
+	PurgeDeletedStudents(context.Background(), time.Now().Add(time.Second))
+	if len(GetStudentSummaries(context.Background(), student.ID)) != 0 {
+		t.Error("Expected summaries to be removed when the student is purged")
+	}
+}
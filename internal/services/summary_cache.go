@@ -0,0 +1,151 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+type summaryCacheEntry struct {
+	key       string
+	tenantID  string
+	summary   models.Summary
+	expiresAt time.Time
+}
+
+// SummaryCache is a TTL-bounded LRU cache of generated summaries.
+type SummaryCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mutex sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+func NewSummaryCache(maxEntries int, ttl time.Duration) *SummaryCache {
+	return &SummaryCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// SummaryCacheKey identifies a summary by the tenant, the student's content
+// and current transcript together with the model, prompt version and
+// generation options that produced it, so any change to one of them results
+// in a different key.
+func SummaryCacheKey(ctx context.Context, student models.Student, model, promptVersion string, generation models.GenerationOptions) string {
+	studentJSON, _ := json.Marshal(student)
+	transcriptJSON, _ := json.Marshal(studentTranscript(ctx, student.ID))
+	generationJSON, _ := json.Marshal(generation)
+	hash := sha256.New()
+	hash.Write([]byte(reqctx.Tenant(ctx)))
+	hash.Write([]byte{0})
+	hash.Write(studentJSON)
+	hash.Write([]byte{0})
+	hash.Write(transcriptJSON)
+	hash.Write([]byte{0})
+	hash.Write([]byte(model))
+	hash.Write([]byte{0})
+	hash.Write([]byte(promptVersion))
+	hash.Write([]byte{0})
+	hash.Write(generationJSON)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Get returns the cached summary for key and how long it remains fresh.
+func (c *SummaryCache) Get(key string) (models.Summary, time.Duration, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return models.Summary{}, 0, false
+	}
+
+	entry := element.Value.(*summaryCacheEntry)
+	remaining := entry.expiresAt.Sub(c.now())
+	if remaining <= 0 {
+		c.removeElement(element)
+		return models.Summary{}, 0, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.summary, remaining, true
+}
+
+// Set caches a summary of a student of the tenant in ctx.
+func (c *SummaryCache) Set(ctx context.Context, key string, summary models.Summary) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*summaryCacheEntry)
+		entry.summary = summary
+		entry.expiresAt = c.now().Add(c.ttl)
+		c.order.MoveToFront(element)
+		return
+	}
+
+	entry := &summaryCacheEntry{
+		key:       key,
+		tenantID:  reqctx.Tenant(ctx),
+		summary:   summary,
+		expiresAt: c.now().Add(c.ttl),
+	}
+	c.items[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *SummaryCache) InvalidateStudent(tenantID string, studentID int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, element := range c.items {
+		entry := element.Value.(*summaryCacheEntry)
+		if entry.tenantID == tenantID && entry.summary.StudentID == studentID {
+			c.removeElement(element)
+		}
+	}
+}
+
+func (c *SummaryCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *SummaryCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// InvalidateOnChanges drops a student's cached summaries whenever the student
+// is updated or removed, until ctx is cancelled.
+func (c *SummaryCache) InvalidateOnChanges(ctx context.Context) {
+	go consumeEvents(ctx, c.handleEvent)
+}
+
+func (c *SummaryCache) handleEvent(event models.StudentEvent) {
+	switch event.Type {
+	case models.StudentUpdated, models.StudentStatusChanged, models.StudentDeleted, models.StudentPurged:
+		c.InvalidateStudent(event.Tenant, event.StudentID)
+	}
+}
+
+func (c *SummaryCache) removeElement(element *list.Element) {
+	entry := c.order.Remove(element).(*summaryCacheEntry)
+	delete(c.items, entry.key)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestSummaryCacheKey(t *testing.T) {
+	student := models.Student{ID: 1, Name: "John Doe", Age: 20, Email: "john@example.com"}
+	key := SummaryCacheKey(context.Background(), student, "llama3", "v1", models.GenerationOptions{})
+
+	if key != SummaryCacheKey(context.Background(), student, "llama3", "v1", models.GenerationOptions{}) {
+		t.Error("Expected identical inputs to produce the same key")
+	}
+
+	changed := student
+	changed.Age = 21
+	seed := 42
+	variants := []string{
+		SummaryCacheKey(context.Background(), changed, "llama3", "v1", models.GenerationOptions{}),
+		SummaryCacheKey(context.Background(), student, "mistral", "v1", models.GenerationOptions{}),
+		SummaryCacheKey(context.Background(), student, "llama3", "v2", models.GenerationOptions{}),
+		SummaryCacheKey(context.Background(), student, "llama3", "v1", models.GenerationOptions{Seed: &seed}),
+	}
+	for _, variant := range variants {
+		if variant == key {
+			t.Error("Expected student, model, prompt version or option changes to produce a new key")
+		}
+	}
+}
+
+func TestSummaryCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := NewSummaryCache(10, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Set(context.Background(), "key", models.Summary{StudentID: 1, Text: "summary"})
+	summary, remaining, ok := cache.Get("key")
+	if !ok || summary.Text != "summary" || remaining != time.Minute {
+		t.Errorf("Expected fresh hit, got %q %s %v", summary.Text, remaining, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, ok := cache.Get("key"); ok {
+		t.Error("Expected entry to expire after the TTL")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestSummaryCacheLRU(t *testing.T) {
+	cache := NewSummaryCache(2, time.Minute)
+
+	cache.Set(context.Background(), "a", models.Summary{StudentID: 1, Text: "summary a"})
+	cache.Set(context.Background(), "b", models.Summary{StudentID: 2, Text: "summary b"})
+	cache.Get("a")
+	cache.Set(context.Background(), "c", models.Summary{StudentID: 3, Text: "summary c"})
+
+	if _, _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, _, ok := cache.Get("a"); !ok {
+		t.Error("Expected recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected cache to be bounded at 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestSummaryCacheInvalidateOnChanges(t *testing.T) {
+	ResetStudents()
+	ResetEvents()
+
+	cache := NewSummaryCache(10, time.Minute)
+	startEventConsumer(t, cache.InvalidateOnChanges)
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	other, _ := CreateStudent(context.Background(), models.Student{Name: "Jane Doe", Age: 21, Email: "jane@example.com"})
+	cache.Set(context.Background(), "john", models.Summary{StudentID: student.ID, Text: "John summary"})
+	cache.Set(context.Background(), "jane", models.Summary{StudentID: other.ID, Text: "Jane summary"})
+
+	UpdateStudent(context.Background(), student.ID, models.Student{Name: "John Doe", Age: 22, Email: "john@example.com"})
+
+	waitFor(t, func() bool { return cache.Len() == 1 })
+	if _, _, ok := cache.Get("jane"); !ok {
+		t.Error("Expected other students' summaries to be kept")
+	}
+
+	DeleteStudent(context.Background(), other.ID)
+	waitFor(t, func() bool { return cache.Len() == 0 })
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"student-api/internal/models"
+	"time"
+)
+
+const (
+	TemplateSummaryModel   = "template"
+	TemplateSummaryVersion = "template-v1"
+)
+
+// TemplateSummaryService builds summaries from a fixed template, including
+// the student's GPA once they have one, without calling a model. Its output
+// is deterministic, which makes it a fallback for environments with no LLM
+// available. The requested style is validated
+// and recorded but does not change the text, and generation options are
+// ignored.
+type TemplateSummaryService struct{}
+
+func NewTemplateSummaryService() *TemplateSummaryService {
+	return &TemplateSummaryService{}
+}
+
+func (s *TemplateSummaryService) Models() []string {
+	return []string{TemplateSummaryModel}
+}
+
+func (s *TemplateSummaryService) GenerateSummary(ctx context.Context, student models.Student, opts SummaryOptions) (models.Summary, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Summary{}, err
+	}
+	if _, err := ResolveModel(s.Models(), opts.Model); err != nil {
+		return models.Summary{}, err
+	}
+	promptTemplate, err := LookupPrompt(opts.Style)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	return models.Summary{
+		StudentID:     student.ID,
+		Text:          buildTemplateSummary(student, studentTranscript(ctx, student.ID)),
+		Model:         TemplateSummaryModel,
+		Style:         promptTemplate.Style,
+		PromptVersion: TemplateSummaryVersion,
+		GeneratedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// StreamSummary emits the templated summary one word at a time.
+func (s *TemplateSummaryService) StreamSummary(ctx context.Context, student models.Student, opts SummaryOptions, onToken func(token string) error) (models.Summary, error) {
+	summary, err := s.GenerateSummary(ctx, student, opts)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	for _, token := range strings.SplitAfter(summary.Text, " ") {
+		if err := onToken(token); err != nil {
+			return models.Summary{}, err
+		}
+	}
+	return summary, nil
+}
+
+func buildTemplateSummary(student models.Student, transcript models.Transcript) string {
+	text := fmt.Sprintf("%s is a %d-year-old student enrolled with student ID %d.",
+		student.Name, student.CurrentAge(), student.ID)
+	if transcript.GPA != nil {
+		text += fmt.Sprintf(" Their GPA is %.2f out of %.2f.", *transcript.GPA, transcript.MaxGPA)
+	}
+	return text
+}
+
+// StreamCohortReport emits a templated report on the cohort's figures one
+// word at a time.
+func (s *TemplateSummaryService) StreamCohortReport(ctx context.Context, cohort models.Cohort, opts SummaryOptions, onToken func(token string) error) (models.CohortReport, error) {
+	if err := ctx.Err(); err != nil {
+		return models.CohortReport{}, err
+	}
+	if _, err := ResolveModel(s.Models(), opts.Model); err != nil {
+		return models.CohortReport{}, err
+	}
+
+	report := newCohortReport(cohort, TemplateSummaryModel, TemplateSummaryVersion, models.GenerationOptions{}, buildTemplateCohortReport(cohort))
+	for _, token := range strings.SplitAfter(report.Narrative, " ") {
+		if err := onToken(token); err != nil {
+			return models.CohortReport{}, err
+		}
+	}
+	return report, nil
+}
+
+func buildTemplateCohortReport(cohort models.Cohort) string {
+	var b strings.Builder
+	b.WriteString("## Overview\n\n")
+	fmt.Fprintf(&b, "%s covers %d students", cohort.Title, cohort.Stats.Count)
+	if ages := cohort.Stats.AgeHistogram; len(ages) > 0 {
+		fmt.Fprintf(&b, " aged %d to %d", ages[0].Min, ages[len(ages)-1].Max)
+	}
+	b.WriteString(".")
+	if statuses := cohort.Stats.Groups["status"]; len(statuses) > 0 {
+		counts := make([]string, 0, len(statuses))
+		for _, status := range statuses {
+			counts = append(counts, fmt.Sprintf("%d %s", status.Count, status.Value))
+		}
+		fmt.Fprintf(&b, " By status: %s.", strings.Join(counts, ", "))
+	}
+
+	if cohort.AverageGPA != nil {
+		b.WriteString("\n\n## Academic performance\n\n")
+		fmt.Fprintf(&b, "The average GPA is %.2f out of %.2f across %d students with grades.", *cohort.AverageGPA, cohort.MaxGPA, cohort.GradedStudents)
+	}
+	if cohort.AverageAttendance != nil {
+		b.WriteString("\n\n## Attendance\n\n")
+		fmt.Fprintf(&b, "The average attendance rate is %.1f%% across %d students with attendance records.", *cohort.AverageAttendance*100, cohort.TrackedStudents)
+	}
+	return b.String()
+}
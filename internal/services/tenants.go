@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTenantNotFound     = errors.New("tenant not found")
+	ErrTenantExists       = errors.New("tenant already exists")
+	ErrDefaultTenant      = errors.New("the default tenant cannot be deleted")
+	ErrInvalidTenantToken = errors.New("invalid tenant token")
+)
+
+// tenantStores holds one service's store for every tenant. Each service
+// declares its own next to the mutex that guards it. Stores are added when a
+// tenant is created and removed when it is deleted, so request paths only
+// look them up and never bring back the store of a deleted tenant.
+type tenantStores[T any] struct {
+	// mu is the mutex of the owning service. get, lookup and each are called
+	// with it held; the tenant lifecycle methods take it themselves.
+	mu       sync.Locker
+	newStore func() *T
+	// release, when set, stops work still running for a removed store and
+	// deletes content held outside of memory.
+	release func(store *T)
+	stores  map[string]*T
+}
+
+// tenantStoreSet is the tenant lifecycle side of a tenantStores.
+type tenantStoreSet interface {
+	addTenant(tenantID string)
+	removeTenant(tenantID string)
+	resetTenants()
+}
+
+// tenantStoreSets lists every service's stores, in declaration order.
+var tenantStoreSets []tenantStoreSet
+
+func newTenantStores[T any](mu sync.Locker, newStore func() *T, release func(store *T)) *tenantStores[T] {
+	s := &tenantStores[T]{
+		mu:       mu,
+		newStore: newStore,
+		release:  release,
+		stores:   map[string]*T{reqctx.DefaultTenant: newStore()},
+	}
+	tenantStoreSets = append(tenantStoreSets, s)
+	return s
+}
+
+// get returns the store of the tenant in ctx, or ErrTenantNotFound when the
+// tenant does not exist, for example because it was deleted while the
+// request was running.
+func (s *tenantStores[T]) get(ctx context.Context) (*T, error) {
+	store, exists := s.lookup(reqctx.Tenant(ctx))
+	if !exists {
+		return nil, ErrTenantNotFound
+	}
+	return store, nil
+}
+
+func (s *tenantStores[T]) lookup(tenantID string) (*T, bool) {
+	store, exists := s.stores[tenantID]
+	return store, exists
+}
+
+// each calls fn with every tenant's store, in tenant ID order.
+func (s *tenantStores[T]) each(fn func(tenantID string, store *T)) {
+	ids := make([]string, 0, len(s.stores))
+	for id := range s.stores {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fn(id, s.stores[id])
+	}
+}
+
+// reset replaces every tenant's store with an empty one.
+func (s *tenantStores[T]) reset() {
+	for id := range s.stores {
+		s.stores[id] = s.newStore()
+	}
+}
+
+func (s *tenantStores[T]) addTenant(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.stores[tenantID]; !exists {
+		s.stores[tenantID] = s.newStore()
+	}
+}
+
+func (s *tenantStores[T]) removeTenant(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, exists := s.stores[tenantID]
+	if !exists {
+		return
+	}
+	delete(s.stores, tenantID)
+	if s.release != nil {
+		s.release(store)
+	}
+}
+
+// resetTenants drops every store but the default tenant's, which is
+// emptied.
+func (s *tenantStores[T]) resetTenants() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores = map[string]*T{reqctx.DefaultTenant: s.newStore()}
+}
+
+var (
+	tenants = defaultTenants()
+	// tenantTokens maps the SHA-256 of each issued token to its tenant.
+	tenantTokens = make(map[string]string)
+	tenantsMutex = sync.RWMutex{}
+)
+
+func defaultTenants() map[string]models.Tenant {
+	return map[string]models.Tenant{
+		reqctx.DefaultTenant: {ID: reqctx.DefaultTenant, Name: "Default", CreatedAt: time.Now().UTC()},
+	}
+}
+
+// tenantIDs returns every tenant, for background work that spans tenants.
+func tenantIDs() []string {
+	tenantsMutex.RLock()
+	defer tenantsMutex.RUnlock()
+
+	ids := make([]string, 0, len(tenants))
+	for id := range tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func CreateTenant(tenant models.Tenant) (models.Tenant, error) {
+	if err := tenant.Validate(); err != nil {
+		return models.Tenant{}, err
+	}
+
+	if TenantExists(tenant.ID) {
+		return models.Tenant{}, ErrTenantExists
+	}
+	// The stores are added before the tenant becomes visible, so its first
+	// requests find them. Adding a store that exists is a no-op.
+	for _, set := range tenantStoreSets {
+		set.addTenant(tenant.ID)
+	}
+
+	tenantsMutex.Lock()
+	defer tenantsMutex.Unlock()
+
+	if _, exists := tenants[tenant.ID]; exists {
+		return models.Tenant{}, ErrTenantExists
+	}
+	tenant.CreatedAt = time.Now().UTC()
+	tenants[tenant.ID] = tenant
+	return tenant, nil
+}
+
+func ListTenants() []models.Tenant {
+	tenantsMutex.RLock()
+	defer tenantsMutex.RUnlock()
+
+	result := make([]models.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func GetTenant(id string) (models.Tenant, error) {
+	tenantsMutex.RLock()
+	defer tenantsMutex.RUnlock()
+
+	tenant, exists := tenants[id]
+	if !exists {
+		return models.Tenant{}, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func TenantExists(id string) bool {
+	_, err := GetTenant(id)
+	return err == nil
+}
+
+// DeleteTenant removes a tenant with all of its data and tokens. Its running
+// summary jobs are cancelled and its attachment content is deleted.
+func DeleteTenant(id string) error {
+	if id == reqctx.DefaultTenant {
+		return ErrDefaultTenant
+	}
+
+	tenantsMutex.Lock()
+	if _, exists := tenants[id]; !exists {
+		tenantsMutex.Unlock()
+		return ErrTenantNotFound
+	}
+	delete(tenants, id)
+	for hash, tenantID := range tenantTokens {
+		if tenantID == id {
+			delete(tenantTokens, hash)
+		}
+	}
+	tenantsMutex.Unlock()
+
+	// The tenant is gone before its stores are removed, so no request can
+	// recreate them.
+	for _, set := range tenantStoreSets {
+		set.removeTenant(id)
+	}
+	return nil
+}
+
+// IssueTenantToken creates a bearer token that resolves to the tenant. Only
+// its hash is kept, so the token cannot be retrieved again.
+func IssueTenantToken(id string) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(random)
+
+	tenantsMutex.Lock()
+	defer tenantsMutex.Unlock()
+
+	if _, exists := tenants[id]; !exists {
+		return "", ErrTenantNotFound
+	}
+	tenantTokens[hashTenantToken(token)] = id
+	return token, nil
+}
+
+// RevokeTenantTokens invalidates every token issued for the tenant.
+func RevokeTenantTokens(id string) error {
+	tenantsMutex.Lock()
+	defer tenantsMutex.Unlock()
+
+	if _, exists := tenants[id]; !exists {
+		return ErrTenantNotFound
+	}
+	for hash, tenantID := range tenantTokens {
+		if tenantID == id {
+			delete(tenantTokens, hash)
+		}
+	}
+	return nil
+}
+
+// TenantHasTokens reports whether any token is issued for the tenant.
+func TenantHasTokens(id string) bool {
+	tenantsMutex.RLock()
+	defer tenantsMutex.RUnlock()
+
+	for _, tenantID := range tenantTokens {
+		if tenantID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveTenantToken returns the tenant a bearer token was issued for.
+func ResolveTenantToken(token string) (string, error) {
+	tenantsMutex.RLock()
+	defer tenantsMutex.RUnlock()
+
+	id, exists := tenantTokens[hashTenantToken(token)]
+	if !exists {
+		return "", ErrInvalidTenantToken
+	}
+	return id, nil
+}
+
+func hashTenantToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResetTenants removes every tenant but the default one, together with all
+// tenant data.
+func ResetTenants() {
+	tenantsMutex.Lock()
+	tenants = defaultTenants()
+	tenantTokens = make(map[string]string)
+	tenantsMutex.Unlock()
+
+	for _, set := range tenantStoreSets {
+		set.resetTenants()
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"student-api/internal/models"
+	"student-api/internal/reqctx"
+	"testing"
+	"time"
+)
+
+func setupTenantTest(t *testing.T) (context.Context, context.Context) {
+	ResetTenants()
+	for _, id := range []string{"north", "south"} {
+		if _, err := CreateTenant(models.Tenant{ID: id, Name: id + " school"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	return reqctx.WithTenant(context.Background(), "north"), reqctx.WithTenant(context.Background(), "south")
+}
+
+func TestTenantCRUD(t *testing.T) {
+	setupTenantTest(t)
+
+	if _, err := CreateTenant(models.Tenant{ID: "north", Name: "Again"}); err != ErrTenantExists {
+		t.Errorf("Expected ErrTenantExists, got %v", err)
+	}
+	if _, err := CreateTenant(models.Tenant{ID: "North School", Name: "Invalid"}); err == nil {
+		t.Error("Expected validation error for an invalid tenant ID")
+	}
+
+	tenants := ListTenants()
+	if len(tenants) != 3 || tenants[0].ID != reqctx.DefaultTenant || tenants[1].ID != "north" || tenants[2].ID != "south" {
+		t.Fatalf("Expected default, north and south, got %+v", tenants)
+	}
+	if tenant, err := GetTenant("south"); err != nil || tenant.Name != "south school" {
+		t.Errorf("Expected the south tenant, got %+v, %v", tenant, err)
+	}
+
+	if err := DeleteTenant(reqctx.DefaultTenant); err != ErrDefaultTenant {
+		t.Errorf("Expected ErrDefaultTenant, got %v", err)
+	}
+	if err := DeleteTenant("south"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := GetTenant("south"); err != ErrTenantNotFound {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+	if err := DeleteTenant("south"); err != ErrTenantNotFound {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+}
+
+func TestTenantStudentIsolation(t *testing.T) {
+	north, south := setupTenantTest(t)
+
+	alice, _ := CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"})
+	bob, _ := CreateStudent(south, models.Student{Name: "Bob", Age: 21, Email: "bob@south.edu"})
+	if alice.ID != 1 || bob.ID != 1 {
+		t.Fatalf("Expected every tenant to start its own ID sequence, got %d and %d", alice.ID, bob.ID)
+	}
+
+	if student, err := GetStudentByID(north, 1); err != nil || student.Name != "Alice" {
+		t.Errorf("Expected Alice in north, got %+v, %v", student, err)
+	}
+	if student, err := GetStudentByID(south, 1); err != nil || student.Name != "Bob" {
+		t.Errorf("Expected Bob in south, got %+v, %v", student, err)
+	}
+	if students := GetAllStudents(context.Background()); len(students) != 0 {
+		t.Errorf("Expected no students in the default tenant, got %d", len(students))
+	}
+
+	if err := DeleteStudent(north, alice.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := GetStudentByID(south, 1); err != nil {
+		t.Errorf("Expected deleting a north student to leave south untouched, got %v", err)
+	}
+	if history := GetStudentHistory(south, bob); len(history) != 1 || history[0].Tenant != "south" {
+		t.Errorf("Expected only south's audit event, got %+v", history)
+	}
+}
+
+func TestTenantCourseIsolation(t *testing.T) {
+	north, south := setupTenantTest(t)
+
+	CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"})
+	if _, err := CreateCourse(north, models.Course{Code: "CS101", Title: "Programming", Capacity: 10}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := CreateCourse(south, models.Course{Code: "CS101", Title: "Programming", Capacity: 10}); err != nil {
+		t.Errorf("Expected course codes to be unique per tenant only, got %v", err)
+	}
+
+	if _, err := EnrollStudent(south, 1, 1); err != ErrStudentNotFound {
+		t.Errorf("Expected a north student to be invisible to south, got %v", err)
+	}
+	if _, err := EnrollStudent(north, 1, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if roster, _ := GetCourseRoster(south, 1, models.EnrollmentActive); len(roster) != 0 {
+		t.Errorf("Expected an empty south roster, got %d enrollments", len(roster))
+	}
+}
+
+func TestTenantCustomFieldIsolation(t *testing.T) {
+	north, south := setupTenantTest(t)
+
+	if _, err := CreateCustomField(north, models.CustomFieldDefinition{Key: "house", Label: "House", Type: models.CustomFieldString, Required: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := ValidateCustomFields(north, nil); err == nil {
+		t.Error("Expected the required field to be enforced in north")
+	}
+	if err := ValidateCustomFields(south, nil); err != nil {
+		t.Errorf("Expected north's fields not to apply to south, got %v", err)
+	}
+	if fields := ListCustomFields(south); len(fields) != 0 {
+		t.Errorf("Expected no custom fields in south, got %d", len(fields))
+	}
+}
+
+func TestTenantEventIsolation(t *testing.T) {
+	north, south := setupTenantTest(t)
+
+	_, northEvents, unsubscribe := SubscribeEvents(north, 0)
+	defer unsubscribe()
+
+	CreateStudent(south, models.Student{Name: "Bob", Age: 21, Email: "bob@south.edu"})
+	CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"})
+
+	select {
+	case event := <-northEvents:
+		if event.Student.Name != "Alice" || event.ID != 1 {
+			t.Errorf("Expected north's first event for Alice, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for north's event")
+	}
+	select {
+	case event := <-northEvents:
+		t.Errorf("Expected no further events, got %+v", event)
+	default:
+	}
+}
+
+func TestDeleteTenantRemovesData(t *testing.T) {
+	north, _ := setupTenantTest(t)
+
+	CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"})
+	_, events, _ := SubscribeEvents(north, 0)
+
+	if err := DeleteTenant("north"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, open := <-events; open {
+		t.Error("Expected the tenant's subscribers to be closed")
+	}
+
+	if _, err := CreateTenant(models.Tenant{ID: "north", Name: "North again"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if students := GetAllStudents(north); len(students) != 0 {
+		t.Errorf("Expected a recreated tenant to start empty, got %d students", len(students))
+	}
+}
+
+func TestDeletedTenantIsNotRecreated(t *testing.T) {
+	north, _ := setupTenantTest(t)
+
+	if err := DeleteTenant("north"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := CreateStudent(north, models.Student{Name: "Alice", Age: 20, Email: "alice@north.edu"}); err != ErrTenantNotFound {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+	if _, err := SaveSummary(north, models.Summary{StudentID: 1, Text: "Late summary"}); err != ErrTenantNotFound {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+	if students := GetAllStudents(north); len(students) != 0 {
+		t.Errorf("Expected no students, got %d", len(students))
+	}
+
+	mutex.RLock()
+	_, exists := studentStores.lookup("north")
+	mutex.RUnlock()
+	if exists {
+		t.Error("Expected requests of a deleted tenant not to recreate its store")
+	}
+}
+
+func TestTenantTokens(t *testing.T) {
+	setupTenantTest(t)
+
+	token, err := IssueTenantToken("north")
+	if err != nil || len(token) != 64 {
+		t.Fatalf("Expected a 64 character token, got %q, %v", token, err)
+	}
+	if tenant, err := ResolveTenantToken(token); err != nil || tenant != "north" {
+		t.Errorf("Expected the token to resolve to north, got %q, %v", tenant, err)
+	}
+	if _, err := ResolveTenantToken("unknown"); err != ErrInvalidTenantToken {
+		t.Errorf("Expected ErrInvalidTenantToken, got %v", err)
+	}
+	if _, err := IssueTenantToken("missing"); err != ErrTenantNotFound {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+
+	if err := RevokeTenantTokens("north"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := ResolveTenantToken(token); err != ErrInvalidTenantToken {
+		t.Errorf("Expected a revoked token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"student-api/internal/models"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	maxDeliveriesPerWebhook = 100
+	maxDeadLetters          = 100
+)
+
+var (
+	ErrWebhookNotFound        = errors.New("webhook not found")
+	ErrWebhookInternalAddress = errors.New("webhook host resolves to an internal address")
+)
+
+type WebhookSettings struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Timeout        time.Duration
+}
+
+// webhookMutex guards the settings and the webhooks of every tenant.
+// webhookDialControl vets every address a delivery connects to; tests that
+// deliver to a local server replace it before configuring webhooks.
+var (
+	webhookSettings    = WebhookSettings{MaxAttempts: 5, InitialBackoff: time.Second, Timeout: 10 * time.Second}
+	webhookDialControl = rejectInternalAddress
+	webhookClient      = newWebhookClient(10 * time.Second)
+	webhookMutex       = sync.RWMutex{}
+)
+
+// webhookStore holds one tenant's webhooks with their recent deliveries and
+// the events that could not be delivered.
+type webhookStore struct {
+	webhooks          map[int]models.Webhook
+	nextWebhookID     int
+	webhookDeliveries map[int][]models.WebhookDelivery
+	nextDeliveryID    int
+	deadLetters       []models.DeadLetter
+}
+
+var webhookStores = newTenantStores(&webhookMutex, func() *webhookStore {
+	return &webhookStore{
+		webhooks:          make(map[int]models.Webhook),
+		nextWebhookID:     1,
+		webhookDeliveries: make(map[int][]models.WebhookDelivery),
+		nextDeliveryID:    1,
+	}
+}, nil)
+
+func ConfigureWebhooks(settings WebhookSettings) {
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	webhookSettings = settings
+	webhookClient = newWebhookClient(settings.Timeout)
+}
+
+// newWebhookClient returns a client that only connects to public addresses,
+// checked after DNS resolution so a host name cannot point a webhook at the
+// server's own network, and that does not follow redirects, which could do
+// the same. A redirect is recorded as an unsuccessful delivery.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: webhookDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// rejectInternalAddress is the dialer control of webhook deliveries.
+func rejectInternalAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || models.IsInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookInternalAddress, host)
+	}
+	return nil
+}
+
+func RegisterWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return models.Webhook{}, err
+	}
+
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return models.Webhook{}, err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.ID = data.nextWebhookID
+	webhook.CreatedAt = time.Now().UTC()
+	data.nextWebhookID++
+	data.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+// ListWebhooks returns the tenant's webhooks with their secrets redacted.
+func ListWebhooks(ctx context.Context) []models.Webhook {
+	webhookMutex.RLock()
+	defer webhookMutex.RUnlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return []models.Webhook{}
+	}
+
+	result := make([]models.Webhook, 0, len(data.webhooks))
+	for _, webhook := range data.webhooks {
+		webhook.Secret = ""
+		result = append(result, webhook)
+	}
+	return result
+}
+
+// GetWebhook returns a registered webhook with its secret redacted.
+func GetWebhook(ctx context.Context, id int) (models.Webhook, error) {
+	webhookMutex.RLock()
+	defer webhookMutex.RUnlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook, exists := data.webhooks[id]
+	if !exists {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func DeleteWebhook(ctx context.Context, id int) error {
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := data.webhooks[id]; !exists {
+		return ErrWebhookNotFound
+	}
+	delete(data.webhooks, id)
+	delete(data.webhookDeliveries, id)
+	return nil
+}
+
+func GetWebhookDeliveries(ctx context.Context, id int) ([]models.WebhookDelivery, error) {
+	webhookMutex.RLock()
+	defer webhookMutex.RUnlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := data.webhooks[id]; !exists {
+		return nil, ErrWebhookNotFound
+	}
+	return append([]models.WebhookDelivery{}, data.webhookDeliveries[id]...), nil
+}
+
+func GetDeadLetters(ctx context.Context) []models.DeadLetter {
+	webhookMutex.RLock()
+	defer webhookMutex.RUnlock()
+	data, err := webhookStores.get(ctx)
+	if err != nil {
+		return []models.DeadLetter{}
+	}
+	return append([]models.DeadLetter{}, data.deadLetters...)
+}
+
+// StartWebhookDispatcher delivers student events to the matching webhooks of
+// their tenant until ctx is cancelled.
+func StartWebhookDispatcher(ctx context.Context) {
+	go consumeEvents(ctx, func(event models.StudentEvent) {
+		dispatchEvent(ctx, event)
+	})
+}
+
+func dispatchEvent(ctx context.Context, event models.StudentEvent) {
+	webhookMutex.RLock()
+	defer webhookMutex.RUnlock()
+	data, exists := webhookStores.lookup(event.Tenant)
+	if !exists {
+		return
+	}
+
+	for _, webhook := range data.webhooks {
+		if webhook.Matches(event.Type) {
+			go deliverWebhook(ctx, data, webhook, event)
+		}
+	}
+}
+
+func deliverWebhook(ctx context.Context, data *webhookStore, webhook models.Webhook, event models.StudentEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	webhookMutex.RLock()
+	settings := webhookSettings
+	client := webhookClient
+	webhookMutex.RUnlock()
+
+	backoff := settings.InitialBackoff
+	var lastErr string
+	for attempt := 1; attempt <= settings.MaxAttempts; attempt++ {
+		statusCode, err := sendWebhook(ctx, client, webhook, event, payload)
+		if err == nil {
+			recordDelivery(data, webhook.ID, event, attempt, statusCode, "")
+			return
+		}
+		lastErr = err.Error()
+		recordDelivery(data, webhook.ID, event, attempt, statusCode, lastErr)
+
+		if attempt == settings.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	deadLetters := append(data.deadLetters, models.DeadLetter{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		Event:     event,
+		Attempts:  settings.MaxAttempts,
+		LastError: lastErr,
+		FailedAt:  time.Now().UTC(),
+	})
+	if len(deadLetters) > maxDeadLetters {
+		deadLetters = deadLetters[len(deadLetters)-maxDeadLetters:]
+	}
+	data.deadLetters = deadLetters
+}
+
+func sendWebhook(ctx context.Context, client *http.Client, webhook models.Webhook, event models.StudentEvent, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(event.ID, 10))
+	timestamp := time.Now().Unix()
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value receivers use to
+// verify a payload: "sha256=" followed by the hex HMAC-SHA256 of the Unix
+// timestamp sent in WebhookTimestampHeader, a dot and the body. Signing the
+// timestamp lets receivers reject replayed deliveries.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func recordDelivery(data *webhookStore, webhookID int, event models.StudentEvent, attempt, statusCode int, errMessage string) {
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+
+	if _, exists := data.webhooks[webhookID]; !exists {
+		return
+	}
+
+	delivery := models.WebhookDelivery{
+		ID:         data.nextDeliveryID,
+		WebhookID:  webhookID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		Error:      errMessage,
+		Success:    errMessage == "",
+		Timestamp:  time.Now().UTC(),
+	}
+	data.nextDeliveryID++
+
+	deliveries := append(data.webhookDeliveries[webhookID], delivery)
+	if len(deliveries) > maxDeliveriesPerWebhook {
+		deliveries = deliveries[len(deliveries)-maxDeliveriesPerWebhook:]
+	}
+	data.webhookDeliveries[webhookID] = deliveries
+}
+
+func ResetWebhooks() {
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	webhookStores.reset()
+}
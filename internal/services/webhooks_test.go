@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"student-api/internal/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setupWebhookTest lets deliveries reach test servers on the loopback
+// address until the test ends.
+func setupWebhookTest(t *testing.T) {
+	ResetStudents()
+	ResetEvents()
+	ResetWebhooks()
+	webhookDialControl = nil
+	ConfigureWebhooks(WebhookSettings{MaxAttempts: 3, InitialBackoff: time.Millisecond, Timeout: time.Second})
+	t.Cleanup(func() {
+		webhookDialControl = rejectInternalAddress
+		ConfigureWebhooks(WebhookSettings{MaxAttempts: 5, InitialBackoff: time.Second, Timeout: 10 * time.Second})
+	})
+}
+
+// registerLocalWebhook stores webhook directly so it can point to a test
+// server on the loopback address, which RegisterWebhook rejects.
+func registerLocalWebhook(t *testing.T, webhook models.Webhook) models.Webhook {
+	t.Helper()
+	webhookMutex.Lock()
+	defer webhookMutex.Unlock()
+	data, err := webhookStores.get(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	webhook.ID = data.nextWebhookID
+	data.nextWebhookID++
+	data.webhooks[webhook.ID] = webhook
+	return webhook
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	setupWebhookTest(t)
+
+	webhook, err := RegisterWebhook(context.Background(), models.Webhook{URL: "https://lms.example.com/hooks"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if webhook.ID != 1 || webhook.Secret == "" {
+		t.Errorf("Expected ID 1 and a generated secret, got %+v", webhook)
+	}
+
+	listed := ListWebhooks(context.Background())
+	if len(listed) != 1 || listed[0].Secret != "" {
+		t.Errorf("Expected one webhook with a redacted secret, got %+v", listed)
+	}
+
+	if _, err := RegisterWebhook(context.Background(), models.Webhook{URL: "not a url"}); err == nil {
+		t.Error("Expected error for invalid URL")
+	}
+
+	if err := DeleteWebhook(context.Background(), webhook.ID); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := DeleteWebhook(context.Background(), webhook.ID); err != ErrWebhookNotFound {
+		t.Errorf("Expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestWebhookDeliverySignedWithRetry(t *testing.T) {
+	setupWebhookTest(t)
+
+	var mu sync.Mutex
+	calls := 0
+	var signature, timestamp, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		payload, _ := io.ReadAll(r.Body)
+		body = string(payload)
+		signature = r.Header.Get(WebhookSignatureHeader)
+		timestamp = r.Header.Get(WebhookTimestampHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := registerLocalWebhook(t, models.Webhook{
+		URL:    server.URL,
+		Events: []models.StudentEventType{models.StudentCreated},
+		Secret: "s3cret",
+	})
+
+	startEventConsumer(t, StartWebhookDispatcher)
+
+	student, _ := CreateStudent(context.Background(), models.Student{Name: "John Doe", Age: 20, Email: "john@example.com"})
+	UpdateStudent(context.Background(), student.ID, models.Student{Name: "John Doe", Age: 21, Email: "john@example.com"})
+
+	waitFor(t, func() bool {
+		deliveries, _ := GetWebhookDeliveries(context.Background(), webhook.ID)
+		return len(deliveries) == 2
+	})
+
+	deliveries, _ := GetWebhookDeliveries(context.Background(), webhook.ID)
+	if deliveries[0].Success || deliveries[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected first attempt to fail with 503, got %+v", deliveries[0])
+	}
+	if !deliveries[1].Success || deliveries[1].Attempt != 2 {
+		t.Errorf("Expected second attempt to succeed, got %+v", deliveries[1])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Errorf("Expected a current timestamp header, got %q", timestamp)
+	}
+	if signature != SignWebhookPayload("s3cret", sent, []byte(body)) {
+		t.Errorf("Expected valid HMAC signature, got %s", signature)
+	}
+	if calls != 2 {
+		t.Errorf("Expected only the subscribed created event to be delivered, got %d calls", calls)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	setupWebhookTest(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := registerLocalWebhook(t, models.Webhook{URL: server.URL})
+	event := models.StudentEvent{ID: 7, Type: models.StudentDeleted, StudentID: 1}
+
+	data, _ := webhookStores.get(context.Background())
+	deliverWebhook(context.Background(), data, webhook, event)
+
+	deliveries, _ := GetWebhookDeliveries(context.Background(), webhook.ID)
+	if len(deliveries) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(deliveries))
+	}
+
+	letters := GetDeadLetters(context.Background())
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Event.ID != 7 || letters[0].Attempts != 3 || letters[0].LastError == "" {
+		t.Errorf("Unexpected dead letter: %+v", letters[0])
+	}
+}
+
+func TestWebhookDeadLettersCapped(t *testing.T) {
+	setupWebhookTest(t)
+	ConfigureWebhooks(WebhookSettings{MaxAttempts: 1, InitialBackoff: time.Millisecond, Timeout: time.Second})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := registerLocalWebhook(t, models.Webhook{URL: server.URL})
+	data, _ := webhookStores.get(context.Background())
+	for i := 0; i < maxDeadLetters; i++ {
+		data.deadLetters = append(data.deadLetters, models.DeadLetter{WebhookID: webhook.ID, Event: models.StudentEvent{ID: int64(i + 1)}})
+	}
+
+	deliverWebhook(context.Background(), data, webhook, models.StudentEvent{ID: 500, Type: models.StudentDeleted})
+
+	letters := GetDeadLetters(context.Background())
+	if len(letters) != maxDeadLetters {
+		t.Fatalf("Expected %d dead letters, got %d", maxDeadLetters, len(letters))
+	}
+	if letters[0].Event.ID != 2 || letters[len(letters)-1].Event.ID != 500 {
+		t.Errorf("Expected the oldest dead letter to be dropped, got %d to %d", letters[0].Event.ID, letters[len(letters)-1].Event.ID)
+	}
+}
+
+func TestWebhookDeliveryRejectsInternalAddress(t *testing.T) {
+	setupWebhookTest(t)
+	webhookDialControl = rejectInternalAddress
+	ConfigureWebhooks(WebhookSettings{MaxAttempts: 1, InitialBackoff: time.Millisecond, Timeout: time.Second})
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Skipping Validate stands in for a host name that resolves to the
+	// loopback address, which only the dialer can see.
+	webhook := registerLocalWebhook(t, models.Webhook{URL: server.URL})
+	data, _ := webhookStores.get(context.Background())
+
+	deliverWebhook(context.Background(), data, webhook, models.StudentEvent{ID: 1, Type: models.StudentCreated})
+
+	if called {
+		t.Error("Expected the delivery not to reach the internal address")
+	}
+	letters := GetDeadLetters(context.Background())
+	if len(letters) != 1 || !strings.Contains(letters[0].LastError, ErrWebhookInternalAddress.Error()) {
+		t.Errorf("Expected a dead letter for the internal address, got %+v", letters)
+	}
+}
+
+func TestWebhookDeliveryDoesNotFollowRedirects(t *testing.T) {
+	setupWebhookTest(t)
+	ConfigureWebhooks(WebhookSettings{MaxAttempts: 1, InitialBackoff: time.Millisecond, Timeout: time.Second})
+
+	called := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	webhook := registerLocalWebhook(t, models.Webhook{URL: server.URL})
+	data, _ := webhookStores.get(context.Background())
+	deliverWebhook(context.Background(), data, webhook, models.StudentEvent{ID: 1, Type: models.StudentCreated})
+
+	if called {
+		t.Error("Expected the redirect not to be followed")
+	}
+	deliveries, _ := GetWebhookDeliveries(context.Background(), webhook.ID)
+	if len(deliveries) != 1 || deliveries[0].Success || deliveries[0].StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("Expected a failed delivery with the redirect status, got %+v", deliveries)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("key", 1700000000, []byte("The quick brown fox jumps over the lazy dog"))
+	expected := "sha256=2f658d6aef4f246e91cd741bbcded7479e9605f9d41c9e248122a117e0e1765b"
+	if signature != expected {
+		t.Errorf("Expected %s, got %s", expected, signature)
+	}
+}